@@ -1,22 +1,42 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/multitracer"
 	_ "github.com/lib/pq"
 	_ "github.com/mariosker/products_rest_api/docs"
+	"github.com/mariosker/products_rest_api/internal/auth"
 	"github.com/mariosker/products_rest_api/internal/config"
 	"github.com/mariosker/products_rest_api/internal/database"
 	"github.com/mariosker/products_rest_api/internal/handlers"
+	"github.com/mariosker/products_rest_api/internal/health"
+	"github.com/mariosker/products_rest_api/internal/metrics"
+	"github.com/mariosker/products_rest_api/internal/middleware"
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/mariosker/products_rest_api/internal/ratelimit"
 	"github.com/mariosker/products_rest_api/internal/repository"
 	"github.com/mariosker/products_rest_api/internal/routes"
+	"github.com/mariosker/products_rest_api/internal/storage"
+	"github.com/mariosker/products_rest_api/internal/tax"
+	"github.com/mariosker/products_rest_api/internal/thumbnails"
+	"github.com/mariosker/products_rest_api/internal/tracing"
+	"github.com/mariosker/products_rest_api/internal/utils"
+	"github.com/mariosker/products_rest_api/internal/workers"
 
 	swaggerfiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
@@ -33,51 +53,353 @@ func main() {
 		log.Fatal("Failed to load configuration:", err)
 	}
 
+	// Metrics and tracing are set up first, so that they see the queries made while setting up too
+	var registry *metrics.Registry
+	var queryTracers []pgx.QueryTracer
+	if cfg.MetricsEnabled {
+		registry = metrics.NewRegistry()
+		queryTracers = append(queryTracers, metrics.NewQueryTracer(registry))
+	}
+	stopTracing := func(context.Context) error { return nil }
+	if cfg.TracingExporter != "" {
+		stopTracing, err = tracing.Setup(context.Background(), tracing.Options{
+			Exporter:    cfg.TracingExporter,
+			Endpoint:    cfg.TracingEndpoint,
+			Insecure:    cfg.TracingInsecure,
+			ServiceName: cfg.TracingServiceName,
+		})
+		if err != nil {
+			log.Fatal("Failed to set up tracing:", err)
+		}
+		queryTracers = append(queryTracers, tracing.NewQueryTracer())
+	}
+	var queryTracer pgx.QueryTracer
+	switch len(queryTracers) {
+	case 0:
+	case 1:
+		queryTracer = queryTracers[0]
+	default:
+		queryTracer = multitracer.New(queryTracers...)
+	}
+
 	// Initialize the database connection
-	err = database.InitDB(cfg.DBURL)
+	err = database.InitDB(cfg.DBURL, queryTracer)
 	if err != nil {
 		log.Fatal("Failed to connect to database:", err)
 	}
-	defer database.CloseDB()
 
-	if err := runMigrations(cfg.DBURL); err != nil {
+	schemaVersion, err := runMigrations(cfg.DBURL)
+	if err != nil {
 		log.Fatalf("Migration failed: %v", err)
 	}
 
 	// Create repository and handlers
 	productRepo := repository.NewPostgresProductRepository(database.GetDB())
-	productHandler := handlers.NewProductHandler(productRepo)
+	variantRepo := repository.NewPostgresVariantRepository(database.GetDB())
+	translationRepo := repository.NewPostgresTranslationRepository(database.GetDB())
+	taxRepo := repository.NewPostgresTaxRepository(database.GetDB())
+	taxRounding, err := tax.ParseRoundingMode(cfg.TaxRoundingMode)
+	if err != nil {
+		log.Fatal("Invalid tax rounding mode:", err)
+	}
+	bundleRepo := repository.NewPostgresBundleRepository(database.GetDB())
+	relationRepo := repository.NewPostgresRelationRepository(database.GetDB())
+	productOpts := []handlers.ProductHandlerOption{
+		handlers.WithVariantRepository(variantRepo),
+		handlers.WithTranslations(translationRepo, cfg.DefaultLocale),
+		handlers.WithTaxes(taxRepo, taxRounding),
+		handlers.WithRelations(relationRepo),
+	}
+	switch cfg.BundleDeletePolicy {
+	case models.BundleDeleteRestrict:
+	case models.BundleDeleteCascade:
+		productOpts = append(productOpts, handlers.WithBundleCascade(bundleRepo))
+	default:
+		log.Fatalf("Invalid bundle delete policy: %q", cfg.BundleDeletePolicy)
+	}
+	productHandler := handlers.NewProductHandler(productRepo, productOpts...)
+	bundleHandler := handlers.NewBundleHandler(bundleRepo)
+	relationHandler := handlers.NewRelationHandler(relationRepo)
+	apiKeyRepo := repository.NewPostgresAPIKeyRepository(database.GetDB())
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyRepo)
+	reviewHandler := handlers.NewReviewHandler(repository.NewPostgresReviewRepository(database.GetDB()), cfg.ReviewAutoApprove)
+	taxHandler := handlers.NewTaxHandler(taxRepo)
+	translationHandler := handlers.NewTranslationHandler(translationRepo, cfg.DefaultLocale)
+	variantHandler := handlers.NewVariantHandler(variantRepo)
+	reservationRepo := repository.NewPostgresReservationRepository(database.GetDB())
+	reservationHandler := handlers.NewReservationHandler(reservationRepo, cfg.ReservationTTL)
+	priceScheduleHandler := handlers.NewPriceScheduleHandler(repository.NewPostgresPriceScheduleRepository(database.GetDB()))
+	discountRepo := repository.NewPostgresDiscountRepository(database.GetDB())
+	discountHandler := handlers.NewDiscountHandler(discountRepo)
+	pricingHandler := handlers.NewPricingHandler(productRepo, discountRepo)
+	attributeHandler := handlers.NewAttributeHandler(repository.NewPostgresAttributeRepository(database.GetDB()))
+	imageStorage, err := newImageStorage(cfg)
+	if err != nil {
+		log.Fatal("Failed to set up image storage:", err)
+	}
+	thumbnailCache, err := thumbnails.NewCache(cfg.ThumbnailCacheDir)
+	if err != nil {
+		log.Fatal("Failed to set up thumbnail cache:", err)
+	}
+	imageRepo := repository.NewPostgresImageRepository(database.GetDB())
+	imageHandler := handlers.NewImageHandler(imageRepo, imageStorage, cfg.ImageMaxBytes,
+		handlers.WithThumbnails(thumbnailCache, cfg.ThumbnailSizes))
+
+	// Start background workers
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	var running sync.WaitGroup
+	for _, worker := range []interface{ Run(context.Context) }{
+		workers.NewReservationSweeper(reservationRepo, cfg.ReservationSweepInterval),
+		workers.NewPublishScheduler(productRepo, cfg.PublishSchedulerInterval),
+		workers.NewImageCleaner(imageRepo, imageStorage, cfg.ImageCleanupInterval),
+	} {
+		running.Add(1)
+		go func() {
+			defer running.Done()
+			worker.Run(workerCtx)
+		}()
+	}
 
 	// Set up router and routes
+	utils.RegisterValidators()
 	r := gin.Default()
 	r.Use(gin.Logger())
 	r.Use(gin.Recovery())
+	if err := r.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Fatal("Invalid trusted proxies:", err)
+	}
+	if registry != nil {
+		metrics.RegisterPool(registry, database.GetDB())
+		metrics.RegisterCatalog(registry, database.GetDB())
+		r.Use(metrics.HTTP(registry))
+	}
+	// Probes are registered before the middleware below, so they are never authenticated,
+	// tenant-scoped or rate limited.
+	checker := health.NewChecker(cfg.HealthCheckTimeout)
+	checker.Add("database", health.Database(database.GetDB()))
+	checker.Add("migrations", health.Migrations(database.GetDB(), schemaVersion))
+	r.GET("/livez", checker.Live)
+	r.GET("/readyz", checker.Ready)
+	if cfg.TracingExporter != "" {
+		r.Use(tracing.Middleware())
+	}
+	r.Use(middleware.SecurityHeaders(middleware.SecurityHeadersOptions{
+		HSTSMaxAge:            cfg.HSTSMaxAge,
+		HSTSIncludeSubdomains: cfg.HSTSIncludeSubdomains,
+		FrameOptions:          cfg.FrameOptions,
+	}))
+	if len(cfg.CORSAllowedOrigins) > 0 {
+		// CORS runs before authentication, since browsers send preflight requests without credentials.
+		r.Use(middleware.CORS(middleware.CORSOptions{
+			AllowedOrigins:   cfg.CORSAllowedOrigins,
+			AllowedMethods:   cfg.CORSAllowedMethods,
+			AllowedHeaders:   cfg.CORSAllowedHeaders,
+			ExposedHeaders:   cfg.CORSExposedHeaders,
+			AllowCredentials: cfg.CORSAllowCredentials,
+			MaxAge:           cfg.CORSMaxAge,
+		}))
+	}
+	var rateLimitStore ratelimit.Store
+	if cfg.RateLimitEnabled {
+		if rateLimitStore, err = newRateLimitStore(cfg); err != nil {
+			log.Fatal("Failed to set up rate limiting:", err)
+		}
+	}
+	if cfg.AuthEnabled {
+		// Without an admin key, API keys or bearer tokens every request would be rejected.
+		if cfg.AuthAdminKey == "" && cfg.JWTJWKS == "" {
+			hasKeys, err := apiKeyRepo.HasActiveAPIKeys(context.Background())
+			if err != nil {
+				log.Fatal("Failed to check for API keys:", err)
+			}
+			if !hasKeys {
+				log.Fatal("Authentication is enabled but there is no way to authenticate: set AuthAdminKey to issue the first API keys, set JWTJWKS to accept bearer tokens, or set AuthEnabled=false")
+			}
+		}
+		authOptions := auth.Options{PublicPaths: cfg.AuthPublicPaths, AdminKey: cfg.AuthAdminKey, Policy: auth.DefaultPolicy}
+		if cfg.RBACPolicyFile != "" {
+			if authOptions.Policy, err = auth.LoadPolicy(cfg.RBACPolicyFile); err != nil {
+				log.Fatal("Failed to load RBAC policy:", err)
+			}
+		}
+		if cfg.JWTJWKS != "" {
+			if cfg.JWTIssuer == "" || cfg.JWTAudience == "" {
+				log.Fatal("JWTIssuer and JWTAudience must be set to accept bearer tokens")
+			}
+			authOptions.Tokens = auth.NewJWTVerifier(auth.NewJWKS(cfg.JWTJWKS, cfg.JWTJWKSCacheTTL), cfg.JWTIssuer, cfg.JWTAudience, cfg.JWTLeeway)
+		}
+		// Failed authentications are limited by IP address before the credentials are checked.
+		if rateLimitStore != nil && cfg.RateLimitAuthFailures != "" {
+			limit, err := ratelimit.ParseLimit(cfg.RateLimitAuthFailures)
+			if err != nil {
+				log.Fatal("Failed to set up rate limiting:", err)
+			}
+			r.Use(ratelimit.AuthFailures(rateLimitStore, limit))
+		}
+		r.Use(auth.Authenticate(apiKeyRepo, authOptions))
+	}
+	r.Use(auth.ResolveTenant(cfg.DefaultTenant))
+	if rateLimitStore != nil {
+		rateLimiter, err := newRateLimiter(rateLimitStore, cfg)
+		if err != nil {
+			log.Fatal("Failed to set up rate limiting:", err)
+		}
+		r.Use(rateLimiter)
+	}
+	// Image uploads are limited to ImageMaxBytes by the image handler instead.
+	r.Use(middleware.BodyLimit(cfg.MaxBodyBytes, "/products/:id/images"))
+	r.Use(handlers.HideUnpublishedProducts(productRepo))
+	if registry != nil && cfg.MetricsAddr == "" {
+		r.GET("/metrics", registry.GinHandler())
+	}
+	// Unlike /readyz, /health is an ordinary route, so its report can be protected by leaving it out
+	// of AuthPublicPaths.
+	r.GET("/health", checker.Ready)
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerfiles.Handler))
-	r.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"status": "OK"})
-	})
 
 	routes.SetupRoutes(r, productHandler)
+	routes.SetupReservationRoutes(r, reservationHandler)
+	routes.SetupVariantRoutes(r, variantHandler)
+	routes.SetupAttributeRoutes(r, attributeHandler)
+	routes.SetupImageRoutes(r, imageHandler)
+	routes.SetupTranslationRoutes(r, translationHandler)
+	routes.SetupPriceScheduleRoutes(r, priceScheduleHandler)
+	routes.SetupDiscountRoutes(r, discountHandler)
+	routes.SetupPricingRoutes(r, pricingHandler)
+	routes.SetupTaxRoutes(r, taxHandler)
+	routes.SetupBundleRoutes(r, bundleHandler)
+	routes.SetupRelationRoutes(r, relationHandler)
+	routes.SetupReviewRoutes(r, reviewHandler)
+	routes.SetupAPIKeyRoutes(r, apiKeyHandler)
+
+	server := &http.Server{
+		Addr:              cfg.ServerHost + ":" + cfg.ServerPort,
+		Handler:           r,
+		ReadTimeout:       cfg.ReadTimeout,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+	}
+	signals, stopSignals := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+
+	serverErr := make(chan error, 2)
+	go func() {
+		log.Printf("Running server at: %s", server.Addr)
+		serverErr <- server.ListenAndServe()
+	}()
+	var metricsServer *http.Server
+	if registry != nil && cfg.MetricsAddr != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", registry.Handler())
+		metricsServer = &http.Server{Addr: cfg.MetricsAddr, Handler: mux, ReadHeaderTimeout: cfg.ReadHeaderTimeout}
+		go func() {
+			log.Printf("Serving metrics at: %s", metricsServer.Addr)
+			serverErr <- metricsServer.ListenAndServe()
+		}()
+	}
+
+	exitCode := 0
+	select {
+	case err := <-serverErr:
+		log.Printf("Failed to run server: %v", err)
+		exitCode = 1
+	case <-signals.Done():
+		log.Printf("Shutting down, waiting up to %s for in-flight requests", cfg.ShutdownDrainDelay+cfg.ShutdownTimeout)
+	}
+
+	// A second signal kills the process straight away instead of waiting for the drain.
+	stopSignals()
+
+	if exitCode == 0 {
+		checker.Drain()
+		time.Sleep(cfg.ShutdownDrainDelay)
+	}
+
+	// Stop accepting requests and let in-flight ones finish, then stop the workers, and only
+	// then close the database both depend on.
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	if err := server.Shutdown(ctx); err != nil {
+		log.Printf("Failed to drain connections, closing them: %v", err)
+		server.Close()
+		exitCode = 1
+	}
+	cancel()
+	if metricsServer != nil {
+		metricsServer.Close()
+	}
+	stopWorkers()
+	running.Wait()
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	if err := stopTracing(ctx); err != nil {
+		log.Printf("Failed to flush traces: %v", err)
+	}
+	cancel()
+	database.CloseDB()
+	log.Println("Server stopped")
+	os.Exit(exitCode)
+}
 
-	serverAddr := cfg.ServerHost + ":" + cfg.ServerPort
+// newImageStorage creates the storage backend selected by cfg.StorageBackend.
+func newImageStorage(cfg *config.Config) (storage.Storage, error) {
+	switch cfg.StorageBackend {
+	case "local":
+		return storage.NewLocalStorage(cfg.StoragePath)
+	case "s3":
+		return storage.NewS3Storage(storage.S3Config{
+			Endpoint:  cfg.S3Endpoint,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+			Region:    cfg.S3Region,
+			UseSSL:    cfg.S3UseSSL,
+		})
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", cfg.StorageBackend)
+	}
+}
 
-	if err := r.Run(serverAddr); err != nil {
-		log.Fatal("Failed to run server:", err)
+func newRateLimitStore(cfg *config.Config) (ratelimit.Store, error) {
+	switch cfg.RateLimitStore {
+	case "memory":
+		return ratelimit.NewMemoryStore(), nil
+	case "postgres":
+		return ratelimit.NewPostgresStore(database.GetDB()), nil
+	default:
+		return nil, fmt.Errorf("unknown rate limit store %q", cfg.RateLimitStore)
 	}
-	log.Printf("Running server at: %s", serverAddr)
 }
 
-func runMigrations(dsn string) error {
+func newRateLimiter(store ratelimit.Store, cfg *config.Config) (gin.HandlerFunc, error) {
+	limits := make(map[string]ratelimit.Limit)
+	for group, value := range map[string]string{
+		ratelimit.GroupRead:  cfg.RateLimitRead,
+		ratelimit.GroupWrite: cfg.RateLimitWrite,
+		ratelimit.GroupAdmin: cfg.RateLimitAdmin,
+	} {
+		if value == "" {
+			continue
+		}
+		limit, err := ratelimit.ParseLimit(value)
+		if err != nil {
+			return nil, err
+		}
+		limits[group] = limit
+	}
+	return ratelimit.Middleware(store, limits), nil
+}
+
+// runMigrations applies every pending migration and returns the schema version it leaves the database at.
+func runMigrations(dsn string) (uint, error) {
 	// Create a temporary *sql.DB connection for migrations
 	sqlDB, err := sql.Open("postgres", dsn)
 	if err != nil {
-		return fmt.Errorf("failed to connect for migrations: %w", err)
+		return 0, fmt.Errorf("failed to connect for migrations: %w", err)
 	}
 	defer sqlDB.Close()
 
 	driver, err := postgres.WithInstance(sqlDB, &postgres.Config{})
 	if err != nil {
-		return fmt.Errorf("migration driver error: %w", err)
+		return 0, fmt.Errorf("migration driver error: %w", err)
 	}
 
 	m, err := migrate.NewWithDatabaseInstance(
@@ -85,10 +407,14 @@ func runMigrations(dsn string) error {
 		"postgres", driver,
 	)
 	if err != nil {
-		return fmt.Errorf("migration setup error: %w", err)
+		return 0, fmt.Errorf("migration setup error: %w", err)
 	}
 	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
-		return fmt.Errorf("migration up error: %w", err)
+		return 0, fmt.Errorf("migration up error: %w", err)
+	}
+	version, _, err := m.Version()
+	if err != nil {
+		return 0, fmt.Errorf("migration version error: %w", err)
 	}
-	return nil
+	return version, nil
 }
@@ -0,0 +1,98 @@
+// Package attributes validates typed product attributes against the definitions of their category.
+package attributes
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/mariosker/products_rest_api/internal/models"
+)
+
+// ValidationError lists every problem found with a product's attributes.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid attributes: " + strings.Join(e.Problems, "; ")
+}
+
+// Validate checks attrs against the attribute definitions of a category.
+// A category without definitions accepts any attributes. Otherwise every attribute
+// must be defined, have the defined type and satisfy its constraints, and every
+// required attribute must be present.
+func Validate(defs []*models.AttributeDefinition, attrs map[string]any) error {
+	if len(defs) == 0 {
+		return nil
+	}
+
+	byName := make(map[string]*models.AttributeDefinition, len(defs))
+	for _, def := range defs {
+		byName[def.Name] = def
+	}
+
+	var problems []string
+	for _, name := range sortedKeys(attrs) {
+		def, ok := byName[name]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s is not defined for category %s", name, defs[0].Category))
+			continue
+		}
+		if problem := check(def, attrs[name]); problem != "" {
+			problems = append(problems, name+" "+problem)
+		}
+	}
+	for _, def := range defs {
+		if _, ok := attrs[def.Name]; def.Required && !ok {
+			problems = append(problems, def.Name+" is required")
+		}
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// check returns a description of why value does not satisfy def, or "" if it does.
+func check(def *models.AttributeDefinition, value any) string {
+	switch def.Type {
+	case models.AttributeTypeString:
+		s, ok := value.(string)
+		if !ok {
+			return "must be a string"
+		}
+		if len(def.AllowedValues) > 0 && !slices.Contains(def.AllowedValues, s) {
+			return "must be one of " + strings.Join(def.AllowedValues, ", ")
+		}
+	case models.AttributeTypeNumber:
+		n, ok := value.(float64)
+		if !ok {
+			return "must be a number"
+		}
+		if def.Min != nil && n < *def.Min {
+			return fmt.Sprintf("must be at least %g", *def.Min)
+		}
+		if def.Max != nil && n > *def.Max {
+			return fmt.Sprintf("must be at most %g", *def.Max)
+		}
+	case models.AttributeTypeBoolean:
+		if _, ok := value.(bool); !ok {
+			return "must be a boolean"
+		}
+	default:
+		return "has unknown type " + def.Type
+	}
+	return ""
+}
+
+func sortedKeys(attrs map[string]any) []string {
+	keys := make([]string, 0, len(attrs))
+	for key := range attrs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,78 @@
+package attributes
+
+import (
+	"testing"
+
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func ptr(f float64) *float64 { return &f }
+
+func TestValidate(t *testing.T) {
+	defs := []*models.AttributeDefinition{
+		{Category: "apparel", Name: "material", Type: models.AttributeTypeString, Required: true, AllowedValues: []string{"cotton", "wool"}},
+		{Category: "apparel", Name: "weight", Type: models.AttributeTypeNumber, Min: ptr(0), Max: ptr(10)},
+		{Category: "apparel", Name: "organic", Type: models.AttributeTypeBoolean},
+	}
+
+	tests := []struct {
+		name     string
+		defs     []*models.AttributeDefinition
+		attrs    map[string]any
+		problems []string
+	}{
+		{
+			name:  "No Definitions Accepts Anything",
+			attrs: map[string]any{"anything": []any{1, "two"}},
+		},
+		{
+			name:  "Valid",
+			defs:  defs,
+			attrs: map[string]any{"material": "cotton", "weight": 1.5, "organic": true},
+		},
+		{
+			name:     "Missing Required",
+			defs:     defs,
+			attrs:    map[string]any{"weight": 1.5},
+			problems: []string{"material is required"},
+		},
+		{
+			name:     "Not In Allowed Values",
+			defs:     defs,
+			attrs:    map[string]any{"material": "silk"},
+			problems: []string{"material must be one of cotton, wool"},
+		},
+		{
+			name:     "Wrong Types",
+			defs:     defs,
+			attrs:    map[string]any{"material": 3.0, "weight": "heavy", "organic": "yes"},
+			problems: []string{"material must be a string", "organic must be a boolean", "weight must be a number"},
+		},
+		{
+			name:     "Out Of Range",
+			defs:     defs,
+			attrs:    map[string]any{"material": "wool", "weight": 12.0},
+			problems: []string{"weight must be at most 10"},
+		},
+		{
+			name:     "Undefined Attribute",
+			defs:     defs,
+			attrs:    map[string]any{"material": "wool", "voltage": 230.0},
+			problems: []string{"voltage is not defined for category apparel"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.defs, tt.attrs)
+			if tt.problems == nil {
+				assert.NoError(t, err)
+				return
+			}
+			if assert.IsType(t, &ValidationError{}, err) {
+				assert.Equal(t, tt.problems, err.(*ValidationError).Problems)
+			}
+		})
+	}
+}
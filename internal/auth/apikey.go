@@ -0,0 +1,31 @@
+// Package auth authenticates API clients and checks what they are allowed to do.
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+)
+
+// apiKeyPrefix marks the keys issued by this API so they are easy to recognise in logs and secret scanners.
+const apiKeyPrefix = "pk_"
+
+// prefixLength is how much of a key is kept in the clear to tell keys apart.
+const prefixLength = len(apiKeyPrefix) + 8
+
+// GenerateAPIKey returns a new random API key and the prefix it is shown by.
+func GenerateAPIKey() (key, prefix string, err error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", err
+	}
+	key = apiKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+	return key, key[:prefixLength], nil
+}
+
+// HashAPIKey returns the hash an API key is stored and looked up by. Keys are long and random,
+// so a fast unsalted hash is enough to keep them from being recovered from the database.
+func HashAPIKey(key string) []byte {
+	sum := sha256.Sum256([]byte(key))
+	return sum[:]
+}
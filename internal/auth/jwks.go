@@ -0,0 +1,227 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// minJWKSRefresh is how often a key set may be reloaded to look for a key it does not know, or
+// after a failed load, so tokens with made-up key IDs or an unreachable JWKS endpoint cannot make
+// the API hammer it.
+const minJWKSRefresh = 30 * time.Second
+
+// JWKS is a JSON Web Key Set loaded from a file or an http(s) URL. Keys are cached for a TTL,
+// and the set is reloaded early when a token names a key it does not know, so signing keys can
+// be rotated without restarting the API.
+type JWKS struct {
+	source     string
+	client     *http.Client
+	ttl        time.Duration
+	minRefresh time.Duration
+	loads      singleflight.Group
+
+	mu       sync.Mutex
+	keys     map[string]crypto.PublicKey
+	loadedAt time.Time
+	// failedAt and loadErr record the last failed load, which is not retried for minRefresh.
+	failedAt time.Time
+	loadErr  error
+}
+
+// NewJWKS creates a key set that is loaded from source on first use and reloaded every ttl.
+func NewJWKS(source string, ttl time.Duration) *JWKS {
+	return &JWKS{
+		source:     source,
+		client:     &http.Client{Timeout: 10 * time.Second},
+		ttl:        ttl,
+		minRefresh: minJWKSRefresh,
+	}
+}
+
+// Key returns the public key with the given key ID. Concurrent requests share a single load of
+// the set, which does not hold up requests for cached keys. When the set cannot be reloaded, the
+// keys loaded before keep being used.
+func (s *JWKS) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	key, ok, load, err := s.cached(kid)
+	if !load {
+		return key, err
+	}
+
+	loaded := s.loads.DoChan("", func() (any, error) {
+		// The load is shared with other requests, so it outlives the cancellation of this one.
+		return nil, s.load(context.WithoutCancel(ctx))
+	})
+	select {
+	case result := <-loaded:
+		err = result.Err
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	if err != nil {
+		if ok {
+			return key, nil
+		}
+		return nil, err
+	}
+
+	s.mu.Lock()
+	key, ok = s.keys[kid]
+	s.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q: %w", kid, ErrInvalidToken)
+	}
+	return key, nil
+}
+
+// cached returns the cached key with the given key ID and whether it is known, and whether the set
+// must be loaded to find or refresh it. When it need not be loaded, err is the result of the lookup.
+func (s *JWKS) cached(kid string) (key crypto.PublicKey, ok, load bool, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, ok = s.keys[kid]
+	age := time.Since(s.loadedAt)
+	switch {
+	case ok && age < s.ttl:
+		return key, true, false, nil
+	case time.Since(s.failedAt) < s.minRefresh:
+		if ok {
+			return key, true, false, nil
+		}
+		if s.keys == nil {
+			return nil, false, false, s.loadErr
+		}
+		return nil, false, false, fmt.Errorf("unknown signing key %q: %w", kid, ErrInvalidToken)
+	case !ok && s.keys != nil && age < s.minRefresh:
+		return nil, false, false, fmt.Errorf("unknown signing key %q: %w", kid, ErrInvalidToken)
+	}
+	return key, ok, true, nil
+}
+
+// load replaces the cached keys with those read from the source, or records that it failed.
+func (s *JWKS) load(ctx context.Context) error {
+	keys, err := s.fetch(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		s.failedAt, s.loadErr = time.Now(), err
+		return err
+	}
+	s.keys, s.loadedAt = keys, time.Now()
+	return nil
+}
+
+// fetch reads and parses the keys of the source.
+func (s *JWKS) fetch(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	data, err := s.read(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load JWKS from %s: %w", s.source, err)
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("failed to parse JWKS from %s: %w", s.source, err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse key %q of JWKS from %s: %w", k.Kid, s.source, err)
+		}
+		if key != nil {
+			keys[k.Kid] = key
+		}
+	}
+	return keys, nil
+}
+
+func (s *JWKS) read(ctx context.Context) ([]byte, error) {
+	if !strings.HasPrefix(s.source, "http://") && !strings.HasPrefix(s.source, "https://") {
+		return os.ReadFile(s.source)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.source, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+}
+
+// jwk is a JSON Web Key as defined in RFC 7517. Only the members of RSA and EC public keys are kept.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// publicKey returns the RSA or ECDSA public key of k, or nil for key types that cannot verify
+// RS256 or ES256 signatures.
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid modulus: %w", err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil || len(e) == 0 || len(e) > 4 {
+			return nil, fmt.Errorf("invalid exponent")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		if k.Crv != "P-256" {
+			return nil, nil
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != 32 {
+			return nil, fmt.Errorf("invalid x coordinate")
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil || len(y) != 32 {
+			return nil, fmt.Errorf("invalid y coordinate")
+		}
+		// Parsing the uncompressed point checks that it lies on the curve.
+		if _, err := ecdh.P256().NewPublicKey(append(append([]byte{4}, x...), y...)); err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	default:
+		return nil, nil
+	}
+}
@@ -0,0 +1,214 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ErrInvalidToken is returned for bearer tokens that are malformed, not signed by a trusted key,
+// expired, or not issued for this API.
+var ErrInvalidToken = errors.New("invalid token")
+
+// KeySource looks up the public keys tokens are signed with by key ID.
+type KeySource interface {
+	Key(ctx context.Context, kid string) (crypto.PublicKey, error)
+}
+
+// Claims are the claims of a verified token.
+type Claims map[string]any
+
+// Subject returns the sub claim, or "" when the token has none.
+func (c Claims) Subject() string {
+	sub, _ := c["sub"].(string)
+	return sub
+}
+
+// Scopes returns the scopes granted by the space-separated scope claim, or by the scp claim
+// some identity providers use instead.
+func (c Claims) Scopes() []string {
+	if scope, ok := c["scope"].(string); ok {
+		return strings.Fields(scope)
+	}
+	switch scp := c["scp"].(type) {
+	case string:
+		return strings.Fields(scp)
+	case []any:
+		scopes := make([]string, 0, len(scp))
+		for _, s := range scp {
+			if s, ok := s.(string); ok {
+				scopes = append(scopes, s)
+			}
+		}
+		return scopes
+	}
+	return nil
+}
+
+// Tenant returns the tenant_id claim, or "" when the token is not bound to a tenant.
+func (c Claims) Tenant() string {
+	tenant, _ := c["tenant_id"].(string)
+	return tenant
+}
+
+// Roles returns the roles granted by the roles claim, as an array or a space-separated string.
+func (c Claims) Roles() []string {
+	switch roles := c["roles"].(type) {
+	case string:
+		return strings.Fields(roles)
+	case []any:
+		names := make([]string, 0, len(roles))
+		for _, role := range roles {
+			if role, ok := role.(string); ok {
+				names = append(names, role)
+			}
+		}
+		return names
+	}
+	return nil
+}
+
+// JWTVerifier verifies RS256 and ES256 signed JSON Web Tokens.
+type JWTVerifier struct {
+	keys     KeySource
+	issuer   string
+	audience string
+	leeway   time.Duration
+	now      func() time.Time
+}
+
+// NewJWTVerifier creates a verifier that accepts tokens signed by keys from keys, issued by issuer
+// for audience. leeway allows for clock skew when checking exp and nbf.
+func NewJWTVerifier(keys KeySource, issuer, audience string, leeway time.Duration) *JWTVerifier {
+	return &JWTVerifier{keys: keys, issuer: issuer, audience: audience, leeway: leeway, now: time.Now}
+}
+
+// Verify checks the signature, issuer, audience and lifetime of token and returns its claims.
+// Errors wrap ErrInvalidToken unless the signing keys could not be loaded.
+func (v *JWTVerifier) Verify(ctx context.Context, token string) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("token is not a JWT: %w", ErrInvalidToken)
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("malformed token header: %w", ErrInvalidToken)
+	}
+	if header.Alg != "RS256" && header.Alg != "ES256" {
+		return nil, fmt.Errorf("unsupported signing algorithm %q: %w", header.Alg, ErrInvalidToken)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("malformed token signature: %w", ErrInvalidToken)
+	}
+
+	key, err := v.keys.Key(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if !verifySignature(header.Alg, key, digest[:], signature) {
+		return nil, fmt.Errorf("token signature does not match: %w", ErrInvalidToken)
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("malformed token claims: %w", ErrInvalidToken)
+	}
+	if err := v.checkClaims(claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// checkClaims checks the registered claims of a token whose signature has been verified.
+func (v *JWTVerifier) checkClaims(claims Claims) error {
+	now := v.now()
+
+	exp, ok := claims["exp"].(json.Number)
+	if !ok {
+		return fmt.Errorf("token has no expiry: %w", ErrInvalidToken)
+	}
+	if expiry, err := numericDate(exp); err != nil || !now.Before(expiry.Add(v.leeway)) {
+		return fmt.Errorf("token has expired: %w", ErrInvalidToken)
+	}
+	if nbf, ok := claims["nbf"].(json.Number); ok {
+		if notBefore, err := numericDate(nbf); err != nil || now.Add(v.leeway).Before(notBefore) {
+			return fmt.Errorf("token is not valid yet: %w", ErrInvalidToken)
+		}
+	}
+
+	if iss, _ := claims["iss"].(string); iss != v.issuer {
+		return fmt.Errorf("token was issued by %q: %w", iss, ErrInvalidToken)
+	}
+	var audiences []string
+	switch aud := claims["aud"].(type) {
+	case string:
+		audiences = []string{aud}
+	case []any:
+		for _, a := range aud {
+			if a, ok := a.(string); ok {
+				audiences = append(audiences, a)
+			}
+		}
+	}
+	if !slices.Contains(audiences, v.audience) {
+		return fmt.Errorf("token is not meant for %q: %w", v.audience, ErrInvalidToken)
+	}
+	return nil
+}
+
+// verifySignature reports whether signature is a valid alg signature of digest by key.
+func verifySignature(alg string, key crypto.PublicKey, digest, signature []byte) bool {
+	switch alg {
+	case "RS256":
+		key, ok := key.(*rsa.PublicKey)
+		return ok && rsa.VerifyPKCS1v15(key, crypto.SHA256, digest, signature) == nil
+	case "ES256":
+		// ES256 signatures are the 32-byte r and s values back to back rather than ASN.1.
+		key, ok := key.(*ecdsa.PublicKey)
+		if !ok || len(signature) != 64 {
+			return false
+		}
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+		return ecdsa.Verify(key, digest, r, s)
+	default:
+		return false
+	}
+}
+
+// decodeSegment decodes a base64url-encoded JSON segment of a token, keeping numbers exact.
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
+
+// numericDate converts a JWT NumericDate, in seconds since the epoch, to a time.
+func numericDate(n json.Number) (time.Time, error) {
+	seconds, err := n.Float64()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(0, 0).Add(time.Duration(seconds * float64(time.Second))), nil
+}
@@ -0,0 +1,272 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// signingKey is a locally generated key that signs test tokens.
+type signingKey struct {
+	kid string
+	alg string
+	key crypto.Signer
+}
+
+func newRSAKey(t *testing.T, kid string) signingKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return signingKey{kid: kid, alg: "RS256", key: key}
+}
+
+func newECKey(t *testing.T, kid string) signingKey {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return signingKey{kid: kid, alg: "ES256", key: key}
+}
+
+// jwk returns the public half of k as a JSON Web Key.
+func (k signingKey) jwk() map[string]string {
+	b64 := base64.RawURLEncoding.EncodeToString
+	switch key := k.key.Public().(type) {
+	case *rsa.PublicKey:
+		return map[string]string{"kty": "RSA", "kid": k.kid, "use": "sig", "n": b64(key.N.Bytes()), "e": b64(big.NewInt(int64(key.E)).Bytes())}
+	case *ecdsa.PublicKey:
+		return map[string]string{"kty": "EC", "kid": k.kid, "crv": "P-256", "x": b64(key.X.FillBytes(make([]byte, 32))), "y": b64(key.Y.FillBytes(make([]byte, 32)))}
+	}
+	return nil
+}
+
+// sign returns a token with claims signed by k.
+func (k signingKey) sign(t *testing.T, claims map[string]any) string {
+	encode := func(v any) string {
+		data, err := json.Marshal(v)
+		require.NoError(t, err)
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	signingInput := encode(map[string]string{"alg": k.alg, "kid": k.kid, "typ": "JWT"}) + "." + encode(claims)
+	digest := sha256.Sum256([]byte(signingInput))
+
+	var signature []byte
+	switch key := k.key.(type) {
+	case *rsa.PrivateKey:
+		var err error
+		signature, err = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+		require.NoError(t, err)
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+		require.NoError(t, err)
+		signature = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func jwksJSON(t *testing.T, keys ...signingKey) []byte {
+	set := map[string][]map[string]string{"keys": {}}
+	for _, k := range keys {
+		set["keys"] = append(set["keys"], k.jwk())
+	}
+	data, err := json.Marshal(set)
+	require.NoError(t, err)
+	return data
+}
+
+func writeJWKS(t *testing.T, path string, keys ...signingKey) {
+	require.NoError(t, os.WriteFile(path, jwksJSON(t, keys...), 0o600))
+}
+
+func validClaims() map[string]any {
+	return map[string]any{
+		"iss":   "https://id.example.com",
+		"aud":   []string{"products-api"},
+		"sub":   "user-7",
+		"scope": "products:read products:write",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func TestJWTVerifier_Verify(t *testing.T) {
+	rsaKey := newRSAKey(t, "rsa-1")
+	ecKey := newECKey(t, "ec-1")
+	untrusted := newRSAKey(t, "rsa-1")
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	writeJWKS(t, path, rsaKey, ecKey)
+	verifier := NewJWTVerifier(NewJWKS(path, time.Hour), "https://id.example.com", "products-api", 30*time.Second)
+
+	with := func(key string, value any) map[string]any {
+		claims := validClaims()
+		if value == nil {
+			delete(claims, key)
+		} else {
+			claims[key] = value
+		}
+		return claims
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		valid bool
+	}{
+		{name: "RS256", token: rsaKey.sign(t, validClaims()), valid: true},
+		{name: "ES256", token: ecKey.sign(t, validClaims()), valid: true},
+		{name: "Single Audience", token: rsaKey.sign(t, with("aud", "products-api")), valid: true},
+		{name: "Expired Within Leeway", token: rsaKey.sign(t, with("exp", time.Now().Add(-10*time.Second).Unix())), valid: true},
+		{name: "Expired", token: rsaKey.sign(t, with("exp", time.Now().Add(-time.Minute).Unix()))},
+		{name: "No Expiry", token: rsaKey.sign(t, with("exp", nil))},
+		{name: "Not Valid Yet", token: rsaKey.sign(t, with("nbf", time.Now().Add(time.Hour).Unix()))},
+		{name: "Wrong Issuer", token: rsaKey.sign(t, with("iss", "https://evil.example.com"))},
+		{name: "Wrong Audience", token: rsaKey.sign(t, with("aud", "orders-api"))},
+		{name: "Untrusted Key", token: untrusted.sign(t, validClaims())},
+		{name: "Unknown Key", token: newECKey(t, "ec-2").sign(t, validClaims())},
+		{name: "Unsigned", token: strings.Join(strings.Split(rsaKey.sign(t, validClaims()), ".")[:2], ".") + "."},
+		{name: "Algorithm None", token: base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","kid":"rsa-1"}`)) + "." +
+			strings.Split(rsaKey.sign(t, validClaims()), ".")[1] + "."},
+		{name: "Not A JWT", token: "pk_abc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := verifier.Verify(context.Background(), tt.token)
+			if !tt.valid {
+				assert.ErrorIs(t, err, ErrInvalidToken)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "user-7", claims.Subject())
+			assert.Equal(t, []string{ScopeProductsRead, ScopeProductsWrite}, claims.Scopes())
+		})
+	}
+}
+
+func TestJWKS_Key(t *testing.T) {
+	oldKey := newRSAKey(t, "2024-01")
+	newKey := newECKey(t, "2024-02")
+
+	t.Run("Cached From URL", func(t *testing.T) {
+		var fetches atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fetches.Add(1)
+			_, _ = w.Write(jwksJSON(t, oldKey))
+		}))
+		defer server.Close()
+
+		jwks := NewJWKS(server.URL, time.Hour)
+		for range 3 {
+			_, err := jwks.Key(context.Background(), "2024-01")
+			require.NoError(t, err)
+		}
+		assert.Equal(t, int32(1), fetches.Load())
+
+		_, err := jwks.Key(context.Background(), "unknown")
+		assert.ErrorIs(t, err, ErrInvalidToken)
+		assert.Equal(t, int32(1), fetches.Load(), "unknown keys should not reload the set more than once per refresh interval")
+	})
+
+	t.Run("Rotation", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "jwks.json")
+		writeJWKS(t, path, oldKey)
+		jwks := NewJWKS(path, time.Hour)
+		jwks.minRefresh = 0
+
+		_, err := jwks.Key(context.Background(), "2024-01")
+		require.NoError(t, err)
+
+		writeJWKS(t, path, newKey)
+		_, err = jwks.Key(context.Background(), "2024-02")
+		require.NoError(t, err)
+		_, err = jwks.Key(context.Background(), "2024-01")
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("Keeps Keys When Reload Fails", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "jwks.json")
+		writeJWKS(t, path, oldKey)
+		jwks := NewJWKS(path, time.Hour)
+
+		_, err := jwks.Key(context.Background(), "2024-01")
+		require.NoError(t, err)
+
+		require.NoError(t, os.Remove(path))
+		jwks.loadedAt = time.Now().Add(-2 * time.Hour)
+		_, err = jwks.Key(context.Background(), "2024-01")
+		assert.NoError(t, err)
+	})
+
+	t.Run("Shares Concurrent Loads", func(t *testing.T) {
+		var fetches atomic.Int32
+		release := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fetches.Add(1)
+			<-release
+			_, _ = w.Write(jwksJSON(t, oldKey))
+		}))
+		defer server.Close()
+
+		jwks := NewJWKS(server.URL, time.Hour)
+		errs := make(chan error, 5)
+		for range cap(errs) {
+			go func() {
+				_, err := jwks.Key(context.Background(), "2024-01")
+				errs <- err
+			}()
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		_, err := jwks.Key(ctx, "2024-01")
+		assert.ErrorIs(t, err, context.DeadlineExceeded, "a caller should stop waiting for a load when its context ends")
+
+		close(release)
+		for range cap(errs) {
+			assert.NoError(t, <-errs)
+		}
+		assert.Equal(t, int32(1), fetches.Load())
+	})
+
+	t.Run("Backs Off After Failed Load", func(t *testing.T) {
+		var fetches atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fetches.Add(1)
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		jwks := NewJWKS(server.URL, time.Hour)
+		for range 3 {
+			_, err := jwks.Key(context.Background(), "2024-01")
+			assert.Error(t, err)
+			assert.NotErrorIs(t, err, ErrInvalidToken)
+		}
+		assert.Equal(t, int32(1), fetches.Load(), "a failed load should not be retried within the refresh interval")
+
+		jwks.failedAt = time.Now().Add(-time.Minute)
+		_, err := jwks.Key(context.Background(), "2024-01")
+		assert.Error(t, err)
+		assert.Equal(t, int32(2), fetches.Load())
+	})
+
+	t.Run("Missing Source", func(t *testing.T) {
+		_, err := NewJWKS(filepath.Join(t.TempDir(), "missing.json"), time.Hour).Key(context.Background(), "2024-01")
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, ErrInvalidToken)
+	})
+}
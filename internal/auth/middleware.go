@@ -0,0 +1,173 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/mariosker/products_rest_api/internal/repository"
+	"github.com/mariosker/products_rest_api/internal/utils"
+)
+
+// APIKeyStore looks up active API keys by hash.
+type APIKeyStore interface {
+	GetAPIKeyByHash(ctx context.Context, hash []byte) (*models.APIKey, error)
+}
+
+// Options configures the API key middleware.
+type Options struct {
+	// PublicPaths are served without authentication, along with every path below them.
+	PublicPaths []string
+	// AdminKey, when set, is a key that is granted every scope without being stored.
+	// It is meant for issuing the first API keys.
+	AdminKey string
+	// Tokens, when set, verifies JSON Web Tokens presented as bearer tokens. Their scope claim
+	// grants scopes like those of API keys.
+	Tokens *JWTVerifier
+	// Policy grants API keys and bearer tokens the scopes of their roles. Roles grant nothing without one.
+	Policy *Policy
+}
+
+// scopeReasons describe what each scope RequiredScope returns is needed for.
+var scopeReasons = map[string]string{
+	ScopeProductsRead:    "read products",
+	ScopeProductsWrite:   "change products",
+	ScopePriceWrite:      "change prices",
+	ScopeReviewsModerate: "moderate reviews",
+	ScopeAdmin:           "use the admin endpoints",
+}
+
+// Authenticate authenticates requests by the API key in the Authorization: Bearer or X-API-Key
+// header, or by a JSON Web Token in the Authorization header when opts.Tokens is set, and rejects
+// those that lack the scope returned by RequiredScope.
+func Authenticate(store APIKeyStore, opts Options) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if isPublicPath(c.Request.URL.Path, opts.PublicPaths) {
+			c.Next()
+			return
+		}
+
+		key := requestAPIKey(c)
+		if key == "" {
+			c.Header("WWW-Authenticate", `Bearer realm="products"`)
+			utils.SendErrorResponse(c, http.StatusUnauthorized, "Missing API key or bearer token")
+			c.Abort()
+			return
+		}
+
+		var principal *Principal
+		if opts.AdminKey != "" && subtle.ConstantTimeCompare([]byte(key), []byte(opts.AdminKey)) == 1 {
+			principal = &Principal{Subject: "admin-key", Scopes: Permissions}
+		} else if opts.Tokens != nil && strings.Count(key, ".") == 2 {
+			claims, err := opts.Tokens.Verify(c.Request.Context(), key)
+			if errors.Is(err, ErrInvalidToken) {
+				c.Header("WWW-Authenticate", `Bearer realm="products", error="invalid_token"`)
+				utils.SendErrorResponse(c, http.StatusUnauthorized, "Invalid bearer token: "+err.Error())
+				c.Abort()
+				return
+			}
+			if err != nil {
+				utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to check bearer token")
+				c.Abort()
+				return
+			}
+			principal = &Principal{Subject: claims.Subject(), Scopes: claims.Scopes(), Roles: claims.Roles(), Tenant: claims.Tenant(), Claims: claims}
+		} else {
+			apiKey, err := store.GetAPIKeyByHash(c.Request.Context(), HashAPIKey(key))
+			if errors.Is(err, repository.ErrNotFound) {
+				c.Header("WWW-Authenticate", `Bearer realm="products", error="invalid_token"`)
+				utils.SendErrorResponse(c, http.StatusUnauthorized, "Invalid API key")
+				c.Abort()
+				return
+			}
+			if err != nil {
+				utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to check API key")
+				c.Abort()
+				return
+			}
+			principal = &Principal{Subject: "api-key:" + strconv.Itoa(apiKey.ID), Scopes: apiKey.Scopes, Roles: apiKey.Roles, Tenant: apiKey.TenantID}
+		}
+		if opts.Policy != nil && len(principal.Roles) > 0 {
+			principal.Scopes = slices.Concat(principal.Scopes, opts.Policy.Permissions(principal.Roles))
+		}
+
+		if scope := RequiredScope(c.Request); !principal.HasScope(scope) {
+			Deny(c, scope, scopeReasons[scope])
+			return
+		}
+
+		SetPrincipal(c, principal)
+		c.Next()
+	}
+}
+
+// RequiredScope returns the scope a request needs: admin below /admin and for /metrics,
+// products:read to read and to quote prices, reviews:moderate to change the status of reviews,
+// products:price:write for changes that set prices and products:write to make other changes.
+// Handlers check for products:price:write themselves where only some changes affect prices.
+func RequiredScope(r *http.Request) string {
+	switch {
+	case r.URL.Path == "/admin" || strings.HasPrefix(r.URL.Path, "/admin/") || r.URL.Path == "/metrics":
+		return ScopeAdmin
+	case r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions:
+		return ScopeProductsRead
+	case r.Method == http.MethodPost && r.URL.Path == "/pricing/quote":
+		return ScopeProductsRead
+	case moderatesReview(r):
+		return ScopeReviewsModerate
+	case setsPrices(r):
+		return ScopePriceWrite
+	default:
+		return ScopeProductsWrite
+	}
+}
+
+// moderatesReview reports whether a request changes the status of a review, as
+// PUT /products/:id/reviews/:reviewId/status does.
+func moderatesReview(r *http.Request) bool {
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	return r.Method == http.MethodPut && len(segments) == 5 && segments[0] == "products" && segments[2] == "reviews" && segments[4] == "status"
+}
+
+// setsPrices reports whether a change request sets prices: price schedules, bundle pricing,
+// discounts, and variants, which may override the product's price.
+func setsPrices(r *http.Request) bool {
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case segments[0] == "discounts":
+		return true
+	case segments[0] == "products" && len(segments) >= 3:
+		switch segments[2] {
+		case "price-schedules", "bundle":
+			return true
+		case "variants":
+			return r.Method == http.MethodPost || r.Method == http.MethodPut
+		}
+	}
+	return false
+}
+
+// requestAPIKey returns the API key presented in the Authorization or X-API-Key header, or "".
+func requestAPIKey(c *gin.Context) string {
+	if scheme, token, ok := strings.Cut(c.GetHeader("Authorization"), " "); ok && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(token)
+	}
+	return strings.TrimSpace(c.GetHeader("X-API-Key"))
+}
+
+// isPublicPath reports whether path is one of publicPaths or below one of them.
+func isPublicPath(path string, publicPaths []string) bool {
+	for _, public := range publicPaths {
+		public = strings.TrimSuffix(public, "/")
+		if path == public || strings.HasPrefix(path, public+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,201 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/mariosker/products_rest_api/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeAPIKeyStore serves API keys from memory by the hash of their key.
+type fakeAPIKeyStore struct {
+	keys map[string]*models.APIKey
+	err  error
+}
+
+func (s *fakeAPIKeyStore) GetAPIKeyByHash(ctx context.Context, hash []byte) (*models.APIKey, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	for key, apiKey := range s.keys {
+		if bytes.Equal(HashAPIKey(key), hash) {
+			return apiKey, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func TestAPIKeys(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	store := &fakeAPIKeyStore{keys: map[string]*models.APIKey{
+		"pk_reader": {ID: 1, Scopes: []string{ScopeProductsRead}},
+		"pk_writer": {ID: 2, Scopes: []string{ScopeProductsRead, ScopeProductsWrite}},
+	}}
+	router := gin.New()
+	router.Use(Authenticate(store, Options{PublicPaths: []string{"/health", "/swagger/"}, AdminKey: "pk_admin"}))
+	handler := func(c *gin.Context) {
+		if principal := PrincipalFrom(c); principal != nil {
+			c.String(http.StatusOK, principal.Subject)
+			return
+		}
+		c.Status(http.StatusOK)
+	}
+	router.GET("/health", handler)
+	router.GET("/swagger/*any", handler)
+	router.GET("/products", handler)
+	router.POST("/products", handler)
+	router.GET("/admin/api-keys", handler)
+	router.GET("/metrics", handler)
+
+	tests := []struct {
+		name    string
+		method  string
+		path    string
+		header  string
+		value   string
+		code    int
+		subject string
+	}{
+		{name: "Public Path", method: "GET", path: "/health", code: http.StatusOK},
+		{name: "Below Public Path", method: "GET", path: "/swagger/index.html", code: http.StatusOK},
+		{name: "Missing Key", method: "GET", path: "/products", code: http.StatusUnauthorized},
+		{name: "Unknown Key", method: "GET", path: "/products", header: "X-API-Key", value: "pk_unknown", code: http.StatusUnauthorized},
+		{name: "Bearer Key", method: "GET", path: "/products", header: "Authorization", value: "Bearer pk_reader", code: http.StatusOK, subject: "api-key:1"},
+		{name: "X-API-Key Header", method: "GET", path: "/products", header: "X-API-Key", value: "pk_reader", code: http.StatusOK, subject: "api-key:1"},
+		{name: "Missing Write Scope", method: "POST", path: "/products", header: "X-API-Key", value: "pk_reader", code: http.StatusForbidden},
+		{name: "Write Scope", method: "POST", path: "/products", header: "X-API-Key", value: "pk_writer", code: http.StatusOK, subject: "api-key:2"},
+		{name: "Missing Admin Scope", method: "GET", path: "/admin/api-keys", header: "X-API-Key", value: "pk_writer", code: http.StatusForbidden},
+		{name: "Admin Key", method: "GET", path: "/admin/api-keys", header: "Authorization", value: "Bearer pk_admin", code: http.StatusOK, subject: "admin-key"},
+		{name: "Metrics Without Admin Scope", method: "GET", path: "/metrics", header: "X-API-Key", value: "pk_reader", code: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tt.method, tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.code, w.Code)
+			if tt.code == http.StatusUnauthorized {
+				assert.Contains(t, w.Header().Get("WWW-Authenticate"), "Bearer")
+			}
+			if tt.subject != "" {
+				assert.Equal(t, tt.subject, w.Body.String())
+			}
+		})
+	}
+
+	t.Run("Store Error", func(t *testing.T) {
+		router := gin.New()
+		router.Use(Authenticate(&fakeAPIKeyStore{err: errors.New("connection refused")}, Options{}))
+		router.GET("/products", handler)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/products", nil)
+		req.Header.Set("X-API-Key", "pk_reader")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+func TestAuthenticate_BearerToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	key := newECKey(t, "ec-1")
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	writeJWKS(t, path, key)
+	verifier := NewJWTVerifier(NewJWKS(path, time.Hour), "https://id.example.com", "products-api", 0)
+
+	router := gin.New()
+	router.Use(Authenticate(&fakeAPIKeyStore{keys: map[string]*models.APIKey{"pk_reader": {ID: 1, Scopes: []string{ScopeProductsRead}}}},
+		Options{Tokens: verifier}))
+	router.GET("/products", func(c *gin.Context) {
+		principal := PrincipalFrom(c)
+		c.JSON(http.StatusOK, gin.H{"subject": principal.Subject, "email": principal.Claims["email"]})
+	})
+	router.POST("/products", func(c *gin.Context) { c.Status(http.StatusCreated) })
+
+	send := func(method, token string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, "/products", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Subject And Claims", func(t *testing.T) {
+		claims := validClaims()
+		claims["email"] = "ana@example.com"
+		w := send("GET", key.sign(t, claims))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"subject":"user-7","email":"ana@example.com"}`, w.Body.String())
+	})
+
+	t.Run("Missing Scope", func(t *testing.T) {
+		claims := validClaims()
+		claims["scope"] = "products:read"
+
+		assert.Equal(t, http.StatusForbidden, send("POST", key.sign(t, claims)).Code)
+	})
+
+	t.Run("Invalid Token", func(t *testing.T) {
+		claims := validClaims()
+		claims["aud"] = "orders-api"
+		w := send("GET", key.sign(t, claims))
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Contains(t, w.Header().Get("WWW-Authenticate"), `error="invalid_token"`)
+	})
+
+	t.Run("API Key Still Accepted", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, send("GET", "pk_reader").Code)
+	})
+}
+
+func TestRequiredScope(t *testing.T) {
+	tests := []struct {
+		method, path, scope string
+	}{
+		{"GET", "/products", ScopeProductsRead},
+		{"POST", "/products", ScopeProductsWrite},
+		{"PUT", "/products/1", ScopeProductsWrite},
+		{"GET", "/products/1/price-schedules", ScopeProductsRead},
+		{"POST", "/products/1/price-schedules", ScopePriceWrite},
+		{"DELETE", "/products/1/price-schedules/2", ScopePriceWrite},
+		{"POST", "/products/1/variants", ScopePriceWrite},
+		{"PUT", "/products/1/variants/2", ScopePriceWrite},
+		{"DELETE", "/products/1/variants/2", ScopeProductsWrite},
+		{"PUT", "/products/1/bundle", ScopePriceWrite},
+		{"DELETE", "/products/1/bundle", ScopePriceWrite},
+		{"GET", "/discounts", ScopeProductsRead},
+		{"POST", "/discounts", ScopePriceWrite},
+		{"PUT", "/discounts/1", ScopePriceWrite},
+		{"DELETE", "/discounts/1", ScopePriceWrite},
+		{"POST", "/pricing/quote", ScopeProductsRead},
+		{"POST", "/products/1/reviews", ScopeProductsWrite},
+		{"PUT", "/products/1/reviews/2/status", ScopeReviewsModerate},
+		{"DELETE", "/products/1/reviews/2", ScopeProductsWrite},
+		{"GET", "/admin/api-keys", ScopeAdmin},
+		{"GET", "/metrics", ScopeAdmin},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			req, _ := http.NewRequest(tt.method, tt.path, nil)
+			assert.Equal(t, tt.scope, RequiredScope(req))
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"slices"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Scopes granted to API keys and bearer tokens, directly or through roles. ScopePriceWrite is
+// needed to change prices and ScopeReviewsModerate to approve or reject reviews.
+const (
+	ScopeProductsRead    = "products:read"
+	ScopeProductsWrite   = "products:write"
+	ScopePriceWrite      = "products:price:write"
+	ScopeReviewsModerate = "reviews:moderate"
+	ScopeAdmin           = "admin"
+)
+
+// principalKey is the Gin context key the authenticated principal is stored under.
+const principalKey = "auth.principal"
+
+// Principal is the authenticated client of a request.
+type Principal struct {
+	// Subject identifies the client: "api-key:3" for an API key, or the sub claim of a bearer token.
+	Subject string
+	// Scopes are the scopes granted to the client directly and through its Roles.
+	Scopes []string
+	Roles  []string
+	// Tenant is the tenant the client is bound to, or "" when it may choose one.
+	Tenant string
+	// Claims are the claims of the bearer token the client authenticated with, if any.
+	Claims Claims
+}
+
+// HasScope reports whether the principal was granted scope.
+func (p *Principal) HasScope(scope string) bool {
+	return slices.Contains(p.Scopes, scope)
+}
+
+// SetPrincipal stores the authenticated principal of a request.
+func SetPrincipal(c *gin.Context, principal *Principal) {
+	c.Set(principalKey, principal)
+}
+
+// PrincipalFrom returns the authenticated principal of a request, or nil when the route is public
+// or authentication is disabled.
+func PrincipalFrom(c *gin.Context) *Principal {
+	if principal, ok := c.Get(principalKey); ok {
+		return principal.(*Principal)
+	}
+	return nil
+}
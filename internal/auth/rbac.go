@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mariosker/products_rest_api/internal/utils"
+)
+
+// Permissions are the scopes that can be granted to roles.
+var Permissions = []string{ScopeProductsRead, ScopeProductsWrite, ScopePriceWrite, ScopeReviewsModerate, ScopeAdmin}
+
+// Policy maps roles to the permissions they grant.
+type Policy struct {
+	Roles map[string][]string `json:"roles"`
+}
+
+// DefaultPolicy is used when no policy file is configured.
+var DefaultPolicy = &Policy{Roles: map[string][]string{
+	"viewer":          {ScopeProductsRead},
+	"moderator":       {ScopeProductsRead, ScopeReviewsModerate},
+	"editor":          {ScopeProductsRead, ScopeProductsWrite, ScopeReviewsModerate},
+	"pricing-manager": {ScopeProductsRead, ScopeProductsWrite, ScopeReviewsModerate, ScopePriceWrite},
+	"admin":           Permissions,
+}}
+
+// LoadPolicy reads a policy from a JSON file of the form {"roles": {"editor": ["products:read", ...]}}.
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var policy Policy
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("failed to parse policy %s: %w", path, err)
+	}
+	for role, permissions := range policy.Roles {
+		for _, permission := range permissions {
+			if !slices.Contains(Permissions, permission) {
+				return nil, fmt.Errorf("role %q of policy %s grants unknown permission %q", role, path, permission)
+			}
+		}
+	}
+	return &policy, nil
+}
+
+// Permissions returns the permissions granted by roles. Roles the policy does not define grant nothing.
+func (p *Policy) Permissions(roles []string) []string {
+	var permissions []string
+	for _, role := range roles {
+		for _, permission := range p.Roles[role] {
+			if !slices.Contains(permissions, permission) {
+				permissions = append(permissions, permission)
+			}
+		}
+	}
+	return permissions
+}
+
+// Allowed reports whether the client of a request was granted permission. Requests are always
+// allowed when authentication is disabled.
+func Allowed(c *gin.Context, permission string) bool {
+	principal := PrincipalFrom(c)
+	return principal == nil || principal.HasScope(permission)
+}
+
+// Deny aborts a request with 403 Forbidden, explaining which permission it lacks and why it is needed.
+func Deny(c *gin.Context, permission, reason string) {
+	utils.SendErrorResponse(c, http.StatusForbidden, "The "+permission+" permission is required to "+reason)
+	c.Abort()
+}
@@ -0,0 +1,81 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadPolicy(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		policy, err := LoadPolicy(write("valid.json", `{"roles":{"viewer":["products:read"],"pricing":["products:read","products:price:write"]}}`))
+		require.NoError(t, err)
+		assert.Equal(t, []string{ScopeProductsRead, ScopePriceWrite}, policy.Permissions([]string{"viewer", "pricing", "unknown"}))
+	})
+
+	t.Run("Unknown Permission", func(t *testing.T) {
+		_, err := LoadPolicy(write("typo.json", `{"roles":{"editor":["products:wirte"]}}`))
+		assert.ErrorContains(t, err, `unknown permission "products:wirte"`)
+	})
+
+	t.Run("Malformed", func(t *testing.T) {
+		_, err := LoadPolicy(write("malformed.json", `{"roles":`))
+		assert.Error(t, err)
+	})
+}
+
+func TestAuthenticate_Roles(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	store := &fakeAPIKeyStore{keys: map[string]*models.APIKey{
+		"pk_viewer":  {ID: 1, Roles: []string{"viewer"}},
+		"pk_editor":  {ID: 2, Roles: []string{"editor"}},
+		"pk_pricing": {ID: 3, Roles: []string{"pricing-manager"}},
+	}}
+	router := gin.New()
+	router.Use(Authenticate(store, Options{Policy: DefaultPolicy}))
+	router.GET("/products", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.POST("/products", func(c *gin.Context) { c.Status(http.StatusCreated) })
+	router.POST("/products/:id/price-schedules", func(c *gin.Context) { c.Status(http.StatusCreated) })
+
+	tests := []struct {
+		name   string
+		key    string
+		method string
+		path   string
+		code   int
+	}{
+		{name: "Viewer Reads", key: "pk_viewer", method: "GET", path: "/products", code: http.StatusOK},
+		{name: "Viewer Cannot Write", key: "pk_viewer", method: "POST", path: "/products", code: http.StatusForbidden},
+		{name: "Editor Writes", key: "pk_editor", method: "POST", path: "/products", code: http.StatusCreated},
+		{name: "Editor Cannot Schedule Prices", key: "pk_editor", method: "POST", path: "/products/1/price-schedules", code: http.StatusForbidden},
+		{name: "Pricing Manager Schedules Prices", key: "pk_pricing", method: "POST", path: "/products/1/price-schedules", code: http.StatusCreated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("X-API-Key", tt.key)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.code, w.Code)
+			if tt.code == http.StatusForbidden {
+				assert.Contains(t, w.Body.String(), "permission is required to")
+			}
+		})
+	}
+}
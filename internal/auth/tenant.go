@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mariosker/products_rest_api/internal/tenant"
+	"github.com/mariosker/products_rest_api/internal/utils"
+)
+
+// ResolveTenant resolves the tenant of every request and stores it in the request context. Clients
+// bound to a tenant by their API key or bearer token work on that tenant and cannot choose another.
+// Other clients, and every client when authentication is disabled, choose one with the X-Tenant-ID
+// header or work on defaultTenant. Requests without a tenant are rejected when defaultTenant is empty.
+func ResolveTenant(defaultTenant string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requested := c.GetHeader(tenant.Header)
+		if requested != "" && !tenant.ValidID(requested) {
+			utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid "+tenant.Header+" header: "+requested)
+			c.Abort()
+			return
+		}
+
+		id := defaultTenant
+		if principal := PrincipalFrom(c); principal != nil && principal.Tenant != "" {
+			if requested != "" && requested != principal.Tenant {
+				utils.SendErrorResponse(c, http.StatusForbidden, "Credentials are not valid for tenant "+requested)
+				c.Abort()
+				return
+			}
+			id = principal.Tenant
+		} else if requested != "" {
+			id = requested
+		}
+		if id == "" {
+			utils.SendErrorResponse(c, http.StatusBadRequest, "Missing "+tenant.Header+" header")
+			c.Abort()
+			return
+		}
+
+		c.Request = c.Request.WithContext(tenant.WithID(c.Request.Context(), id))
+		c.Next()
+	}
+}
@@ -0,0 +1,61 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mariosker/products_rest_api/internal/tenant"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveTenant(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func(principal *Principal, defaultTenant string) *gin.Engine {
+		router := gin.New()
+		if principal != nil {
+			router.Use(func(c *gin.Context) { SetPrincipal(c, principal) })
+		}
+		router.Use(ResolveTenant(defaultTenant))
+		router.GET("/products", func(c *gin.Context) {
+			id, _ := tenant.FromContext(c.Request.Context())
+			c.String(http.StatusOK, id)
+		})
+		return router
+	}
+
+	tests := []struct {
+		name          string
+		principal     *Principal
+		defaultTenant string
+		header        string
+		code          int
+		tenant        string
+	}{
+		{name: "Default Tenant", defaultTenant: "default", code: http.StatusOK, tenant: "default"},
+		{name: "Header", defaultTenant: "default", header: "acme", code: http.StatusOK, tenant: "acme"},
+		{name: "Invalid Header", defaultTenant: "default", header: "Acme Inc", code: http.StatusBadRequest},
+		{name: "No Tenant", code: http.StatusBadRequest},
+		{name: "Unbound Principal Chooses", principal: &Principal{Subject: "admin-key"}, header: "globex", code: http.StatusOK, tenant: "globex"},
+		{name: "Bound Principal", principal: &Principal{Subject: "api-key:1", Tenant: "acme"}, code: http.StatusOK, tenant: "acme"},
+		{name: "Bound Principal Same Header", principal: &Principal{Subject: "api-key:1", Tenant: "acme"}, header: "acme", code: http.StatusOK, tenant: "acme"},
+		{name: "Bound Principal Other Tenant", principal: &Principal{Subject: "api-key:1", Tenant: "acme"}, header: "globex", code: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/products", nil)
+			if tt.header != "" {
+				req.Header.Set(tenant.Header, tt.header)
+			}
+			newRouter(tt.principal, tt.defaultTenant).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.code, w.Code)
+			if tt.code == http.StatusOK {
+				assert.Equal(t, tt.tenant, w.Body.String())
+			}
+		})
+	}
+}
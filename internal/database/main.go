@@ -2,33 +2,43 @@ package database
 
 import (
 	"context"
-	"log"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-type DBConnection = *pgx.Conn
+// DBConnection is the subset of pgx used by the repositories.
+// It is satisfied by both *pgx.Conn and *pgxpool.Pool.
+type DBConnection interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
 
-var db *pgx.Conn
+var db *pgxpool.Pool
 
-// InitDB initializes the database connection
-func InitDB(connectionString string) error {
-	var err error
-	db, err = pgx.Connect(context.Background(), connectionString)
+// InitDB initializes the database connection pool. tracer, when not nil, is told about every query.
+func InitDB(connectionString string, tracer pgx.QueryTracer) error {
+	config, err := pgxpool.ParseConfig(connectionString)
 	if err != nil {
 		return err
 	}
-	return nil
+	config.ConnConfig.Tracer = tracer
+	db, err = pgxpool.NewWithConfig(context.Background(), config)
+	if err != nil {
+		return err
+	}
+	return db.Ping(context.Background())
 }
 
-// CloseDB closes the database connection
+// CloseDB closes the database connection pool
 func CloseDB() {
-	if err := db.Close(context.Background()); err != nil {
-		log.Fatal("Error closing database connection:", err)
-	}
+	db.Close()
 }
 
-// GetDB returns the database connection
-func GetDB() *pgx.Conn {
+// GetDB returns the database connection pool
+func GetDB() *pgxpool.Pool {
 	return db
 }
@@ -0,0 +1,17 @@
+package database
+
+import "context"
+
+type operationKey struct{}
+
+// WithOperation returns a copy of ctx that attributes the queries made with it to operation, such
+// as PostgresProductRepository.GetProductByID, for query tracers.
+func WithOperation(ctx context.Context, operation string) context.Context {
+	return context.WithValue(ctx, operationKey{}, operation)
+}
+
+// Operation returns the operation the queries made with ctx are attributed to, or "" when none was named.
+func Operation(ctx context.Context) string {
+	operation, _ := ctx.Value(operationKey{}).(string)
+	return operation
+}
@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mariosker/products_rest_api/internal/auth"
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/mariosker/products_rest_api/internal/repository"
+	"github.com/mariosker/products_rest_api/internal/tenant"
+	"github.com/mariosker/products_rest_api/internal/utils"
+)
+
+// APIKeyHandler handles HTTP requests for issuing, rotating and revoking API keys.
+type APIKeyHandler struct {
+	repo repository.APIKeyRepository
+}
+
+// NewAPIKeyHandler creates a new APIKeyHandler with the given repository.
+func NewAPIKeyHandler(repo repository.APIKeyRepository) *APIKeyHandler {
+	return &APIKeyHandler{repo: repo}
+}
+
+// CreateAPIKey godoc
+// @Summary Issue an API key
+// @Description Issue an API key with the given scopes. The key is only returned in this response. Clients bound to a tenant can only issue keys bound to the same tenant.
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Param key body models.APIKeyPayload true "API Key Payload"
+// @Success 201 {object} models.IssuedAPIKey
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /admin/api-keys [post]
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	var payload models.APIKeyPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(payload.Scopes) == 0 && len(payload.Roles) == 0 {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "An API key needs at least one scope or role")
+		return
+	}
+	if payload.TenantID != "" && !tenant.ValidID(payload.TenantID) {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid tenant ID: "+payload.TenantID)
+		return
+	}
+	if bound := boundTenant(c); bound != "" {
+		if payload.TenantID != "" && payload.TenantID != bound {
+			utils.SendErrorResponse(c, http.StatusForbidden, "Credentials are not valid for tenant "+payload.TenantID)
+			return
+		}
+		payload.TenantID = bound
+	}
+
+	key, prefix, err := auth.GenerateAPIKey()
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to generate API key")
+		return
+	}
+
+	issued := models.IssuedAPIKey{
+		APIKey: models.APIKey{Name: payload.Name, Prefix: prefix, Scopes: payload.Scopes, Roles: payload.Roles, TenantID: payload.TenantID, ExpiresAt: payload.ExpiresAt},
+		Key:    key,
+	}
+	if err := h.repo.CreateAPIKey(c.Request.Context(), &issued.APIKey, auth.HashAPIKey(key)); err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to store API key")
+		return
+	}
+
+	c.JSON(http.StatusCreated, issued)
+}
+
+// GetAPIKeys godoc
+// @Summary List API keys
+// @Description Retrieve every API key, including revoked ones, without the keys themselves. Clients bound to a tenant only see the keys of their tenant.
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.APIKey
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /admin/api-keys [get]
+func (h *APIKeyHandler) GetAPIKeys(c *gin.Context) {
+	keys, err := h.repo.GetAPIKeys(c.Request.Context(), boundTenant(c))
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve API keys")
+		return
+	}
+
+	c.JSON(http.StatusOK, keys)
+}
+
+// RotateAPIKey godoc
+// @Summary Rotate an API key
+// @Description Replace the key of an API key, optionally keeping the old key working for grace_seconds. The new key is only returned in this response.
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Param id path int true "API Key ID"
+// @Param rotation body models.RotateAPIKeyPayload false "Rotate API Key Payload"
+// @Success 200 {object} models.IssuedAPIKey
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /admin/api-keys/{id}/rotate [post]
+func (h *APIKeyHandler) RotateAPIKey(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid ID: "+c.Param("id"))
+		return
+	}
+
+	// The body is optional: without one the old key stops working immediately.
+	var payload models.RotateAPIKeyPayload
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			utils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	key, prefix, err := auth.GenerateAPIKey()
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to generate API key")
+		return
+	}
+
+	grace := time.Duration(payload.GraceSeconds) * time.Second
+	apiKey, err := h.repo.RotateAPIKey(c.Request.Context(), id, boundTenant(c), prefix, auth.HashAPIKey(key), grace)
+	if err != nil {
+		sendAPIKeyError(c, err, "Failed to rotate API key")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.IssuedAPIKey{APIKey: *apiKey, Key: key})
+}
+
+// RevokeAPIKey godoc
+// @Summary Revoke an API key
+// @Description Stop an API key from working
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Param id path int true "API Key ID"
+// @Success 204 {} {}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /admin/api-keys/{id} [delete]
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid ID: "+c.Param("id"))
+		return
+	}
+
+	if err := h.repo.RevokeAPIKey(c.Request.Context(), id, boundTenant(c)); err != nil {
+		sendAPIKeyError(c, err, "Failed to revoke API key")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// boundTenant returns the tenant the client is bound to, or "" when it manages the keys of every tenant.
+// Keys of other tenants are treated as missing for bound clients.
+func boundTenant(c *gin.Context) string {
+	if principal := auth.PrincipalFrom(c); principal != nil {
+		return principal.Tenant
+	}
+	return ""
+}
+
+// sendAPIKeyError maps repository errors to HTTP responses.
+func sendAPIKeyError(c *gin.Context, err error, fallback string) {
+	if errors.Is(err, repository.ErrNotFound) {
+		utils.SendErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+	utils.SendErrorResponse(c, http.StatusInternalServerError, fallback)
+}
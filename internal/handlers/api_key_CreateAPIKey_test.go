@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mariosker/products_rest_api/internal/auth"
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeyHandler_CreateAPIKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.Default()
+	mockRepo := new(MockAPIKeyRepository)
+	handler := NewAPIKeyHandler(mockRepo)
+
+	router.POST("/admin/api-keys", handler.CreateAPIKey)
+
+	t.Run("Success", func(t *testing.T) {
+		var storedHash []byte
+		mockRepo.On("CreateAPIKey", mock.Anything, mock.MatchedBy(func(key *models.APIKey) bool {
+			return key.Name == "storefront" && len(key.Scopes) == 1 && key.Scopes[0] == auth.ScopeProductsRead
+		}), mock.Anything).Run(func(args mock.Arguments) {
+			args.Get(1).(*models.APIKey).ID = 1
+			storedHash = args.Get(2).([]byte)
+		}).Return(nil).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/admin/api-keys", strings.NewReader(`{"name":"storefront","scopes":["products:read"]}`))
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusCreated, w.Code)
+		var issued models.IssuedAPIKey
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &issued))
+		assert.Equal(t, 1, issued.ID)
+		assert.True(t, strings.HasPrefix(issued.Key, issued.Prefix))
+		assert.Equal(t, auth.HashAPIKey(issued.Key), storedHash)
+	})
+
+	t.Run("Invalid Payload", func(t *testing.T) {
+		for _, body := range []string{
+			`{"scopes":["products:read"]}`,
+			`{"name":"storefront","scopes":[]}`,
+			`{"name":"storefront","scopes":["products:delete"]}`,
+		} {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", "/admin/api-keys", strings.NewReader(body))
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, body)
+		}
+	})
+
+	t.Run("Bound To Tenant", func(t *testing.T) {
+		boundRouter := gin.New()
+		boundRouter.POST("/admin/api-keys", func(c *gin.Context) {
+			auth.SetPrincipal(c, &auth.Principal{Subject: "api-key:7", Scopes: []string{auth.ScopeAdmin}, Tenant: "acme"})
+		}, handler.CreateAPIKey)
+		mockRepo.On("CreateAPIKey", mock.Anything, mock.MatchedBy(func(key *models.APIKey) bool {
+			return key.Name == "acme storefront" && key.TenantID == "acme"
+		}), mock.Anything).Return(nil).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/admin/api-keys", strings.NewReader(`{"name":"acme storefront","scopes":["products:read"]}`))
+		boundRouter.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		w = httptest.NewRecorder()
+		req, _ = http.NewRequest("POST", "/admin/api-keys", strings.NewReader(`{"name":"planted","scopes":["admin"],"tenant_id":"globex"}`))
+		boundRouter.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("Repository Error", func(t *testing.T) {
+		mockRepo.On("CreateAPIKey", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("database error")).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/admin/api-keys", strings.NewReader(`{"name":"backoffice","scopes":["products:write"]}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAPIKeyRepository is a mock implementation of APIKeyRepository.
+// It is used to simulate API keys in handler tests without a real database.
+type MockAPIKeyRepository struct {
+	mock.Mock
+}
+
+// CreateAPIKey mocks storing a new API key.
+func (m *MockAPIKeyRepository) CreateAPIKey(ctx context.Context, key *models.APIKey, hash []byte) error {
+	args := m.Called(ctx, key, hash)
+	return args.Error(0)
+}
+
+// GetAPIKeys mocks retrieving the API keys of a tenant.
+func (m *MockAPIKeyRepository) GetAPIKeys(ctx context.Context, tenantID string) ([]*models.APIKey, error) {
+	args := m.Called(ctx, tenantID)
+	if keys, ok := args.Get(0).([]*models.APIKey); ok {
+		return keys, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// GetAPIKeyByHash mocks looking up an active API key.
+func (m *MockAPIKeyRepository) GetAPIKeyByHash(ctx context.Context, hash []byte) (*models.APIKey, error) {
+	args := m.Called(ctx, hash)
+	if key, ok := args.Get(0).(*models.APIKey); ok {
+		return key, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// HasActiveAPIKeys mocks checking for usable API keys.
+func (m *MockAPIKeyRepository) HasActiveAPIKeys(ctx context.Context) (bool, error) {
+	args := m.Called(ctx)
+	return args.Bool(0), args.Error(1)
+}
+
+// RotateAPIKey mocks replacing the key of an API key.
+func (m *MockAPIKeyRepository) RotateAPIKey(ctx context.Context, id int, tenantID, prefix string, hash []byte, grace time.Duration) (*models.APIKey, error) {
+	args := m.Called(ctx, id, tenantID, prefix, hash, grace)
+	if key, ok := args.Get(0).(*models.APIKey); ok {
+		return key, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// RevokeAPIKey mocks revoking an API key.
+func (m *MockAPIKeyRepository) RevokeAPIKey(ctx context.Context, id int, tenantID string) error {
+	args := m.Called(ctx, id, tenantID)
+	return args.Error(0)
+}
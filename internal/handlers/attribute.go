@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/mariosker/products_rest_api/internal/repository"
+	"github.com/mariosker/products_rest_api/internal/utils"
+)
+
+// AttributeHandler handles HTTP requests for managing the attribute definitions of product categories.
+type AttributeHandler struct {
+	repo repository.AttributeRepository
+}
+
+// NewAttributeHandler creates a new AttributeHandler with the given repository.
+func NewAttributeHandler(repo repository.AttributeRepository) *AttributeHandler {
+	return &AttributeHandler{repo: repo}
+}
+
+// GetAttributeDefinitions godoc
+// @Summary List the attribute definitions of a category
+// @Description Retrieve every attribute definition of a product category
+// @Tags attributes
+// @Accept json
+// @Produce json
+// @Param category path string true "Category"
+// @Success 200 {array} models.AttributeDefinition
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /categories/{category}/attributes [get]
+func (h *AttributeHandler) GetAttributeDefinitions(c *gin.Context) {
+	definitions, err := h.repo.GetAttributeDefinitions(c.Request.Context(), c.Param("category"))
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve attribute definitions")
+		return
+	}
+
+	c.JSON(http.StatusOK, definitions)
+}
+
+// PutAttributeDefinition godoc
+// @Summary Create or replace an attribute definition
+// @Description Define a typed attribute for products of a category. Products created or updated afterwards are validated against it.
+// @Tags attributes
+// @Accept json
+// @Produce json
+// @Param category path string true "Category"
+// @Param name path string true "Attribute name"
+// @Param definition body models.AttributeDefinitionPayload true "Attribute Definition Payload"
+// @Success 200 {object} models.AttributeDefinition
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /categories/{category}/attributes/{name} [put]
+func (h *AttributeHandler) PutAttributeDefinition(c *gin.Context) {
+	var payload models.AttributeDefinitionPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if payload.Type != models.AttributeTypeString && len(payload.AllowedValues) > 0 {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "allowed_values only applies to string attributes")
+		return
+	}
+	if payload.Type != models.AttributeTypeNumber && (payload.Min != nil || payload.Max != nil) {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "min and max only apply to number attributes")
+		return
+	}
+	if payload.Min != nil && payload.Max != nil && *payload.Min > *payload.Max {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "min must not be greater than max")
+		return
+	}
+
+	definition := models.AttributeDefinition{
+		Category:      c.Param("category"),
+		Name:          c.Param("name"),
+		Type:          payload.Type,
+		Required:      payload.Required,
+		AllowedValues: payload.AllowedValues,
+		Min:           payload.Min,
+		Max:           payload.Max,
+	}
+	if err := h.repo.PutAttributeDefinition(c.Request.Context(), &definition); err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to store attribute definition")
+		return
+	}
+
+	c.JSON(http.StatusOK, definition)
+}
+
+// DeleteAttributeDefinition godoc
+// @Summary Delete an attribute definition
+// @Description Delete an attribute definition of a category. Existing product attributes are kept.
+// @Tags attributes
+// @Accept json
+// @Produce json
+// @Param category path string true "Category"
+// @Param name path string true "Attribute name"
+// @Success 204 {} {}
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /categories/{category}/attributes/{name} [delete]
+func (h *AttributeHandler) DeleteAttributeDefinition(c *gin.Context) {
+	err := h.repo.DeleteAttributeDefinition(c.Request.Context(), c.Param("category"), c.Param("name"))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			utils.SendErrorResponse(c, http.StatusNotFound, err.Error())
+		} else {
+			utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to delete attribute definition")
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
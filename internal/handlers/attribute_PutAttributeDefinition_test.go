@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAttributeHandler_PutAttributeDefinition(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.Default()
+	mockRepo := new(MockAttributeRepository)
+	handler := NewAttributeHandler(mockRepo)
+
+	router.PUT("/categories/:category/attributes/:name", handler.PutAttributeDefinition)
+
+	t.Run("Success", func(t *testing.T) {
+		min, max := 4.0, 128.0
+		definition := &models.AttributeDefinition{Category: "laptops", Name: "ram_gb", Type: "number", Required: true, Min: &min, Max: &max}
+		mockRepo.On("PutAttributeDefinition", mock.Anything, definition).Return(nil).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/categories/laptops/attributes/ram_gb", strings.NewReader(`{"type":"number","required":true,"min":4,"max":128}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"category":"laptops","name":"ram_gb","type":"number","required":true,"min":4,"max":128}`, w.Body.String())
+	})
+
+	t.Run("Unknown Type", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/categories/laptops/attributes/ram_gb", strings.NewReader(`{"type":"date"}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Allowed Values On Number", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/categories/laptops/attributes/ram_gb", strings.NewReader(`{"type":"number","allowed_values":["8","16"]}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "allowed_values only applies to string attributes")
+	})
+
+	t.Run("Min Greater Than Max", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/categories/laptops/attributes/ram_gb", strings.NewReader(`{"type":"number","min":10,"max":1}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Repository Error", func(t *testing.T) {
+		definition := &models.AttributeDefinition{Category: "laptops", Name: "colour", Type: "string", AllowedValues: []string{"silver", "black"}}
+		mockRepo.On("PutAttributeDefinition", mock.Anything, definition).Return(errors.New("database error")).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/categories/laptops/attributes/colour", strings.NewReader(`{"type":"string","allowed_values":["silver","black"]}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), "Failed to store attribute definition")
+	})
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAttributeRepository is a mock implementation of AttributeRepository.
+// It is used to simulate category attribute definitions in handler tests without a real database.
+type MockAttributeRepository struct {
+	mock.Mock
+}
+
+// GetAttributeDefinitions mocks retrieving the attribute definitions of a category.
+func (m *MockAttributeRepository) GetAttributeDefinitions(ctx context.Context, category string) ([]*models.AttributeDefinition, error) {
+	args := m.Called(ctx, category)
+	if definitions, ok := args.Get(0).([]*models.AttributeDefinition); ok {
+		return definitions, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// PutAttributeDefinition mocks creating or replacing an attribute definition.
+func (m *MockAttributeRepository) PutAttributeDefinition(ctx context.Context, definition *models.AttributeDefinition) error {
+	args := m.Called(ctx, definition)
+	return args.Error(0)
+}
+
+// DeleteAttributeDefinition mocks deleting an attribute definition.
+func (m *MockAttributeRepository) DeleteAttributeDefinition(ctx context.Context, category, name string) error {
+	args := m.Called(ctx, category, name)
+	return args.Error(0)
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/mariosker/products_rest_api/internal/repository"
+	"github.com/mariosker/products_rest_api/internal/utils"
+)
+
+// BundleHandler handles HTTP requests for managing the components of bundle products.
+type BundleHandler struct {
+	repo repository.BundleRepository
+}
+
+// NewBundleHandler creates a new BundleHandler with the given repository.
+func NewBundleHandler(repo repository.BundleRepository) *BundleHandler {
+	return &BundleHandler{repo: repo}
+}
+
+// GetBundle godoc
+// @Summary Get the components of a bundle
+// @Description Retrieve the components and pricing of a bundle product
+// @Tags bundles
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {object} models.Bundle
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /products/{id}/bundle [get]
+func (h *BundleHandler) GetBundle(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid ID: "+c.Param("id"))
+		return
+	}
+
+	bundle, err := h.repo.GetBundle(c.Request.Context(), productID)
+	if err != nil {
+		sendBundleError(c, err, "Failed to retrieve bundle")
+		return
+	}
+
+	c.JSON(http.StatusOK, bundle)
+}
+
+// PutBundle godoc
+// @Summary Make a product a bundle
+// @Description Turn a product into a bundle of other products, or replace the components and pricing of a bundle
+// @Tags bundles
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param bundle body models.BundlePayload true "Bundle Payload"
+// @Success 200 {object} models.Bundle
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /products/{id}/bundle [put]
+func (h *BundleHandler) PutBundle(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid ID: "+c.Param("id"))
+		return
+	}
+
+	var payload models.BundlePayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	bundle, err := h.repo.PutBundle(c.Request.Context(), productID, &payload)
+	if err != nil {
+		sendBundleError(c, err, "Failed to store bundle")
+		return
+	}
+
+	c.JSON(http.StatusOK, bundle)
+}
+
+// DeleteBundle godoc
+// @Summary Unbundle a product
+// @Description Remove the components of a bundle, turning it back into a simple product
+// @Tags bundles
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 204 {} {}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /products/{id}/bundle [delete]
+func (h *BundleHandler) DeleteBundle(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid ID: "+c.Param("id"))
+		return
+	}
+
+	if err := h.repo.DeleteBundle(c.Request.Context(), productID); err != nil {
+		sendBundleError(c, err, "Failed to delete bundle")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// sendBundleError maps repository errors to HTTP responses.
+func sendBundleError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		utils.SendErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, repository.ErrInvalidBundle):
+		utils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+	default:
+		utils.SendErrorResponse(c, http.StatusInternalServerError, fallback)
+	}
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/mariosker/products_rest_api/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestBundleHandler_PutBundle(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.Default()
+	mockRepo := new(MockBundleRepository)
+	handler := NewBundleHandler(mockRepo)
+
+	router.PUT("/products/:id/bundle", handler.PutBundle)
+
+	t.Run("Success", func(t *testing.T) {
+		payload := &models.BundlePayload{
+			Pricing:         models.BundlePricingDerived,
+			DiscountPercent: 10,
+			Components:      []models.BundleComponent{{ProductID: 2, Quantity: 1}, {ProductID: 3, Quantity: 2}},
+		}
+		bundle := &models.Bundle{ProductID: 1, Pricing: payload.Pricing, DiscountPercent: 10, Components: payload.Components}
+		mockRepo.On("PutBundle", mock.Anything, 1, payload).Return(bundle, nil).Times(1)
+
+		w := httptest.NewRecorder()
+		body := `{"pricing":"derived","discount_percent":10,"components":[{"product_id":2,"quantity":1},{"product_id":3,"quantity":2}]}`
+		req, _ := http.NewRequest("PUT", "/products/1/bundle", strings.NewReader(body))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"product_id":1,"pricing":"derived","discount_percent":10,"components":[{"product_id":2,"quantity":1},{"product_id":3,"quantity":2}]}`, w.Body.String())
+	})
+
+	t.Run("Invalid Payload", func(t *testing.T) {
+		for _, body := range []string{
+			`{"pricing":"cheapest","components":[{"product_id":2,"quantity":1}]}`,
+			`{"pricing":"fixed","components":[]}`,
+			`{"pricing":"fixed","components":[{"product_id":2,"quantity":0}]}`,
+			`{"pricing":"derived","discount_percent":100,"components":[{"product_id":2,"quantity":1}]}`,
+		} {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("PUT", "/products/1/bundle", strings.NewReader(body))
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, body)
+		}
+	})
+
+	t.Run("Invalid Component", func(t *testing.T) {
+		err := fmt.Errorf("component product with ID 4 is itself a bundle: %w", repository.ErrInvalidBundle)
+		mockRepo.On("PutBundle", mock.Anything, 1, mock.Anything).Return(nil, err).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/products/1/bundle", strings.NewReader(`{"pricing":"fixed","components":[{"product_id":4,"quantity":1}]}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "is itself a bundle")
+	})
+
+	t.Run("Product Not Found", func(t *testing.T) {
+		mockRepo.On("PutBundle", mock.Anything, 9, mock.Anything).Return(nil, fmt.Errorf("product with ID 9: %w", repository.ErrNotFound)).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/products/9/bundle", strings.NewReader(`{"pricing":"fixed","components":[{"product_id":2,"quantity":1}]}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockBundleRepository is a mock implementation of BundleRepository.
+// It is used to simulate bundle products in handler tests without a real database.
+type MockBundleRepository struct {
+	mock.Mock
+}
+
+// PutBundle mocks turning a product into a bundle.
+func (m *MockBundleRepository) PutBundle(ctx context.Context, productID int, payload *models.BundlePayload) (*models.Bundle, error) {
+	args := m.Called(ctx, productID, payload)
+	if bundle, ok := args.Get(0).(*models.Bundle); ok {
+		return bundle, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// GetBundle mocks retrieving the components of a bundle.
+func (m *MockBundleRepository) GetBundle(ctx context.Context, productID int) (*models.Bundle, error) {
+	args := m.Called(ctx, productID)
+	if bundle, ok := args.Get(0).(*models.Bundle); ok {
+		return bundle, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// DeleteBundle mocks turning a bundle back into a simple product.
+func (m *MockBundleRepository) DeleteBundle(ctx context.Context, productID int) error {
+	args := m.Called(ctx, productID)
+	return args.Error(0)
+}
+
+// DeleteProductCascade mocks deleting a product after removing it from its bundles.
+func (m *MockBundleRepository) DeleteProductCascade(ctx context.Context, productID int) error {
+	args := m.Called(ctx, productID)
+	return args.Error(0)
+}
@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/mariosker/products_rest_api/internal/repository"
+	"github.com/mariosker/products_rest_api/internal/utils"
+)
+
+// DiscountHandler handles HTTP requests for managing discount rules.
+type DiscountHandler struct {
+	repo repository.DiscountRepository
+}
+
+// NewDiscountHandler creates a new DiscountHandler with the given repository.
+func NewDiscountHandler(repo repository.DiscountRepository) *DiscountHandler {
+	return &DiscountHandler{repo: repo}
+}
+
+// CreateDiscount godoc
+// @Summary Create a discount rule
+// @Description Create a percentage, fixed or tiered discount for the products matched by its target
+// @Tags discounts
+// @Accept json
+// @Produce json
+// @Param discount body models.DiscountPayload true "Discount Payload"
+// @Success 201 {object} models.Discount
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /discounts [post]
+func (h *DiscountHandler) CreateDiscount(c *gin.Context) {
+	payload, ok := bindDiscountPayload(c)
+	if !ok {
+		return
+	}
+
+	discount, err := h.repo.CreateDiscount(c.Request.Context(), payload)
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to create discount")
+		return
+	}
+
+	c.JSON(http.StatusCreated, discount)
+}
+
+// GetDiscounts godoc
+// @Summary List discount rules
+// @Description Retrieve every discount rule in the order they are applied
+// @Tags discounts
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.Discount
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /discounts [get]
+func (h *DiscountHandler) GetDiscounts(c *gin.Context) {
+	discounts, err := h.repo.GetDiscounts(c.Request.Context())
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve discounts")
+		return
+	}
+
+	c.JSON(http.StatusOK, discounts)
+}
+
+// GetDiscount godoc
+// @Summary Get a discount rule by ID
+// @Description Retrieve a single discount rule
+// @Tags discounts
+// @Accept json
+// @Produce json
+// @Param id path int true "Discount ID"
+// @Success 200 {object} models.Discount
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /discounts/{id} [get]
+func (h *DiscountHandler) GetDiscount(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid ID: "+c.Param("id"))
+		return
+	}
+
+	discount, err := h.repo.GetDiscountByID(c.Request.Context(), id)
+	if err != nil {
+		sendDiscountError(c, err, "Failed to retrieve discount")
+		return
+	}
+
+	c.JSON(http.StatusOK, discount)
+}
+
+// UpdateDiscount godoc
+// @Summary Update a discount rule by ID
+// @Description Replace a discount rule
+// @Tags discounts
+// @Accept json
+// @Produce json
+// @Param id path int true "Discount ID"
+// @Param discount body models.DiscountPayload true "Discount Payload"
+// @Success 200 {object} models.Discount
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /discounts/{id} [put]
+func (h *DiscountHandler) UpdateDiscount(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid ID: "+c.Param("id"))
+		return
+	}
+
+	payload, ok := bindDiscountPayload(c)
+	if !ok {
+		return
+	}
+
+	discount, err := h.repo.UpdateDiscount(c.Request.Context(), id, payload)
+	if err != nil {
+		sendDiscountError(c, err, "Failed to update discount with id: "+strconv.Itoa(id))
+		return
+	}
+
+	c.JSON(http.StatusOK, discount)
+}
+
+// DeleteDiscount godoc
+// @Summary Delete a discount rule by ID
+// @Description Delete a discount rule
+// @Tags discounts
+// @Accept json
+// @Produce json
+// @Param id path int true "Discount ID"
+// @Success 204 {} {}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /discounts/{id} [delete]
+func (h *DiscountHandler) DeleteDiscount(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid ID: "+c.Param("id"))
+		return
+	}
+
+	if err := h.repo.DeleteDiscount(c.Request.Context(), id); err != nil {
+		sendDiscountError(c, err, "Failed to delete discount with id: "+strconv.Itoa(id))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// bindDiscountPayload binds a discount payload and checks that its value and tiers suit its type,
+// sending a 400 response and returning false if it is invalid.
+func bindDiscountPayload(c *gin.Context) (*models.DiscountPayload, bool) {
+	var payload models.DiscountPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+
+	var problem string
+	switch payload.Type {
+	case models.DiscountTypePercentage:
+		if payload.Value <= 0 || payload.Value > 100 {
+			problem = "value must be a percentage between 0 and 100"
+		}
+	case models.DiscountTypeFixed:
+		if payload.Value <= 0 {
+			problem = "value must be greater than 0"
+		}
+	case models.DiscountTypeTiered:
+		if payload.Value != 0 {
+			problem = "value does not apply to tiered discounts"
+		} else if len(payload.Tiers) == 0 {
+			problem = "tiers are required for tiered discounts"
+		}
+	}
+	if payload.Type != models.DiscountTypeTiered && len(payload.Tiers) > 0 {
+		problem = "tiers only apply to tiered discounts"
+	}
+	if problem != "" {
+		utils.SendErrorResponse(c, http.StatusBadRequest, problem)
+		return nil, false
+	}
+	return &payload, true
+}
+
+// sendDiscountError maps repository errors to HTTP responses.
+func sendDiscountError(c *gin.Context, err error, fallback string) {
+	if errors.Is(err, repository.ErrNotFound) {
+		utils.SendErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+	utils.SendErrorResponse(c, http.StatusInternalServerError, fallback)
+}
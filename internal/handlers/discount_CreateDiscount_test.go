@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDiscountHandler_CreateDiscount(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.Default()
+	mockRepo := new(MockDiscountRepository)
+	handler := NewDiscountHandler(mockRepo)
+
+	router.POST("/discounts", handler.CreateDiscount)
+
+	t.Run("Percentage", func(t *testing.T) {
+		payload := &models.DiscountPayload{Name: "20% off laptops", Type: models.DiscountTypePercentage, Value: 20,
+			Target: models.DiscountTarget{Categories: []string{"laptops"}}}
+		discount := &models.Discount{ID: 1, Name: payload.Name, Type: payload.Type, Value: 20, Target: payload.Target}
+		mockRepo.On("CreateDiscount", mock.Anything, payload).Return(discount, nil).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/discounts", strings.NewReader(`{"name":"20% off laptops","type":"percentage","value":20,"target":{"categories":["laptops"]}}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), `"target":{"categories":["laptops"]}`)
+	})
+
+	t.Run("Tiered", func(t *testing.T) {
+		payload := &models.DiscountPayload{Name: "Buy 3 get 10% off", Type: models.DiscountTypeTiered,
+			Tiers: []models.DiscountTier{{MinQuantity: 3, Percent: 10}}, Stackable: true}
+		discount := &models.Discount{ID: 2, Name: payload.Name, Type: payload.Type, Tiers: payload.Tiers, Stackable: true}
+		mockRepo.On("CreateDiscount", mock.Anything, payload).Return(discount, nil).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/discounts", strings.NewReader(`{"name":"Buy 3 get 10% off","type":"tiered","tiers":[{"min_quantity":3,"percent":10}],"stackable":true}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), `"tiers":[{"min_quantity":3,"percent":10}]`)
+	})
+
+	invalid := []struct {
+		name    string
+		body    string
+		message string
+	}{
+		{"Unknown Type", `{"name":"Half off","type":"bogo","value":50}`, "oneof"},
+		{"Percentage Over 100", `{"name":"Free","type":"percentage","value":150}`, "value must be a percentage between 0 and 100"},
+		{"Fixed Without Value", `{"name":"Money off","type":"fixed"}`, "value must be greater than 0"},
+		{"Tiered Without Tiers", `{"name":"Bulk","type":"tiered"}`, "tiers are required for tiered discounts"},
+		{"Tiered With Value", `{"name":"Bulk","type":"tiered","value":5,"tiers":[{"min_quantity":3,"percent":10}]}`, "value does not apply to tiered discounts"},
+		{"Tiers On Percentage", `{"name":"Bulk","type":"percentage","value":5,"tiers":[{"min_quantity":3,"percent":10}]}`, "tiers only apply to tiered discounts"},
+		{"Invalid Tier", `{"name":"Bulk","type":"tiered","tiers":[{"min_quantity":0,"percent":10}]}`, "MinQuantity"},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", "/discounts", strings.NewReader(tt.body))
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, w.Body.String(), tt.message)
+		})
+	}
+
+	t.Run("Creation Error", func(t *testing.T) {
+		mockRepo.On("CreateDiscount", mock.Anything, mock.Anything).Return(nil, errors.New("database error")).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/discounts", strings.NewReader(`{"name":"5 off","type":"fixed","value":5}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), "Failed to create discount")
+	})
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockDiscountRepository is a mock implementation of DiscountRepository.
+// It is used to simulate discount rules in handler tests without a real database.
+type MockDiscountRepository struct {
+	mock.Mock
+}
+
+// CreateDiscount mocks the creation of a discount rule.
+func (m *MockDiscountRepository) CreateDiscount(ctx context.Context, payload *models.DiscountPayload) (*models.Discount, error) {
+	args := m.Called(ctx, payload)
+	if discount, ok := args.Get(0).(*models.Discount); ok {
+		return discount, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// GetDiscountByID mocks retrieving a discount rule by ID.
+func (m *MockDiscountRepository) GetDiscountByID(ctx context.Context, id int) (*models.Discount, error) {
+	args := m.Called(ctx, id)
+	if discount, ok := args.Get(0).(*models.Discount); ok {
+		return discount, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// GetDiscounts mocks retrieving every discount rule.
+func (m *MockDiscountRepository) GetDiscounts(ctx context.Context) ([]*models.Discount, error) {
+	args := m.Called(ctx)
+	if discounts, ok := args.Get(0).([]*models.Discount); ok {
+		return discounts, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// UpdateDiscount mocks replacing a discount rule.
+func (m *MockDiscountRepository) UpdateDiscount(ctx context.Context, id int, payload *models.DiscountPayload) (*models.Discount, error) {
+	args := m.Called(ctx, id, payload)
+	if discount, ok := args.Get(0).(*models.Discount); ok {
+		return discount, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// DeleteDiscount mocks deleting a discount rule.
+func (m *MockDiscountRepository) DeleteDiscount(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
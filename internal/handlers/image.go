@@ -0,0 +1,427 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/mariosker/products_rest_api/internal/repository"
+	"github.com/mariosker/products_rest_api/internal/storage"
+	"github.com/mariosker/products_rest_api/internal/thumbnails"
+	"github.com/mariosker/products_rest_api/internal/utils"
+)
+
+// imageExtensions maps the accepted image MIME types to the file extension used in storage keys.
+var imageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// multipartOverhead is the allowance for multipart headers and boundaries on top of the image size limit.
+const multipartOverhead = 1 << 20
+
+// imageCacheControl lets clients and proxies cache image responses indefinitely;
+// the content of an image never changes once uploaded.
+const imageCacheControl = "public, max-age=31536000, immutable"
+
+// ImageHandler handles HTTP requests for managing the images of a product.
+type ImageHandler struct {
+	repo           repository.ImageRepository
+	store          storage.Storage
+	maxBytes       int64
+	thumbnailCache *thumbnails.Cache
+	thumbnailSizes []int
+}
+
+// ImageHandlerOption configures optional features of an ImageHandler.
+type ImageHandlerOption func(*ImageHandler)
+
+// WithThumbnails enables resized downloads with ?w=, ?h= and ?fit=, restricted to the given sizes
+// and cached in cache.
+func WithThumbnails(cache *thumbnails.Cache, sizes []int) ImageHandlerOption {
+	return func(h *ImageHandler) {
+		h.thumbnailCache = cache
+		h.thumbnailSizes = sizes
+	}
+}
+
+// NewImageHandler creates a new ImageHandler that keeps image content in store
+// and rejects uploads larger than maxBytes.
+func NewImageHandler(repo repository.ImageRepository, store storage.Storage, maxBytes int64, opts ...ImageHandlerOption) *ImageHandler {
+	h := &ImageHandler{repo: repo, store: store, maxBytes: maxBytes}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// UploadImage godoc
+// @Summary Upload a product image
+// @Description Upload a JPEG, PNG, GIF or WebP image for a product. The first image of a product becomes its primary image.
+// @Tags images
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param image formData file true "Image file"
+// @Param position formData int false "Sort position; defaults to after the existing images"
+// @Param primary formData bool false "Make this the primary image"
+// @Success 201 {object} models.ProductImage
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 413 {object} utils.ErrorResponse
+// @Failure 415 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /products/{id}/images [post]
+func (h *ImageHandler) UploadImage(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid ID: "+c.Param("id"))
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxBytes+multipartOverhead)
+	header, err := c.FormFile("image")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			utils.SendErrorResponse(c, http.StatusRequestEntityTooLarge, h.tooLargeMessage())
+		} else {
+			utils.SendErrorResponse(c, http.StatusBadRequest, "Missing image file: "+err.Error())
+		}
+		return
+	}
+	if header.Size > h.maxBytes {
+		utils.SendErrorResponse(c, http.StatusRequestEntityTooLarge, h.tooLargeMessage())
+		return
+	}
+
+	image := models.ProductImage{ProductID: productID, Size: header.Size, Position: -1}
+	if position := c.PostForm("position"); position != "" {
+		if image.Position, err = strconv.Atoi(position); err != nil || image.Position < 0 {
+			utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid position: "+position)
+			return
+		}
+	}
+	if primary := c.PostForm("primary"); primary != "" {
+		if image.IsPrimary, err = strconv.ParseBool(primary); err != nil {
+			utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid primary: "+primary)
+			return
+		}
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Failed to read image")
+		return
+	}
+	defer file.Close()
+
+	// Trust the content rather than the client-supplied Content-Type.
+	sniff := make([]byte, 512)
+	n, err := io.ReadFull(file, sniff)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Failed to read image")
+		return
+	}
+	image.ContentType = http.DetectContentType(sniff[:n])
+	ext, ok := imageExtensions[image.ContentType]
+	if !ok {
+		utils.SendErrorResponse(c, http.StatusUnsupportedMediaType, "Unsupported image type: "+image.ContentType)
+		return
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to read image")
+		return
+	}
+
+	image.StorageKey, err = newImageKey(productID, ext)
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to store image")
+		return
+	}
+	ctx := c.Request.Context()
+	hash := sha256.New()
+	if err := h.store.Put(ctx, image.StorageKey, io.TeeReader(file, hash), image.Size, image.ContentType); err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to store image")
+		return
+	}
+	image.ContentHash = hex.EncodeToString(hash.Sum(nil))
+
+	created, err := h.repo.CreateImage(ctx, &image)
+	if err != nil {
+		h.deleteContent(c, image.StorageKey)
+		if errors.Is(err, repository.ErrNotFound) {
+			utils.SendErrorResponse(c, http.StatusNotFound, err.Error())
+		} else {
+			utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to create image")
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, withImageURL(created))
+}
+
+// GetImages godoc
+// @Summary List product images
+// @Description Retrieve the metadata of every image of a product ordered by position
+// @Tags images
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {array} models.ProductImage
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /products/{id}/images [get]
+func (h *ImageHandler) GetImages(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid ID: "+c.Param("id"))
+		return
+	}
+
+	images, err := h.repo.GetImagesByProductID(c.Request.Context(), productID)
+	if err != nil {
+		sendImageError(c, err, "Failed to retrieve images")
+		return
+	}
+
+	for _, image := range images {
+		withImageURL(image)
+	}
+	c.JSON(http.StatusOK, images)
+}
+
+// GetImage godoc
+// @Summary Download a product image
+// @Description Retrieve the content of a single image of a product. With w and/or h, a JPEG or PNG image
+// @Description is resized to one of the configured thumbnail sizes; fit=contain (the default) keeps the whole
+// @Description image within the size, fit=cover fills it and crops the overflow.
+// @Tags images
+// @Produce image/jpeg,image/png,image/gif,image/webp
+// @Param id path int true "Product ID"
+// @Param imageId path int true "Image ID"
+// @Param w query int false "Thumbnail width"
+// @Param h query int false "Thumbnail height"
+// @Param fit query string false "Thumbnail fit" Enums(contain, cover)
+// @Success 200 {file} binary
+// @Success 304 {} {}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 415 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /products/{id}/images/{imageId} [get]
+func (h *ImageHandler) GetImage(c *gin.Context) {
+	productID, imageID, ok := parseImagePath(c)
+	if !ok {
+		return
+	}
+	opts, thumbnail, ok := h.parseThumbnailOptions(c)
+	if !ok {
+		return
+	}
+
+	image, err := h.repo.GetImageByID(c.Request.Context(), productID, imageID)
+	if err != nil {
+		sendImageError(c, err, "Failed to retrieve image")
+		return
+	}
+	if thumbnail && !thumbnails.Supports(image.ContentType) {
+		utils.SendErrorResponse(c, http.StatusUnsupportedMediaType, "Thumbnails are only available for JPEG and PNG images")
+		return
+	}
+
+	etag := imageVersion(image)
+	if thumbnail {
+		etag = thumbnails.Key(etag, opts)
+	}
+	c.Header("Cache-Control", imageCacheControl)
+	c.Header("ETag", `"`+etag+`"`)
+	if c.GetHeader("If-None-Match") == `"`+etag+`"` {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	if thumbnail {
+		if cached, ok, err := h.thumbnailCache.Open(etag); err == nil && ok {
+			defer cached.Close()
+			if info, err := cached.Stat(); err == nil {
+				c.DataFromReader(http.StatusOK, info.Size(), image.ContentType, cached, nil)
+				return
+			}
+		}
+	}
+
+	content, err := h.store.Get(c.Request.Context(), image.StorageKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			utils.SendErrorResponse(c, http.StatusNotFound, "Content of image with id: "+strconv.Itoa(imageID)+" not found")
+		} else {
+			utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve image")
+		}
+		return
+	}
+	defer content.Close()
+
+	if !thumbnail {
+		c.DataFromReader(http.StatusOK, image.Size, image.ContentType, content, nil)
+		return
+	}
+
+	data, err := thumbnails.Generate(content, opts)
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to generate thumbnail")
+		return
+	}
+	if err := h.thumbnailCache.Put(etag, data); err != nil {
+		log.Printf("Failed to cache thumbnail %s: %v", etag, err)
+	}
+	c.DataFromReader(http.StatusOK, int64(len(data)), image.ContentType, bytes.NewReader(data), nil)
+}
+
+// parseThumbnailOptions parses the w, h and fit query parameters, reporting whether a thumbnail
+// was requested. It sends a 400 response and returns false as its last result if they are invalid.
+func (h *ImageHandler) parseThumbnailOptions(c *gin.Context) (thumbnails.Options, bool, bool) {
+	opts := thumbnails.Options{Fit: c.DefaultQuery("fit", thumbnails.FitContain)}
+	if c.Query("w") == "" && c.Query("h") == "" {
+		return opts, false, true
+	}
+	if h.thumbnailCache == nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Thumbnails are not enabled")
+		return opts, false, false
+	}
+
+	for _, dimension := range []struct {
+		param string
+		size  *int
+	}{{"w", &opts.Width}, {"h", &opts.Height}} {
+		param, size := dimension.param, dimension.size
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || !slices.Contains(h.thumbnailSizes, n) {
+			utils.SendErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("Invalid %s: %s; allowed sizes are %s", param, value, h.allowedSizes()))
+			return opts, false, false
+		}
+		*size = n
+	}
+	if opts.Fit != thumbnails.FitContain && opts.Fit != thumbnails.FitCover {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid fit: "+opts.Fit)
+		return opts, false, false
+	}
+	return opts, true, true
+}
+
+func (h *ImageHandler) allowedSizes() string {
+	sizes := make([]string, len(h.thumbnailSizes))
+	for i, size := range h.thumbnailSizes {
+		sizes[i] = strconv.Itoa(size)
+	}
+	return strings.Join(sizes, ", ")
+}
+
+// imageVersion identifies the content of an image: its content hash, or a hash of its storage key
+// for images uploaded before content hashes were recorded.
+func imageVersion(image *models.ProductImage) string {
+	if image.ContentHash != "" {
+		return image.ContentHash
+	}
+	sum := sha256.Sum256([]byte(image.StorageKey))
+	return hex.EncodeToString(sum[:])
+}
+
+// DeleteImage godoc
+// @Summary Delete a product image
+// @Description Delete a single image of a product. If it was the primary image, the next image becomes primary.
+// @Tags images
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param imageId path int true "Image ID"
+// @Success 204 {} {}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /products/{id}/images/{imageId} [delete]
+func (h *ImageHandler) DeleteImage(c *gin.Context) {
+	productID, imageID, ok := parseImagePath(c)
+	if !ok {
+		return
+	}
+
+	image, err := h.repo.DeleteImage(c.Request.Context(), productID, imageID)
+	if err != nil {
+		sendImageError(c, err, "Failed to delete image with id: "+strconv.Itoa(imageID))
+		return
+	}
+	h.deleteContent(c, image.StorageKey)
+
+	c.Status(http.StatusNoContent)
+}
+
+// deleteContent removes stored image content. Failures only leave an orphaned object behind,
+// so they are logged rather than reported to the client.
+func (h *ImageHandler) deleteContent(c *gin.Context, key string) {
+	if err := h.store.Delete(c.Request.Context(), key); err != nil {
+		log.Printf("Failed to delete image content %s: %v", key, err)
+	}
+}
+
+func (h *ImageHandler) tooLargeMessage() string {
+	return fmt.Sprintf("Image must not be larger than %d bytes", h.maxBytes)
+}
+
+// newImageKey returns a unique storage key for a new image of a product.
+func newImageKey(productID int, ext string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("products/%d/%s%s", productID, hex.EncodeToString(b), ext), nil
+}
+
+// withImageURL sets the URL the content of image is served from.
+func withImageURL(image *models.ProductImage) *models.ProductImage {
+	image.URL = fmt.Sprintf("/products/%d/images/%d", image.ProductID, image.ID)
+	return image
+}
+
+// parseImagePath parses the product and image IDs from the path,
+// sending a 400 response and returning false if either is invalid.
+func parseImagePath(c *gin.Context) (int, int, bool) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid ID: "+c.Param("id"))
+		return 0, 0, false
+	}
+	imageID, err := strconv.Atoi(c.Param("imageId"))
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid image ID: "+c.Param("imageId"))
+		return 0, 0, false
+	}
+	return productID, imageID, true
+}
+
+// sendImageError maps repository errors to HTTP responses.
+func sendImageError(c *gin.Context, err error, fallback string) {
+	if errors.Is(err, repository.ErrNotFound) {
+		utils.SendErrorResponse(c, http.StatusNotFound, err.Error())
+	} else {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, fallback)
+	}
+}
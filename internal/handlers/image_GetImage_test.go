@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/mariosker/products_rest_api/internal/storage"
+	"github.com/mariosker/products_rest_api/internal/thumbnails"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImageHandler_GetImage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var source bytes.Buffer
+	require.NoError(t, png.Encode(&source, image.NewRGBA(image.Rect(0, 0, 300, 150))))
+	content := source.Bytes()
+
+	router := gin.Default()
+	mockRepo := new(MockImageRepository)
+	store, err := storage.NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+	cache, err := thumbnails.NewCache(t.TempDir())
+	require.NoError(t, err)
+	handler := NewImageHandler(mockRepo, store, 1<<20, WithThumbnails(cache, []int{50, 100}))
+
+	router.GET("/products/:id/images/:imageId", handler.GetImage)
+
+	require.NoError(t, store.Put(context.Background(), "products/1/a.png", bytes.NewReader(content), int64(len(content)), "image/png"))
+	pngImage := &models.ProductImage{ID: 1, ProductID: 1, StorageKey: "products/1/a.png", ContentHash: "abc123", ContentType: "image/png", Size: int64(len(content))}
+	mockRepo.On("GetImageByID", mock.Anything, 1, 1).Return(pngImage, nil)
+	gifImage := &models.ProductImage{ID: 2, ProductID: 1, StorageKey: "products/1/b.gif", ContentType: "image/gif", Size: 10}
+	mockRepo.On("GetImageByID", mock.Anything, 1, 2).Return(gifImage, nil)
+
+	get := func(path string, header http.Header) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", path, nil)
+		for name, values := range header {
+			req.Header[name] = values
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Original", func(t *testing.T) {
+		w := get("/products/1/images/1", nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, content, w.Body.Bytes())
+		assert.Equal(t, `"abc123"`, w.Header().Get("ETag"))
+		assert.Equal(t, "public, max-age=31536000, immutable", w.Header().Get("Cache-Control"))
+	})
+
+	t.Run("Not Modified", func(t *testing.T) {
+		w := get("/products/1/images/1", http.Header{"If-None-Match": {`"abc123"`}})
+
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Empty(t, w.Body.Bytes())
+	})
+
+	thumbKey := thumbnails.Key("abc123", thumbnails.Options{Width: 50, Height: 50, Fit: thumbnails.FitCover})
+	t.Run("Thumbnail", func(t *testing.T) {
+		w := get("/products/1/images/1?w=50&h=50&fit=cover", nil)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+		assert.Equal(t, `"`+thumbKey+`"`, w.Header().Get("ETag"))
+		config, err := png.DecodeConfig(w.Body)
+		require.NoError(t, err)
+		assert.Equal(t, 50, config.Width)
+		assert.Equal(t, 50, config.Height)
+	})
+
+	t.Run("Thumbnail Served From Cache", func(t *testing.T) {
+		require.NoError(t, cache.Put(thumbKey, []byte("cached thumbnail")))
+
+		w := get("/products/1/images/1?w=50&h=50&fit=cover", nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "cached thumbnail", w.Body.String())
+	})
+
+	t.Run("Thumbnail Width Only", func(t *testing.T) {
+		w := get("/products/1/images/1?w=100", nil)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		config, err := png.DecodeConfig(w.Body)
+		require.NoError(t, err)
+		assert.Equal(t, 100, config.Width)
+		assert.Equal(t, 50, config.Height)
+	})
+
+	t.Run("Size Not Allowed", func(t *testing.T) {
+		w := get("/products/1/images/1?w=75", nil)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "Invalid w: 75; allowed sizes are 50, 100")
+	})
+
+	t.Run("Invalid Fit", func(t *testing.T) {
+		w := get("/products/1/images/1?w=50&fit=stretch", nil)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "Invalid fit: stretch")
+	})
+
+	t.Run("Unsupported Thumbnail Type", func(t *testing.T) {
+		w := get("/products/1/images/2?w=50", nil)
+
+		assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+	})
+}
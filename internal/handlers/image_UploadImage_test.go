@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/mariosker/products_rest_api/internal/repository"
+	"github.com/mariosker/products_rest_api/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// pngHeader is enough of a PNG file for content sniffing to recognise it.
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func newImageUpload(t *testing.T, content []byte, fields map[string]string) *http.Request {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	for name, value := range fields {
+		require.NoError(t, form.WriteField(name, value))
+	}
+	part, err := form.CreateFormFile("image", "photo.png")
+	require.NoError(t, err)
+	_, err = part.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, form.Close())
+
+	req, _ := http.NewRequest("POST", "/products/1/images", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	return req
+}
+
+func TestImageHandler_UploadImage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.Default()
+	mockRepo := new(MockImageRepository)
+	store, err := storage.NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+	handler := NewImageHandler(mockRepo, store, 64)
+
+	router.POST("/products/:id/images", handler.UploadImage)
+
+	t.Run("Success", func(t *testing.T) {
+		var storedKey string
+		mockRepo.On("CreateImage", mock.Anything, mock.MatchedBy(func(image *models.ProductImage) bool {
+			return image.ProductID == 1 && image.ContentType == "image/png" && image.Position == -1 && image.IsPrimary
+		})).Run(func(args mock.Arguments) {
+			storedKey = args.Get(1).(*models.ProductImage).StorageKey
+		}).Return(&models.ProductImage{ID: 7, ProductID: 1, ContentType: "image/png", Size: int64(len(pngHeader)), IsPrimary: true}, nil).Times(1)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, newImageUpload(t, pngHeader, map[string]string{"primary": "true"}))
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), `"url":"/products/1/images/7"`)
+		assert.NotContains(t, w.Body.String(), storedKey)
+
+		stored, err := store.Get(context.Background(), storedKey)
+		require.NoError(t, err)
+		content, _ := io.ReadAll(stored)
+		stored.Close()
+		assert.Equal(t, pngHeader, content)
+	})
+
+	t.Run("Unsupported Type", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, newImageUpload(t, []byte("%PDF-1.7 not an image"), nil))
+
+		assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+		assert.Contains(t, w.Body.String(), "Unsupported image type")
+	})
+
+	t.Run("Too Large", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, newImageUpload(t, append(pngHeader, make([]byte, 64)...), nil))
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+		assert.Contains(t, w.Body.String(), "Image must not be larger than 64 bytes")
+	})
+
+	t.Run("Missing File", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/products/1/images", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Invalid Position", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, newImageUpload(t, pngHeader, map[string]string{"position": "first"}))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "Invalid position: first")
+	})
+
+	t.Run("Product Not Found Removes Content", func(t *testing.T) {
+		var storedKey string
+		errRepo := fmt.Errorf("product with ID %d: %w", 1, repository.ErrNotFound)
+		mockRepo.On("CreateImage", mock.Anything, mock.MatchedBy(func(image *models.ProductImage) bool {
+			return image.Position == 2
+		})).Run(func(args mock.Arguments) {
+			storedKey = args.Get(1).(*models.ProductImage).StorageKey
+		}).Return(nil, errRepo).Times(1)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, newImageUpload(t, pngHeader, map[string]string{"position": "2"}))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		_, err := store.Get(context.Background(), storedKey)
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+	})
+}
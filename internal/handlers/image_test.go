@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockImageRepository is a mock implementation of ImageRepository.
+// It is used to simulate product image metadata in handler tests without a real database.
+type MockImageRepository struct {
+	mock.Mock
+}
+
+// CreateImage mocks storing the metadata of an uploaded image.
+func (m *MockImageRepository) CreateImage(ctx context.Context, image *models.ProductImage) (*models.ProductImage, error) {
+	args := m.Called(ctx, image)
+	if created, ok := args.Get(0).(*models.ProductImage); ok {
+		return created, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// GetImageByID mocks retrieving the metadata of a single image of a product.
+func (m *MockImageRepository) GetImageByID(ctx context.Context, productID, imageID int) (*models.ProductImage, error) {
+	args := m.Called(ctx, productID, imageID)
+	if image, ok := args.Get(0).(*models.ProductImage); ok {
+		return image, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// GetImagesByProductID mocks retrieving the metadata of every image of a product.
+func (m *MockImageRepository) GetImagesByProductID(ctx context.Context, productID int) ([]*models.ProductImage, error) {
+	args := m.Called(ctx, productID)
+	if images, ok := args.Get(0).([]*models.ProductImage); ok {
+		return images, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// DeleteImage mocks deleting the metadata of an image.
+func (m *MockImageRepository) DeleteImage(ctx context.Context, productID, imageID int) (*models.ProductImage, error) {
+	args := m.Called(ctx, productID, imageID)
+	if image, ok := args.Get(0).(*models.ProductImage); ok {
+		return image, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// GetOrphanedImageKeys mocks retrieving the storage keys of deleted images.
+func (m *MockImageRepository) GetOrphanedImageKeys(ctx context.Context, limit int) ([]string, error) {
+	args := m.Called(ctx, limit)
+	if keys, ok := args.Get(0).([]string); ok {
+		return keys, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// DeleteOrphanedImageKeys mocks forgetting the storage keys of deleted images.
+func (m *MockImageRepository) DeleteOrphanedImageKeys(ctx context.Context, keys []string) error {
+	args := m.Called(ctx, keys)
+	return args.Error(0)
+}
@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/mariosker/products_rest_api/internal/repository"
+	"github.com/mariosker/products_rest_api/internal/utils"
+)
+
+// PriceScheduleHandler handles HTTP requests for managing the scheduled prices of a product.
+type PriceScheduleHandler struct {
+	repo repository.PriceScheduleRepository
+}
+
+// NewPriceScheduleHandler creates a new PriceScheduleHandler with the given repository.
+func NewPriceScheduleHandler(repo repository.PriceScheduleRepository) *PriceScheduleHandler {
+	return &PriceScheduleHandler{repo: repo}
+}
+
+// CreatePriceSchedule godoc
+// @Summary Schedule a product price
+// @Description Schedule a price that applies from starts_at until ends_at, or indefinitely when ends_at is omitted
+// @Tags price-schedules
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param schedule body models.PriceSchedulePayload true "Price Schedule Payload"
+// @Success 201 {object} models.PriceSchedule
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /products/{id}/price-schedules [post]
+func (h *PriceScheduleHandler) CreatePriceSchedule(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid ID: "+c.Param("id"))
+		return
+	}
+
+	payload, ok := bindPriceSchedulePayload(c)
+	if !ok {
+		return
+	}
+
+	schedule, err := h.repo.CreatePriceSchedule(c.Request.Context(), productID, payload)
+	if err != nil {
+		sendPriceScheduleError(c, err, "Failed to create price schedule")
+		return
+	}
+
+	c.JSON(http.StatusCreated, schedule)
+}
+
+// GetPriceSchedules godoc
+// @Summary List product price schedules
+// @Description Retrieve every price schedule of a product ordered by start time
+// @Tags price-schedules
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {array} models.PriceSchedule
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /products/{id}/price-schedules [get]
+func (h *PriceScheduleHandler) GetPriceSchedules(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid ID: "+c.Param("id"))
+		return
+	}
+
+	schedules, err := h.repo.GetPriceSchedulesByProductID(c.Request.Context(), productID)
+	if err != nil {
+		sendPriceScheduleError(c, err, "Failed to retrieve price schedules")
+		return
+	}
+
+	c.JSON(http.StatusOK, schedules)
+}
+
+// GetPriceSchedule godoc
+// @Summary Get a product price schedule
+// @Description Retrieve a single price schedule of a product
+// @Tags price-schedules
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param scheduleId path int true "Price Schedule ID"
+// @Success 200 {object} models.PriceSchedule
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /products/{id}/price-schedules/{scheduleId} [get]
+func (h *PriceScheduleHandler) GetPriceSchedule(c *gin.Context) {
+	productID, scheduleID, ok := parsePriceSchedulePath(c)
+	if !ok {
+		return
+	}
+
+	schedule, err := h.repo.GetPriceScheduleByID(c.Request.Context(), productID, scheduleID)
+	if err != nil {
+		sendPriceScheduleError(c, err, "Failed to retrieve price schedule")
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+// UpdatePriceSchedule godoc
+// @Summary Update a product price schedule
+// @Description Replace the price and period of a price schedule
+// @Tags price-schedules
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param scheduleId path int true "Price Schedule ID"
+// @Param schedule body models.PriceSchedulePayload true "Price Schedule Payload"
+// @Success 200 {object} models.PriceSchedule
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /products/{id}/price-schedules/{scheduleId} [put]
+func (h *PriceScheduleHandler) UpdatePriceSchedule(c *gin.Context) {
+	productID, scheduleID, ok := parsePriceSchedulePath(c)
+	if !ok {
+		return
+	}
+
+	payload, ok := bindPriceSchedulePayload(c)
+	if !ok {
+		return
+	}
+
+	schedule, err := h.repo.UpdatePriceSchedule(c.Request.Context(), productID, scheduleID, payload)
+	if err != nil {
+		sendPriceScheduleError(c, err, "Failed to update price schedule with id: "+strconv.Itoa(scheduleID))
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+// DeletePriceSchedule godoc
+// @Summary Delete a product price schedule
+// @Description Delete a single price schedule of a product
+// @Tags price-schedules
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param scheduleId path int true "Price Schedule ID"
+// @Success 204 {} {}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /products/{id}/price-schedules/{scheduleId} [delete]
+func (h *PriceScheduleHandler) DeletePriceSchedule(c *gin.Context) {
+	productID, scheduleID, ok := parsePriceSchedulePath(c)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.DeletePriceSchedule(c.Request.Context(), productID, scheduleID); err != nil {
+		sendPriceScheduleError(c, err, "Failed to delete price schedule with id: "+strconv.Itoa(scheduleID))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// bindPriceSchedulePayload binds and validates a price schedule payload,
+// sending a 400 response and returning false if it is invalid.
+func bindPriceSchedulePayload(c *gin.Context) (*models.PriceSchedulePayload, bool) {
+	var payload models.PriceSchedulePayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+	if payload.EndsAt != nil && !payload.EndsAt.After(payload.StartsAt) {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "ends_at must be after starts_at")
+		return nil, false
+	}
+	return &payload, true
+}
+
+// parsePriceSchedulePath parses the product and price schedule IDs from the path,
+// sending a 400 response and returning false if either is invalid.
+func parsePriceSchedulePath(c *gin.Context) (int, int, bool) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid ID: "+c.Param("id"))
+		return 0, 0, false
+	}
+	scheduleID, err := strconv.Atoi(c.Param("scheduleId"))
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid price schedule ID: "+c.Param("scheduleId"))
+		return 0, 0, false
+	}
+	return productID, scheduleID, true
+}
+
+// sendPriceScheduleError maps repository errors to HTTP responses.
+func sendPriceScheduleError(c *gin.Context, err error, fallback string) {
+	if errors.Is(err, repository.ErrNotFound) {
+		utils.SendErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+	utils.SendErrorResponse(c, http.StatusInternalServerError, fallback)
+}
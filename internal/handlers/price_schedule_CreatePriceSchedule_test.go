@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/mariosker/products_rest_api/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPriceScheduleHandler_CreatePriceSchedule(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.Default()
+	mockRepo := new(MockPriceScheduleRepository)
+	handler := NewPriceScheduleHandler(mockRepo)
+
+	router.POST("/products/:id/price-schedules", handler.CreatePriceSchedule)
+
+	startsAt := time.Date(2024, 11, 29, 0, 0, 0, 0, time.UTC)
+	endsAt := time.Date(2024, 12, 3, 0, 0, 0, 0, time.UTC)
+
+	t.Run("Success", func(t *testing.T) {
+		payload := &models.PriceSchedulePayload{Price: 79.99, StartsAt: startsAt, EndsAt: &endsAt}
+		schedule := &models.PriceSchedule{ID: 1, ProductID: 2, Price: 79.99, StartsAt: startsAt, EndsAt: &endsAt}
+		mockRepo.On("CreatePriceSchedule", mock.Anything, 2, payload).Return(schedule, nil).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/products/2/price-schedules", strings.NewReader(`{"price":79.99,"starts_at":"2024-11-29T00:00:00Z","ends_at":"2024-12-03T00:00:00Z"}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), `"starts_at":"2024-11-29T00:00:00Z","ends_at":"2024-12-03T00:00:00Z"`)
+	})
+
+	t.Run("Without End", func(t *testing.T) {
+		payload := &models.PriceSchedulePayload{Price: 89, StartsAt: startsAt}
+		schedule := &models.PriceSchedule{ID: 2, ProductID: 2, Price: 89, StartsAt: startsAt}
+		mockRepo.On("CreatePriceSchedule", mock.Anything, 2, payload).Return(schedule, nil).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/products/2/price-schedules", strings.NewReader(`{"price":89,"starts_at":"2024-11-29T00:00:00Z"}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.NotContains(t, w.Body.String(), "ends_at")
+	})
+
+	t.Run("Invalid ID", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/products/invalid/price-schedules", strings.NewReader(`{"price":89,"starts_at":"2024-11-29T00:00:00Z"}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Missing Start", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/products/2/price-schedules", strings.NewReader(`{"price":89}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Non-positive Price", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/products/2/price-schedules", strings.NewReader(`{"price":0,"starts_at":"2024-11-29T00:00:00Z"}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("End Before Start", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/products/2/price-schedules", strings.NewReader(`{"price":89,"starts_at":"2024-12-03T00:00:00Z","ends_at":"2024-11-29T00:00:00Z"}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "ends_at must be after starts_at")
+	})
+
+	t.Run("Product Not Found", func(t *testing.T) {
+		mockRepo.On("CreatePriceSchedule", mock.Anything, 9, mock.Anything).Return(nil, repository.ErrNotFound).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/products/9/price-schedules", strings.NewReader(`{"price":89,"starts_at":"2024-11-29T00:00:00Z"}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Creation Error", func(t *testing.T) {
+		mockRepo.On("CreatePriceSchedule", mock.Anything, 5, mock.Anything).Return(nil, errors.New("database error")).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/products/5/price-schedules", strings.NewReader(`{"price":89,"starts_at":"2024-11-29T00:00:00Z"}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), "Failed to create price schedule")
+	})
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPriceScheduleRepository is a mock implementation of PriceScheduleRepository.
+// It is used to simulate scheduled prices in handler tests without a real database.
+type MockPriceScheduleRepository struct {
+	mock.Mock
+}
+
+// CreatePriceSchedule mocks scheduling a price for a product.
+func (m *MockPriceScheduleRepository) CreatePriceSchedule(ctx context.Context, productID int, payload *models.PriceSchedulePayload) (*models.PriceSchedule, error) {
+	args := m.Called(ctx, productID, payload)
+	if schedule, ok := args.Get(0).(*models.PriceSchedule); ok {
+		return schedule, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// GetPriceScheduleByID mocks retrieving a single price schedule of a product.
+func (m *MockPriceScheduleRepository) GetPriceScheduleByID(ctx context.Context, productID, scheduleID int) (*models.PriceSchedule, error) {
+	args := m.Called(ctx, productID, scheduleID)
+	if schedule, ok := args.Get(0).(*models.PriceSchedule); ok {
+		return schedule, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// GetPriceSchedulesByProductID mocks retrieving every price schedule of a product.
+func (m *MockPriceScheduleRepository) GetPriceSchedulesByProductID(ctx context.Context, productID int) ([]*models.PriceSchedule, error) {
+	args := m.Called(ctx, productID)
+	if schedules, ok := args.Get(0).([]*models.PriceSchedule); ok {
+		return schedules, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// UpdatePriceSchedule mocks replacing a price schedule of a product.
+func (m *MockPriceScheduleRepository) UpdatePriceSchedule(ctx context.Context, productID, scheduleID int, payload *models.PriceSchedulePayload) (*models.PriceSchedule, error) {
+	args := m.Called(ctx, productID, scheduleID, payload)
+	if schedule, ok := args.Get(0).(*models.PriceSchedule); ok {
+		return schedule, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// DeletePriceSchedule mocks deleting a price schedule of a product.
+func (m *MockPriceScheduleRepository) DeletePriceSchedule(ctx context.Context, productID, scheduleID int) error {
+	args := m.Called(ctx, productID, scheduleID)
+	return args.Error(0)
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/mariosker/products_rest_api/internal/pricing"
+	"github.com/mariosker/products_rest_api/internal/repository"
+	"github.com/mariosker/products_rest_api/internal/utils"
+)
+
+// PricingHandler handles HTTP requests for pricing line items.
+type PricingHandler struct {
+	products  repository.ProductRepository
+	discounts repository.DiscountRepository
+}
+
+// NewPricingHandler creates a new PricingHandler with the given repositories.
+func NewPricingHandler(products repository.ProductRepository, discounts repository.DiscountRepository) *PricingHandler {
+	return &PricingHandler{products: products, discounts: discounts}
+}
+
+// Quote godoc
+// @Summary Quote line items
+// @Description Price line items at the current product prices and apply the matching discount rules
+// @Tags pricing
+// @Accept json
+// @Produce json
+// @Param quote body models.QuotePayload true "Quote Payload"
+// @Success 200 {object} models.Quote
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /pricing/quote [post]
+func (h *PricingHandler) Quote(c *gin.Context) {
+	var payload models.QuotePayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	products := make(map[int]*models.Product)
+	lines := make([]pricing.Line, 0, len(payload.Items))
+	for _, item := range payload.Items {
+		product, ok := products[item.ProductID]
+		if !ok {
+			var err error
+			product, err = h.products.GetProductByID(c.Request.Context(), item.ProductID)
+			if err != nil || product.Status != models.ProductStatusPublished {
+				utils.SendErrorResponse(c, http.StatusNotFound, "Product with id: "+strconv.Itoa(item.ProductID)+" not found")
+				return
+			}
+			products[item.ProductID] = product
+		}
+		lines = append(lines, pricing.Line{Product: product, Quantity: item.Quantity})
+	}
+
+	discounts, err := h.discounts.GetDiscounts(c.Request.Context())
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve discounts")
+		return
+	}
+
+	c.JSON(http.StatusOK, pricing.Quote(discounts, lines))
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPricingHandler_Quote(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.Default()
+	mockProducts := new(MockProductRepository)
+	mockDiscounts := new(MockDiscountRepository)
+	handler := NewPricingHandler(mockProducts, mockDiscounts)
+
+	router.POST("/pricing/quote", handler.Quote)
+
+	laptop := &models.Product{ID: 1, Name: "Laptop", Price: 1000, Category: "laptops", Status: models.ProductStatusPublished}
+	cable := &models.Product{ID: 2, Name: "Cable", Price: 5, Status: models.ProductStatusPublished}
+	discounts := []*models.Discount{
+		{ID: 1, Name: "20% off laptops", Type: models.DiscountTypePercentage, Value: 20, Target: models.DiscountTarget{Categories: []string{"laptops"}}},
+		{ID: 2, Name: "Buy 3 get 10% off", Type: models.DiscountTypeTiered, Tiers: []models.DiscountTier{{MinQuantity: 3, Percent: 10}}},
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockProducts.On("GetProductByID", mock.Anything, 1).Return(laptop, nil).Times(1)
+		mockProducts.On("GetProductByID", mock.Anything, 2).Return(cable, nil).Times(1)
+		mockDiscounts.On("GetDiscounts", mock.Anything).Return(discounts, nil).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/pricing/quote", strings.NewReader(`{"items":[{"product_id":1,"quantity":1},{"product_id":2,"quantity":4}]}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"applied_discounts":[{"discount_id":1,"name":"20% off laptops","amount":200}]`)
+		assert.Contains(t, w.Body.String(), `"applied_discounts":[{"discount_id":2,"name":"Buy 3 get 10% off","amount":2}]`)
+		assert.Contains(t, w.Body.String(), `"subtotal":1020,"discount":202,"total":818}`)
+	})
+
+	t.Run("Missing Items", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/pricing/quote", strings.NewReader(`{"items":[]}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Invalid Quantity", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/pricing/quote", strings.NewReader(`{"items":[{"product_id":1,"quantity":0}]}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Product Not Found", func(t *testing.T) {
+		mockProducts.On("GetProductByID", mock.Anything, 9).Return(nil, pgx.ErrNoRows).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/pricing/quote", strings.NewReader(`{"items":[{"product_id":9,"quantity":1}]}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "Product with id: 9 not found")
+	})
+
+	t.Run("Draft Product", func(t *testing.T) {
+		draft := &models.Product{ID: 3, Name: "Prototype", Price: 10, Status: models.ProductStatusDraft}
+		mockProducts.On("GetProductByID", mock.Anything, 3).Return(draft, nil).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/pricing/quote", strings.NewReader(`{"items":[{"product_id":3,"quantity":1}]}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Discount Error", func(t *testing.T) {
+		mockProducts.On("GetProductByID", mock.Anything, 1).Return(laptop, nil).Times(1)
+		mockDiscounts.On("GetDiscounts", mock.Anything).Return(nil, errors.New("database error")).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/pricing/quote", strings.NewReader(`{"items":[{"product_id":1,"quantity":1}]}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
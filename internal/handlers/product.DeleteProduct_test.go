@@ -2,11 +2,13 @@ package handlers
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mariosker/products_rest_api/internal/repository"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -49,4 +51,29 @@ func TestProductHandler_DeleteProduct(t *testing.T) {
 
 		assert.Equal(t, http.StatusNoContent, w.Code)
 	})
+	t.Run("Bundle Component", func(t *testing.T) {
+		err := fmt.Errorf("product with ID 4 is a component of a bundle: %w", repository.ErrConflict)
+		mockRepo.On("DeleteProduct", mock.Anything, 4).Return(err).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("DELETE", "/products/4", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "component of a bundle")
+	})
+
+	t.Run("Cascade To Bundles", func(t *testing.T) {
+		mockBundleRepo := new(MockBundleRepository)
+		cascadeRouter := gin.Default()
+		cascadeRouter.DELETE("/products/:id", NewProductHandler(mockRepo, WithBundleCascade(mockBundleRepo)).DeleteProduct)
+		mockBundleRepo.On("DeleteProductCascade", mock.Anything, 4).Return(nil).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("DELETE", "/products/4", nil)
+		cascadeRouter.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		mockBundleRepo.AssertExpectations(t)
+	})
 }
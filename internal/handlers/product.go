@@ -2,24 +2,87 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"slices"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mariosker/products_rest_api/internal/attributes"
+	"github.com/mariosker/products_rest_api/internal/auth"
 	"github.com/mariosker/products_rest_api/internal/models"
 	"github.com/mariosker/products_rest_api/internal/repository"
+	"github.com/mariosker/products_rest_api/internal/tax"
 	"github.com/mariosker/products_rest_api/internal/utils"
 )
 
 // ProductHandler handles HTTP requests for managing products.
 type ProductHandler struct {
-	repo repository.ProductRepository
+	repo            repository.ProductRepository
+	variantRepo     repository.VariantRepository
+	translationRepo repository.TranslationRepository
+	defaultLocale   string
+	taxRepo         repository.TaxRepository
+	taxRounding     tax.RoundingMode
+	bundleRepo      repository.BundleRepository
+	relationRepo    repository.RelationRepository
+}
+
+// ProductHandlerOption configures optional dependencies of a ProductHandler.
+type ProductHandlerOption func(*ProductHandler)
+
+// WithVariantRepository enables ?expand=variants on product reads.
+func WithVariantRepository(variantRepo repository.VariantRepository) ProductHandlerOption {
+	return func(h *ProductHandler) {
+		h.variantRepo = variantRepo
+	}
+}
+
+// WithRelations enables ?expand=relations on product reads.
+func WithRelations(relationRepo repository.RelationRepository) ProductHandlerOption {
+	return func(h *ProductHandler) {
+		h.relationRepo = relationRepo
+	}
+}
+
+// WithTranslations localises product reads from ?locale= or Accept-Language, falling back to
+// the product's own name and description, which are in defaultLocale.
+func WithTranslations(translationRepo repository.TranslationRepository, defaultLocale string) ProductHandlerOption {
+	return func(h *ProductHandler) {
+		h.translationRepo = translationRepo
+		h.defaultLocale = defaultLocale
+	}
+}
+
+// WithTaxes adds the net, tax and gross amounts of product prices in the region given by ?region=,
+// rounded to cents with rounding.
+func WithTaxes(taxRepo repository.TaxRepository, rounding tax.RoundingMode) ProductHandlerOption {
+	return func(h *ProductHandler) {
+		h.taxRepo = taxRepo
+		h.taxRounding = rounding
+	}
+}
+
+// WithBundleCascade deletes products that are components of bundles by removing them from
+// their bundles first. Without it such products cannot be deleted.
+func WithBundleCascade(bundleRepo repository.BundleRepository) ProductHandlerOption {
+	return func(h *ProductHandler) {
+		h.bundleRepo = bundleRepo
+	}
 }
 
 // NewProductHandler creates a new ProductHandler with the given repository.
-func NewProductHandler(repo repository.ProductRepository) *ProductHandler {
-	return &ProductHandler{repo: repo}
+func NewProductHandler(repo repository.ProductRepository, opts ...ProductHandlerOption) *ProductHandler {
+	h := &ProductHandler{repo: repo}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
 // CreateProduct godoc
@@ -31,6 +94,8 @@ func NewProductHandler(repo repository.ProductRepository) *ProductHandler {
 // @Param product body models.CreateProductPayload true "Product Payload"
 // @Success 201 {object} models.CreateProductResponse
 // @Failure 400 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
 // @Failure 500 {object} utils.ErrorResponse
 // @Router /products [post]
 func (h *ProductHandler) CreateProduct(c *gin.Context) {
@@ -39,10 +104,31 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 		utils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
+	if product.Slug != "" && !isValidSlug(product.Slug) {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid slug: "+product.Slug)
+		return
+	}
+	if product.PublishAt != nil && product.Status != models.ProductStatusDraft {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "publish_at can only be set on drafts")
+		return
+	}
+	// Every product is created with a price, so only clients that may change prices can create
+	// products; otherwise editors could delete a product and recreate it with another price.
+	if !auth.Allowed(c, auth.ScopePriceWrite) {
+		auth.Deny(c, auth.ScopePriceWrite, "change the price of a product")
+		return
+	}
 
 	id, repoErr := h.repo.CreateProduct(c.Request.Context(), &product)
 	if repoErr != nil {
-		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to create product")
+		var invalid *attributes.ValidationError
+		if errors.Is(repoErr, repository.ErrConflict) {
+			utils.SendErrorResponse(c, http.StatusConflict, repoErr.Error())
+		} else if errors.As(repoErr, &invalid) {
+			utils.SendErrorResponse(c, http.StatusBadRequest, invalid.Error())
+		} else {
+			utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to create product")
+		}
 		return
 	}
 	response := models.CreateProductResponse{ID: id}
@@ -56,9 +142,14 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int true "Product ID"
+// @Param expand query string false "Comma-separated related resources to embed (variants, relations)"
+// @Param locale query string false "Preferred locale; overrides Accept-Language"
+// @Param Accept-Language header string false "Preferred locales"
+// @Param region query string false "Country or region code, such as DE or US-CA, to include taxes for"
 // @Success 200 {object} models.Product
 // @Failure 400 {object} utils.ErrorResponse
 // @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
 // @Router /products/{id} [get]
 func (h *ProductHandler) GetProduct(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
@@ -67,25 +158,232 @@ func (h *ProductHandler) GetProduct(c *gin.Context) {
 		return
 	}
 
+	expands := parseExpand(c)
+	for _, expand := range expands {
+		if !h.canExpand(expand) {
+			utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid expand: "+expand)
+			return
+		}
+	}
+	locales, ok := h.requestLocales(c)
+	if !ok {
+		return
+	}
+	region, ok := h.requestRegion(c)
+	if !ok {
+		return
+	}
+
 	product, err := h.repo.GetProductByID(c.Request.Context(), id)
-	if err != nil {
+	if err != nil || !canSeeProduct(c, product) {
 		utils.SendErrorResponse(c, http.StatusNotFound, "Product with id: "+strconv.Itoa(id)+" not found")
 		return
 	}
+	if !h.localize(c, locales, product) || !h.applyTax(c, region, product) {
+		return
+	}
+
+	for _, expand := range expands {
+		switch expand {
+		case "variants":
+			product.Variants, err = h.variantRepo.GetVariantsByProductID(c.Request.Context(), id)
+		case "relations":
+			product.Relations, err = h.relationRepo.GetRelations(c.Request.Context(), id, "", !canSeeUnpublished(c))
+		}
+		if err != nil {
+			utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve "+expand+" for product with id: "+strconv.Itoa(id))
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, product)
+}
+
+// canExpand reports whether the named related resource can be embedded in product reads.
+func (h *ProductHandler) canExpand(expand string) bool {
+	switch expand {
+	case "variants":
+		return h.variantRepo != nil
+	case "relations":
+		return h.relationRepo != nil
+	default:
+		return false
+	}
+}
+
+// requestLocales returns the locales to localise products in when translations are enabled,
+// sending a 400 response and returning false if ?locale= is invalid.
+func (h *ProductHandler) requestLocales(c *gin.Context) ([]string, bool) {
+	if h.translationRepo == nil {
+		return nil, true
+	}
+	locales, err := preferredLocales(c, h.defaultLocale)
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid locale: "+c.Query("locale"))
+		return nil, false
+	}
+	return locales, true
+}
+
+// localize replaces the name and description of products with their best translation into locales.
+// A translation without a description keeps the product's own description.
+// It sends a 500 response and returns false if translations cannot be retrieved.
+func (h *ProductHandler) localize(c *gin.Context, locales []string, products ...*models.Product) bool {
+	if h.translationRepo == nil {
+		return true
+	}
+	c.Header("Vary", "Accept-Language")
+
+	translations := map[int]*models.ProductTranslation{}
+	if len(locales) > 0 {
+		ids := make([]int, len(products))
+		for i, product := range products {
+			ids[i] = product.ID
+		}
+		var err error
+		translations, err = h.translationRepo.GetBestTranslations(c.Request.Context(), ids, locales)
+		if err != nil {
+			utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve translations")
+			return false
+		}
+	}
+
+	for _, product := range products {
+		product.Locale = h.defaultLocale
+		if t, ok := translations[product.ID]; ok {
+			product.Name, product.Locale = t.Name, t.Locale
+			if t.Description != "" {
+				product.Description = t.Description
+			}
+		}
+	}
+	if len(products) == 1 {
+		c.Header("Content-Language", products[0].Locale)
+	}
+	return true
+}
+
+// isValidSlug reports whether slug is already in the canonical form produced by utils.Slugify.
+func isValidSlug(slug string) bool {
+	return utils.Slugify(slug) == slug
+}
+
+// parseExpand returns the comma-separated resource names of the expand query parameter.
+func parseExpand(c *gin.Context) []string {
+	var expands []string
+	for _, expand := range strings.Split(c.Query("expand"), ",") {
+		if expand = strings.TrimSpace(expand); expand != "" {
+			expands = append(expands, expand)
+		}
+	}
+	return expands
+}
+
+// GetProductBySKU godoc
+// @Summary Get a product by SKU
+// @Description Retrieve a product by its stock keeping unit
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param sku path string true "Product SKU"
+// @Param locale query string false "Preferred locale; overrides Accept-Language"
+// @Success 200 {object} models.Product
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /products/by-sku/{sku} [get]
+func (h *ProductHandler) GetProductBySKU(c *gin.Context) {
+	product, err := h.repo.GetProductBySKU(c.Request.Context(), c.Param("sku"))
+	h.sendProductLookup(c, product, err, "Product with sku: "+c.Param("sku")+" not found")
+}
+
+// GetProductBySlug godoc
+// @Summary Get a product by slug
+// @Description Retrieve a product by its URL slug
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param slug path string true "Product slug"
+// @Param locale query string false "Preferred locale; overrides Accept-Language"
+// @Success 200 {object} models.Product
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /products/by-slug/{slug} [get]
+func (h *ProductHandler) GetProductBySlug(c *gin.Context) {
+	product, err := h.repo.GetProductBySlug(c.Request.Context(), c.Param("slug"))
+	h.sendProductLookup(c, product, err, "Product with slug: "+c.Param("slug")+" not found")
+}
+
+// GetProductByGTIN godoc
+// @Summary Get a product by barcode
+// @Description Retrieve a product by its GTIN. UPC-A and EAN-13 forms of the same code are equivalent.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param code path string true "GTIN-8, UPC-A, EAN-13 or GTIN-14"
+// @Param locale query string false "Preferred locale; overrides Accept-Language"
+// @Success 200 {object} models.Product
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /products/by-gtin/{code} [get]
+func (h *ProductHandler) GetProductByGTIN(c *gin.Context) {
+	code := c.Param("code")
+	if !utils.IsValidGTIN(code) {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid GTIN: "+code)
+		return
+	}
+
+	product, err := h.repo.GetProductByGTIN(c.Request.Context(), code)
+	h.sendProductLookup(c, product, err, "Product with gtin: "+code+" not found")
+}
+
+// sendProductLookup responds with the product found by an alternate key lookup.
+func (h *ProductHandler) sendProductLookup(c *gin.Context, product *models.Product, err error, notFound string) {
+	locales, ok := h.requestLocales(c)
+	if !ok {
+		return
+	}
+	region, ok := h.requestRegion(c)
+	if !ok {
+		return
+	}
+	if err == nil && !canSeeProduct(c, product) {
+		err = repository.ErrNotFound
+	}
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			utils.SendErrorResponse(c, http.StatusNotFound, notFound)
+		} else {
+			utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve product")
+		}
+		return
+	}
+	if !h.localize(c, locales, product) || !h.applyTax(c, region, product) {
+		return
+	}
 
 	c.JSON(http.StatusOK, product)
 }
 
 // GetProducts godoc
 // @Summary Get a list of products
-// @Description Retrieve a list of products with pagination
+// @Description Retrieve a list of products with pagination. Products can be filtered by attribute with
+// @Description attr.<name>=<value> for equality and attr.<name>_lt, _lte, _gt or _gte for numeric ranges.
 // @Tags products
 // @Accept json
 // @Produce json
 // @Param limit query int false "Limit"
 // @Param offset query int false "Offset"
+// @Param status query string false "Comma-separated statuses to list, or all; anything but published needs products:write" default(published)
+// @Param sort query string false "Order by average rating, lowest (rating) or highest (-rating) first" Enums(rating, -rating)
+// @Param locale query string false "Preferred locale; overrides Accept-Language"
+// @Param Accept-Language header string false "Preferred locales"
+// @Param region query string false "Country or region code, such as DE or US-CA, to include taxes for"
 // @Success 200 {array} models.Product
 // @Failure 400 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
 // @Failure 500 {object} utils.ErrorResponse
 // @Router /products [get]
 func (h *ProductHandler) GetProducts(c *gin.Context) {
@@ -109,7 +407,38 @@ func (h *ProductHandler) GetProducts(c *gin.Context) {
 		return
 	}
 
-	products, err := h.repo.GetProducts(c.Request.Context(), limit, offset)
+	attributeFilters, err := parseAttributeFilters(c)
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	statuses, err := parseStatusFilter(c.DefaultQuery("status", models.ProductStatusPublished))
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !canSeeUnpublished(c) && !slices.Equal(statuses, []string{models.ProductStatusPublished}) {
+		auth.Deny(c, auth.ScopeProductsWrite, "list unpublished products")
+		return
+	}
+	order := c.Query("sort")
+	switch order {
+	case "", models.ProductSortRating, models.ProductSortRatingDesc:
+	default:
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid sort: "+order)
+		return
+	}
+	locales, ok := h.requestLocales(c)
+	if !ok {
+		return
+	}
+	region, ok := h.requestRegion(c)
+	if !ok {
+		return
+	}
+
+	filter := models.ProductFilter{Attributes: attributeFilters, Statuses: statuses, Sort: order}
+	products, err := h.repo.GetProducts(c.Request.Context(), limit, offset, filter)
 	if err != nil {
 		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve products")
 		return
@@ -118,10 +447,118 @@ func (h *ProductHandler) GetProducts(c *gin.Context) {
 	if products == nil {
 		products = []*models.Product{}
 	}
+	if !h.localize(c, locales, products...) || !h.applyTax(c, region, products...) {
+		return
+	}
 
 	c.JSON(http.StatusOK, products)
 }
 
+// attributeFilterPrefix marks query parameters that filter products by attribute.
+const attributeFilterPrefix = "attr."
+
+// attributeRangeOps are the suffixes of attribute filters that compare numerically.
+var attributeRangeOps = []string{models.AttributeOpLte, models.AttributeOpLt, models.AttributeOpGte, models.AttributeOpGt}
+
+// parseAttributeFilters parses attr.<name>[_op]=<value> query parameters into attribute filters.
+func parseAttributeFilters(c *gin.Context) ([]models.AttributeFilter, error) {
+	query := c.Request.URL.Query()
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		if strings.HasPrefix(key, attributeFilterPrefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var filters []models.AttributeFilter
+	for _, key := range keys {
+		filter := models.AttributeFilter{Name: strings.TrimPrefix(key, attributeFilterPrefix), Op: models.AttributeOpEq, Value: query.Get(key)}
+		for _, op := range attributeRangeOps {
+			if name, ok := strings.CutSuffix(filter.Name, "_"+op); ok {
+				filter.Name, filter.Op = name, op
+				break
+			}
+		}
+		if filter.Name == "" {
+			return nil, fmt.Errorf("Invalid attribute filter: %s", key)
+		}
+		if filter.Op != models.AttributeOpEq {
+			if _, err := strconv.ParseFloat(filter.Value, 64); err != nil {
+				return nil, fmt.Errorf("Invalid attribute filter value: %s=%s", key, filter.Value)
+			}
+		}
+		filters = append(filters, filter)
+	}
+	return filters, nil
+}
+
+// parseStatusFilter parses a comma-separated list of product statuses, where "all" means any status.
+func parseStatusFilter(value string) ([]string, error) {
+	if value == "all" {
+		return nil, nil
+	}
+	var statuses []string
+	for _, status := range strings.Split(value, ",") {
+		switch status = strings.TrimSpace(status); status {
+		case models.ProductStatusDraft, models.ProductStatusPublished, models.ProductStatusArchived:
+			statuses = append(statuses, status)
+		default:
+			return nil, fmt.Errorf("Invalid status: %s", status)
+		}
+	}
+	return statuses, nil
+}
+
+// canSeeUnpublished reports whether the client may read draft and archived products. Unlike other
+// permissions it is never granted to anonymous clients, so a public catalogue, served with
+// authentication disabled or below AuthPublicPaths, only shows published products.
+func canSeeUnpublished(c *gin.Context) bool {
+	principal := auth.PrincipalFrom(c)
+	return principal != nil && principal.HasScope(auth.ScopeProductsWrite)
+}
+
+// canSeeProduct reports whether the client may read product, treating it as missing otherwise.
+func canSeeProduct(c *gin.Context, product *models.Product) bool {
+	return product.Status == models.ProductStatusPublished || canSeeUnpublished(c)
+}
+
+// HideUnpublishedProducts answers reads below /products/:id with 404 Not Found when the client may
+// not see the product, so the variants, images, translations, price schedules, bundle and reviews
+// of a draft or archived product stay hidden like the product itself. It must run after the client
+// is authenticated and its tenant resolved. Changes are left to the handlers and their permissions.
+func HideUnpublishedProducts(repo repository.ProductRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		read := c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead
+		if !read || !strings.HasPrefix(c.FullPath(), "/products/:id/") || canSeeUnpublished(c) {
+			c.Next()
+			return
+		}
+		// Invalid IDs and missing products are reported by the handler.
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.Next()
+			return
+		}
+		product, err := repo.GetProductByID(c.Request.Context(), id)
+		if errors.Is(err, repository.ErrNotFound) {
+			c.Next()
+			return
+		}
+		if err != nil {
+			utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve product")
+			c.Abort()
+			return
+		}
+		if !canSeeProduct(c, product) {
+			utils.SendErrorResponse(c, http.StatusNotFound, "Product with id: "+strconv.Itoa(id)+" not found")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
 // UpdateProduct godoc
 // @Summary Update a product by ID
 // @Description Update an existing product by its ID
@@ -132,7 +569,9 @@ func (h *ProductHandler) GetProducts(c *gin.Context) {
 // @Param product body models.UpdateProductPayload true "Product Payload"
 // @Success 200 {object} models.Product
 // @Failure 400 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
 // @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
 // @Failure 500 {object} utils.ErrorResponse
 // @Router /products/{id} [put]
 func (h *ProductHandler) UpdateProduct(c *gin.Context) {
@@ -147,17 +586,27 @@ func (h *ProductHandler) UpdateProduct(c *gin.Context) {
 		utils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
-
-	err = h.repo.UpdateProduct(c.Request.Context(), id, &payload)
+	if payload.Slug != nil && !isValidSlug(*payload.Slug) {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid slug: "+*payload.Slug)
+		return
+	}
+	// Editors may update everything but the price, which they must send back unchanged.
+	product, err := h.repo.UpdateProduct(c.Request.Context(), id, &payload, auth.Allowed(c, auth.ScopePriceWrite))
 	if err != nil {
-		if err.Error() == fmt.Sprintf("product with ID %d not found", id) {
+		var invalid *attributes.ValidationError
+		if errors.Is(err, repository.ErrNotFound) {
 			utils.SendErrorResponse(c, http.StatusNotFound, "Product not found")
+		} else if errors.Is(err, repository.ErrPriceChangeNotAllowed) {
+			auth.Deny(c, auth.ScopePriceWrite, "change the price of a product")
+		} else if errors.Is(err, repository.ErrConflict) {
+			utils.SendErrorResponse(c, http.StatusConflict, err.Error())
+		} else if errors.As(err, &invalid) {
+			utils.SendErrorResponse(c, http.StatusBadRequest, invalid.Error())
 		} else {
 			utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to update product with ID: "+strconv.Itoa(id))
 		}
 		return
 	}
-	product := models.Product{ID: id, Name: payload.Name, Price: payload.Price}
 	c.JSON(http.StatusOK, product)
 }
 
@@ -170,6 +619,7 @@ func (h *ProductHandler) UpdateProduct(c *gin.Context) {
 // @Param id path int true "Product ID"
 // @Success 204 {} {}
 // @Failure 400 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
 // @Failure 500 {object} utils.ErrorResponse
 // @Router /products/{id} [delete]
 func (h *ProductHandler) DeleteProduct(c *gin.Context) {
@@ -179,7 +629,16 @@ func (h *ProductHandler) DeleteProduct(c *gin.Context) {
 		return
 	}
 
-	deleteErr := h.repo.DeleteProduct(c.Request.Context(), id)
+	var deleteErr error
+	if h.bundleRepo != nil {
+		deleteErr = h.bundleRepo.DeleteProductCascade(c.Request.Context(), id)
+	} else {
+		deleteErr = h.repo.DeleteProduct(c.Request.Context(), id)
+	}
+	if errors.Is(deleteErr, repository.ErrConflict) {
+		utils.SendErrorResponse(c, http.StatusConflict, deleteErr.Error())
+		return
+	}
 	if deleteErr != nil {
 		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to delete product with id: "+strconv.Itoa(id))
 		return
@@ -187,3 +646,92 @@ func (h *ProductHandler) DeleteProduct(c *gin.Context) {
 
 	c.Status(http.StatusNoContent)
 }
+
+// PublishProduct godoc
+// @Summary Publish a product
+// @Description Publish a draft now, or schedule it to be published at publish_at
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param schedule body models.PublishProductPayload false "Publish Payload"
+// @Success 200 {object} models.Product
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /products/{id}/publish [post]
+func (h *ProductHandler) PublishProduct(c *gin.Context) {
+	// The body is optional: without one the product is published immediately.
+	var payload models.PublishProductPayload
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&payload); err != nil && !errors.Is(err, io.EOF) {
+			utils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	publishAt := payload.PublishAt
+	if publishAt != nil && !publishAt.After(time.Now()) {
+		publishAt = nil
+	}
+	h.changeStatus(c, models.ProductStatusPublished, publishAt)
+}
+
+// ArchiveProduct godoc
+// @Summary Archive a product
+// @Description Take a published product off sale
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {object} models.Product
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /products/{id}/archive [post]
+func (h *ProductHandler) ArchiveProduct(c *gin.Context) {
+	h.changeStatus(c, models.ProductStatusArchived, nil)
+}
+
+// UnarchiveProduct godoc
+// @Summary Unarchive a product
+// @Description Move an archived product back to draft
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {object} models.Product
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /products/{id}/unarchive [post]
+func (h *ProductHandler) UnarchiveProduct(c *gin.Context) {
+	h.changeStatus(c, models.ProductStatusDraft, nil)
+}
+
+// changeStatus moves the product in the path to status and responds with the updated product.
+func (h *ProductHandler) changeStatus(c *gin.Context, status string, publishAt *time.Time) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid ID: "+c.Param("id"))
+		return
+	}
+
+	product, err := h.repo.ChangeProductStatus(c.Request.Context(), id, status, publishAt)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			utils.SendErrorResponse(c, http.StatusNotFound, "Product with id: "+strconv.Itoa(id)+" not found")
+		case errors.Is(err, repository.ErrInvalidStatusTransition):
+			utils.SendErrorResponse(c, http.StatusConflict, err.Error())
+		default:
+			utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to change status of product with id: "+strconv.Itoa(id))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, product)
+}
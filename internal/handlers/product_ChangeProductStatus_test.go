@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/mariosker/products_rest_api/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestProductHandler_ChangeProductStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.Default()
+	mockRepo := new(MockProductRepository)
+	handler := NewProductHandler(mockRepo)
+
+	router.POST("/products/:id/publish", handler.PublishProduct)
+	router.POST("/products/:id/archive", handler.ArchiveProduct)
+	router.POST("/products/:id/unarchive", handler.UnarchiveProduct)
+
+	t.Run("Publish Now", func(t *testing.T) {
+		product := &models.Product{ID: 1, Name: "Product 1", Status: models.ProductStatusPublished}
+		mockRepo.On("ChangeProductStatus", mock.Anything, 1, models.ProductStatusPublished, (*time.Time)(nil)).Return(product, nil).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/products/1/publish", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"status":"published"`)
+	})
+
+	t.Run("Schedule Publish", func(t *testing.T) {
+		publishAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+		product := &models.Product{ID: 2, Name: "Product 2", Status: models.ProductStatusDraft, PublishAt: &publishAt}
+		mockRepo.On("ChangeProductStatus", mock.Anything, 2, models.ProductStatusPublished, mock.MatchedBy(func(at *time.Time) bool {
+			return at != nil && at.Equal(publishAt)
+		})).Return(product, nil).Times(1)
+
+		w := httptest.NewRecorder()
+		body := fmt.Sprintf(`{"publish_at":%q}`, publishAt.Format(time.RFC3339))
+		req, _ := http.NewRequest("POST", "/products/2/publish", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"status":"draft"`)
+		assert.Contains(t, w.Body.String(), `"publish_at"`)
+	})
+
+	t.Run("Publish At In The Past", func(t *testing.T) {
+		product := &models.Product{ID: 3, Name: "Product 3", Status: models.ProductStatusPublished}
+		mockRepo.On("ChangeProductStatus", mock.Anything, 3, models.ProductStatusPublished, (*time.Time)(nil)).Return(product, nil).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/products/3/publish", bytes.NewBufferString(`{"publish_at":"2020-01-01T00:00:00Z"}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Invalid Transition", func(t *testing.T) {
+		err := fmt.Errorf("cannot change product status from draft to archived: %w", repository.ErrInvalidStatusTransition)
+		mockRepo.On("ChangeProductStatus", mock.Anything, 4, models.ProductStatusArchived, (*time.Time)(nil)).Return(nil, err).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/products/4/archive", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "cannot change product status from draft to archived")
+	})
+
+	t.Run("Unarchive", func(t *testing.T) {
+		product := &models.Product{ID: 5, Name: "Product 5", Status: models.ProductStatusDraft}
+		mockRepo.On("ChangeProductStatus", mock.Anything, 5, models.ProductStatusDraft, (*time.Time)(nil)).Return(product, nil).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/products/5/unarchive", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Product Not Found", func(t *testing.T) {
+		mockRepo.On("ChangeProductStatus", mock.Anything, 6, models.ProductStatusArchived, (*time.Time)(nil)).Return(nil, repository.ErrNotFound).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/products/6/archive", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Internal Server Error", func(t *testing.T) {
+		mockRepo.On("ChangeProductStatus", mock.Anything, 7, models.ProductStatusArchived, (*time.Time)(nil)).Return(nil, errors.New("database error")).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/products/7/archive", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+
+	t.Run("Invalid ID", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/products/invalid/archive", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	mockRepo.AssertExpectations(t)
+}
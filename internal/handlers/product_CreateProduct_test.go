@@ -2,13 +2,17 @@ package handlers
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mariosker/products_rest_api/internal/attributes"
+	"github.com/mariosker/products_rest_api/internal/auth"
 	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/mariosker/products_rest_api/internal/repository"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -33,6 +37,21 @@ func TestProductHandler_CreateProduct(t *testing.T) {
 		assert.Contains(t, w.Body.String(), `{"id":1}`)
 	})
 
+	t.Run("Price Without Permission", func(t *testing.T) {
+		editor := gin.Default()
+		editor.Use(func(c *gin.Context) {
+			auth.SetPrincipal(c, &auth.Principal{Subject: "user-7", Scopes: []string{auth.ScopeProductsRead, auth.ScopeProductsWrite}})
+		})
+		editor.POST("/products", handler.CreateProduct)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/products", strings.NewReader(`{"name":"Test Product","price":10.0}`))
+		editor.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), "products:price:write permission is required to change the price")
+	})
+
 	t.Run("Invalid JSON", func(t *testing.T) {
 		w := httptest.NewRecorder()
 		req, _ := http.NewRequest("POST", "/products", strings.NewReader(`{"name":"Test Product", "price":"invalid"}`))
@@ -80,4 +99,63 @@ func TestProductHandler_CreateProduct(t *testing.T) {
 		assert.Contains(t, w.Body.String(), "Failed to create product")
 	})
 
+	t.Run("Duplicate SKU", func(t *testing.T) {
+		errRepo := fmt.Errorf("product with SKU %q already exists: %w", "TP-001", repository.ErrConflict)
+		mockRepo.On("CreateProduct", mock.Anything, &models.CreateProductPayload{Name: "Test Product", Price: 10.0, SKU: "TP-001"}).Return(-1, errRepo).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/products", strings.NewReader(`{"name":"Test Product","price":10.0,"sku":"TP-001"}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "already exists")
+	})
+
+	t.Run("Valid GTIN", func(t *testing.T) {
+		mockRepo.On("CreateProduct", mock.Anything, &models.CreateProductPayload{Name: "Soda", Price: 1.5, GTIN: "036000291452"}).Return(2, nil).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/products", strings.NewReader(`{"name":"Soda","price":1.5,"gtin":"036000291452"}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+
+	t.Run("Invalid GTIN Check Digit", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/products", strings.NewReader(`{"name":"Soda","price":1.5,"gtin":"036000291453"}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "gtin")
+	})
+
+	t.Run("Invalid GTIN Length", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/products", strings.NewReader(`{"name":"Soda","price":1.5,"gtin":"1234567"}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Invalid Slug", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/products", strings.NewReader(`{"name":"Test Product","price":10.0,"slug":"Not A Slug"}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Invalid Attributes", func(t *testing.T) {
+		payload := &models.CreateProductPayload{Name: "Laptop", Price: 999.0, Category: "laptops", Attributes: map[string]any{"ram_gb": "lots"}}
+		errRepo := &attributes.ValidationError{Problems: []string{"ram_gb must be a number"}}
+		mockRepo.On("CreateProduct", mock.Anything, payload).Return(-1, errRepo).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/products", strings.NewReader(`{"name":"Laptop","price":999.0,"category":"laptops","attributes":{"ram_gb":"lots"}}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "invalid attributes: ram_gb must be a number")
+	})
 }
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/mariosker/products_rest_api/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestProductHandler_GetProductBySKU(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.Default()
+	mockRepo := new(MockProductRepository)
+	handler := NewProductHandler(mockRepo)
+
+	router.GET("/products/by-sku/:sku", handler.GetProductBySKU)
+
+	t.Run("Success", func(t *testing.T) {
+		mockProduct := &models.Product{ID: 1, Name: "Test Product", Status: models.ProductStatusPublished, Price: 10.0, SKU: "TP-001", Slug: "test-product"}
+		mockRepo.On("GetProductBySKU", mock.Anything, "TP-001").Return(mockProduct, nil).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/products/by-sku/TP-001", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"sku":"TP-001"`)
+	})
+
+	t.Run("Product Not Found", func(t *testing.T) {
+		mockRepo.On("GetProductBySKU", mock.Anything, "NOPE").Return(nil, fmt.Errorf("product with SKU %q: %w", "NOPE", repository.ErrNotFound)).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/products/by-sku/NOPE", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "Product with sku: NOPE not found")
+	})
+
+	t.Run("Repository Error", func(t *testing.T) {
+		mockRepo.On("GetProductBySKU", mock.Anything, "ERR").Return(nil, errors.New("database error")).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/products/by-sku/ERR", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+func TestProductHandler_GetProductBySlug(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.Default()
+	mockRepo := new(MockProductRepository)
+	handler := NewProductHandler(mockRepo)
+
+	router.GET("/products/by-slug/:slug", handler.GetProductBySlug)
+
+	t.Run("Success", func(t *testing.T) {
+		mockProduct := &models.Product{ID: 1, Name: "Test Product", Status: models.ProductStatusPublished, Price: 10.0, Slug: "test-product"}
+		mockRepo.On("GetProductBySlug", mock.Anything, "test-product").Return(mockProduct, nil).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/products/by-slug/test-product", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"slug":"test-product"`)
+	})
+
+	t.Run("Product Not Found", func(t *testing.T) {
+		mockRepo.On("GetProductBySlug", mock.Anything, "missing").Return(nil, repository.ErrNotFound).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/products/by-slug/missing", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestProductHandler_GetProductByGTIN(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.Default()
+	mockRepo := new(MockProductRepository)
+	handler := NewProductHandler(mockRepo)
+
+	router.GET("/products/by-gtin/:code", handler.GetProductByGTIN)
+
+	t.Run("Success", func(t *testing.T) {
+		mockProduct := &models.Product{ID: 1, Name: "Soda", Status: models.ProductStatusPublished, Price: 1.5, GTIN: "00036000291452"}
+		mockRepo.On("GetProductByGTIN", mock.Anything, "036000291452").Return(mockProduct, nil).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/products/by-gtin/036000291452", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"gtin":"00036000291452"`)
+	})
+
+	t.Run("Invalid Check Digit", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/products/by-gtin/036000291453", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockRepo.AssertNotCalled(t, "GetProductByGTIN", mock.Anything, "036000291453")
+	})
+
+	t.Run("Product Not Found", func(t *testing.T) {
+		mockRepo.On("GetProductByGTIN", mock.Anything, "4006381333931").Return(nil, repository.ErrNotFound).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/products/by-gtin/4006381333931", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
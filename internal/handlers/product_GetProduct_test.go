@@ -8,6 +8,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/mariosker/products_rest_api/internal/tax"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -22,7 +23,7 @@ func TestProductHandler_GetProductByID(t *testing.T) {
 	router.GET("/products/:id", handler.GetProduct)
 
 	t.Run("Success", func(t *testing.T) {
-		mockProduct := &models.Product{ID: 1, Name: "Test Product", Price: 10.0}
+		mockProduct := &models.Product{ID: 1, Name: "Test Product", Status: models.ProductStatusPublished, Price: 10.0}
 		mockRepo.On("GetProductByID", mock.Anything, 1).Return(mockProduct, nil).Times(1)
 
 		w := httptest.NewRecorder()
@@ -53,3 +54,194 @@ func TestProductHandler_GetProductByID(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 	})
 }
+
+func TestProductHandler_GetProductByID_Expand(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.Default()
+	mockRepo := new(MockProductRepository)
+	mockVariantRepo := new(MockVariantRepository)
+	mockRelationRepo := new(MockRelationRepository)
+	handler := NewProductHandler(mockRepo, WithVariantRepository(mockVariantRepo), WithRelations(mockRelationRepo))
+
+	router.GET("/products/:id", handler.GetProduct)
+
+	t.Run("Expand Variants", func(t *testing.T) {
+		mockProduct := &models.Product{ID: 1, Name: "T-Shirt", Status: models.ProductStatusPublished, Price: 10.0}
+		mockVariants := []*models.ProductVariant{
+			{ID: 4, ProductID: 1, SKU: "TS-M-RED", Options: map[string]string{"size": "M", "colour": "red"}, EffectivePrice: 10.0, Stock: 3},
+		}
+		mockRepo.On("GetProductByID", mock.Anything, 1).Return(mockProduct, nil).Times(1)
+		mockVariantRepo.On("GetVariantsByProductID", mock.Anything, 1).Return(mockVariants, nil).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/products/1?expand=variants", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"variants":[{"id":4,"product_id":1,"sku":"TS-M-RED"`)
+	})
+
+	t.Run("Expand Relations", func(t *testing.T) {
+		mockProduct := &models.Product{ID: 1, Name: "Camera", Status: models.ProductStatusPublished, Price: 400.0}
+		mockRelations := []*models.ProductRelation{
+			{Type: models.RelationTypeAccessory, ProductID: 5, Name: "Lens", Slug: "lens", Position: 0},
+		}
+		mockRepo.On("GetProductByID", mock.Anything, 1).Return(mockProduct, nil).Times(1)
+		mockRelationRepo.On("GetRelations", mock.Anything, 1, "", true).Return(mockRelations, nil).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/products/1?expand=relations", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"relations":[{"type":"accessory","product_id":5,"name":"Lens","slug":"lens","position":0}]`)
+	})
+
+	t.Run("Without Expand", func(t *testing.T) {
+		mockProduct := &models.Product{ID: 2, Name: "Mug", Status: models.ProductStatusPublished, Price: 5.0}
+		mockRepo.On("GetProductByID", mock.Anything, 2).Return(mockProduct, nil).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/products/2", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, w.Body.String(), "variants")
+		mockVariantRepo.AssertNotCalled(t, "GetVariantsByProductID", mock.Anything, 2)
+	})
+
+	t.Run("Unknown Expand", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/products/1?expand=unknown", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "Invalid expand: unknown")
+	})
+}
+
+func TestProductHandler_GetProductByID_Localized(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.Default()
+	mockRepo := new(MockProductRepository)
+	mockTranslationRepo := new(MockTranslationRepository)
+	handler := NewProductHandler(mockRepo, WithTranslations(mockTranslationRepo, "en"))
+
+	router.GET("/products/:id", handler.GetProduct)
+
+	t.Run("Accept-Language", func(t *testing.T) {
+		mockProduct := &models.Product{ID: 1, Name: "Mug", Status: models.ProductStatusPublished, Description: "A ceramic mug", Price: 5.0}
+		translations := map[int]*models.ProductTranslation{1: {ProductID: 1, Locale: "de", Name: "Becher"}}
+		mockRepo.On("GetProductByID", mock.Anything, 1).Return(mockProduct, nil).Times(1)
+		mockTranslationRepo.On("GetBestTranslations", mock.Anything, []int{1}, []string{"de-AT", "de"}).Return(translations, nil).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/products/1", nil)
+		req.Header.Set("Accept-Language", "de-AT,en;q=0.5")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"name":"Becher","description":"A ceramic mug"`)
+		assert.Contains(t, w.Body.String(), `"locale":"de"`)
+		assert.Equal(t, "de", w.Header().Get("Content-Language"))
+		assert.Equal(t, "Accept-Language", w.Header().Get("Vary"))
+	})
+
+	t.Run("Default Locale", func(t *testing.T) {
+		mockProduct := &models.Product{ID: 2, Name: "Plate", Status: models.ProductStatusPublished, Price: 8.0}
+		mockRepo.On("GetProductByID", mock.Anything, 2).Return(mockProduct, nil).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/products/2?locale=en", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"name":"Plate"`)
+		assert.Equal(t, "en", w.Header().Get("Content-Language"))
+		mockTranslationRepo.AssertNotCalled(t, "GetBestTranslations", mock.Anything, []int{2}, mock.Anything)
+	})
+
+	t.Run("Invalid Locale", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/products/1?locale=x_invalid!", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestProductHandler_GetProductByID_Taxed(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.Default()
+	mockRepo := new(MockProductRepository)
+	mockTaxRepo := new(MockTaxRepository)
+	handler := NewProductHandler(mockRepo, WithTaxes(mockTaxRepo, tax.RoundHalfUp))
+
+	router.GET("/products/:id", handler.GetProduct)
+
+	t.Run("Gross Region", func(t *testing.T) {
+		mockProduct := &models.Product{ID: 1, Name: "Mug", Status: models.ProductStatusPublished, Price: 10.0, TaxClass: "standard"}
+		rates := []*models.TaxRate{{Country: "DE", TaxClass: "standard", Rate: 19, PricesIncludeTax: true}}
+		mockRepo.On("GetProductByID", mock.Anything, 1).Return(mockProduct, nil).Times(1)
+		mockTaxRepo.On("GetTaxRatesForRegion", mock.Anything, "DE", "").Return(rates, nil).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/products/1?region=de", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"tax":{"region":"DE","tax_class":"standard","rate":19,"net":10,"tax":1.9,"gross":11.9,"display_price":11.9}`)
+	})
+
+	t.Run("Net Region", func(t *testing.T) {
+		mockProduct := &models.Product{ID: 2, Name: "Plate", Status: models.ProductStatusPublished, Price: 12.5, TaxClass: "standard"}
+		rates := []*models.TaxRate{{Country: "US", Region: "CA", TaxClass: "standard", Rate: 7.25}}
+		mockRepo.On("GetProductByID", mock.Anything, 2).Return(mockProduct, nil).Times(1)
+		mockTaxRepo.On("GetTaxRatesForRegion", mock.Anything, "US", "CA").Return(rates, nil).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/products/2?region=US-CA", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"net":12.5,"tax":0.91,"gross":13.41,"display_price":12.5`)
+	})
+
+	t.Run("Class Without Rate Is Zero-Rated", func(t *testing.T) {
+		mockProduct := &models.Product{ID: 3, Name: "Book", Status: models.ProductStatusPublished, Price: 20.0, TaxClass: "books"}
+		rates := []*models.TaxRate{{Country: "GB", TaxClass: "standard", Rate: 20, PricesIncludeTax: true}}
+		mockRepo.On("GetProductByID", mock.Anything, 3).Return(mockProduct, nil).Times(1)
+		mockTaxRepo.On("GetTaxRatesForRegion", mock.Anything, "GB", "").Return(rates, nil).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/products/3?region=GB", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"rate":0,"net":20,"tax":0,"gross":20,"display_price":20`)
+	})
+
+	t.Run("Unknown Region", func(t *testing.T) {
+		mockProduct := &models.Product{ID: 4, Name: "Bowl", Status: models.ProductStatusPublished, Price: 6.0, TaxClass: "standard"}
+		mockRepo.On("GetProductByID", mock.Anything, 4).Return(mockProduct, nil).Times(1)
+		mockTaxRepo.On("GetTaxRatesForRegion", mock.Anything, "JP", "").Return([]*models.TaxRate{}, nil).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/products/4?region=JP", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "No tax rates for region: JP")
+	})
+
+	t.Run("Invalid Region", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/products/4?region=Japan", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mariosker/products_rest_api/internal/auth"
 	"github.com/mariosker/products_rest_api/internal/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -20,13 +21,20 @@ func TestProductHandler_GetProducts(t *testing.T) {
 	handler := NewProductHandler(mockRepo)
 
 	router.GET("/products", handler.GetProducts)
+	editor := gin.Default()
+	editor.Use(func(c *gin.Context) {
+		auth.SetPrincipal(c, &auth.Principal{Subject: "user-7", Scopes: []string{auth.ScopeProductsRead, auth.ScopeProductsWrite}})
+	})
+	editor.GET("/products", handler.GetProducts)
+
+	published := models.ProductFilter{Statuses: []string{models.ProductStatusPublished}}
 
 	t.Run("Success", func(t *testing.T) {
 		mockProducts := []*models.Product{
 			{ID: 1, Name: "Product 1", Price: 10.0},
 			{ID: 2, Name: "Product 2", Price: 20.0},
 		}
-		mockRepo.On("GetProducts", mock.Anything, 10, 0).Return(mockProducts, nil).Times(1)
+		mockRepo.On("GetProducts", mock.Anything, 10, 0, published).Return(mockProducts, nil).Times(1)
 
 		w := httptest.NewRecorder()
 		req, _ := http.NewRequest("GET", "/products?limit=10&offset=0", nil)
@@ -43,7 +51,7 @@ func TestProductHandler_GetProducts(t *testing.T) {
 			{ID: 4, Name: "Product 4", Price: 40.0},
 		}
 		// First call with limit=2 and offset=0
-		mockRepo.On("GetProducts", mock.Anything, 2, 0).Return(mockProducts[:2], nil).Times(1)
+		mockRepo.On("GetProducts", mock.Anything, 2, 0, published).Return(mockProducts[:2], nil).Times(1)
 
 		w := httptest.NewRecorder()
 		req, _ := http.NewRequest("GET", "/products?limit=2&offset=0", nil)
@@ -53,7 +61,7 @@ func TestProductHandler_GetProducts(t *testing.T) {
 		assert.JSONEq(t, `[{"id":1,"name":"Product 1","price":10},{"id":2,"name":"Product 2","price":20}]`, w.Body.String())
 
 		// Second call with limit=2 and offset=2
-		mockRepo.On("GetProducts", mock.Anything, 2, 2).Return(mockProducts[2:], nil).Times(1)
+		mockRepo.On("GetProducts", mock.Anything, 2, 2, published).Return(mockProducts[2:], nil).Times(1)
 
 		w = httptest.NewRecorder()
 		req, _ = http.NewRequest("GET", "/products?limit=2&offset=2", nil)
@@ -64,7 +72,7 @@ func TestProductHandler_GetProducts(t *testing.T) {
 	})
 
 	t.Run("Internal Server Error", func(t *testing.T) {
-		mockRepo.On("GetProducts", mock.Anything, 10, 0).Return(nil, errors.New("database error")).Times(1)
+		mockRepo.On("GetProducts", mock.Anything, 10, 0, published).Return(nil, errors.New("database error")).Times(1)
 
 		w := httptest.NewRecorder()
 		req, _ := http.NewRequest("GET", "/products?limit=10&offset=0", nil)
@@ -100,4 +108,86 @@ func TestProductHandler_GetProducts(t *testing.T) {
 		router.ServeHTTP(w, req)
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 	})
+
+	t.Run("Attribute Filters", func(t *testing.T) {
+		mockProducts := []*models.Product{
+			{ID: 1, Name: "Laptop", Price: 999.0, Category: "laptops", Attributes: map[string]any{"ram_gb": 16.0, "colour": "silver"}},
+		}
+		filter := models.ProductFilter{Attributes: []models.AttributeFilter{
+			{Name: "colour", Op: models.AttributeOpEq, Value: "silver"},
+			{Name: "ram_gb", Op: models.AttributeOpGte, Value: "16"},
+		}, Statuses: []string{models.ProductStatusPublished}}
+		mockRepo.On("GetProducts", mock.Anything, 10, 0, filter).Return(mockProducts, nil).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/products?attr.ram_gb_gte=16&attr.colour=silver", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"attributes":{"colour":"silver","ram_gb":16}`)
+	})
+
+	t.Run("Non-numeric Range Filter", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/products?attr.ram_gb_lt=lots", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "Invalid attribute filter value: attr.ram_gb_lt=lots")
+	})
+
+	t.Run("Status Filter", func(t *testing.T) {
+		mockProducts := []*models.Product{
+			{ID: 3, Name: "Draft", Price: 5.0, Status: models.ProductStatusDraft},
+		}
+		filter := models.ProductFilter{Statuses: []string{models.ProductStatusDraft, models.ProductStatusArchived}}
+		mockRepo.On("GetProducts", mock.Anything, 10, 0, filter).Return(mockProducts, nil).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/products?status=draft,archived", nil)
+		editor.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"status":"draft"`)
+	})
+
+	t.Run("All Statuses", func(t *testing.T) {
+		mockRepo.On("GetProducts", mock.Anything, 10, 0, models.ProductFilter{}).Return([]*models.Product{}, nil).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/products?status=all", nil)
+		editor.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Invalid Status", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/products?status=deleted", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+	t.Run("Sort By Rating", func(t *testing.T) {
+		filter := models.ProductFilter{Statuses: []string{models.ProductStatusPublished}, Sort: models.ProductSortRatingDesc}
+		rating := 4.5
+		mockProducts := []*models.Product{{ID: 1, Name: "Product 1", Price: 10.0, RatingAverage: &rating, ReviewCount: 2}}
+		mockRepo.On("GetProducts", mock.Anything, 10, 0, filter).Return(mockProducts, nil).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/products?sort=-rating", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `[{"id":1,"name":"Product 1","price":10,"rating_average":4.5,"review_count":2}]`, w.Body.String())
+	})
+
+	t.Run("Invalid Sort", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/products?sort=popularity", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "Invalid sort: popularity")
+	})
 }
@@ -1,14 +1,16 @@
 package handlers
 
 import (
-	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mariosker/products_rest_api/internal/auth"
 	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/mariosker/products_rest_api/internal/repository"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -23,8 +25,8 @@ func TestProductHandler_UpdateProduct(t *testing.T) {
 	router.PUT("/products/:id", handler.UpdateProduct)
 
 	t.Run("Product Not Found", func(t *testing.T) {
-		var errRepo = errors.New("product with ID 3 not found")
-		mockRepo.On("UpdateProduct", mock.Anything, 3, mock.Anything).Return(errRepo).Times(1)
+		var errRepo = fmt.Errorf("product with ID 3: %w", repository.ErrNotFound)
+		mockRepo.On("UpdateProduct", mock.Anything, 3, mock.Anything, true).Return(nil, errRepo).Times(1)
 
 		w := httptest.NewRecorder()
 		req, _ := http.NewRequest("PUT", "/products/3", strings.NewReader(`{"name":"Updated Product","price":15.0}`))
@@ -49,9 +51,43 @@ func TestProductHandler_UpdateProduct(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 	})
 
+	t.Run("Duplicate Slug", func(t *testing.T) {
+		slug := "taken"
+		payload := &models.UpdateProductPayload{Name: "Updated Product", Price: 15.0, Slug: &slug}
+		errRepo := fmt.Errorf("product with slug %q already exists: %w", slug, repository.ErrConflict)
+		mockRepo.On("UpdateProduct", mock.Anything, 4, payload, true).Return(nil, errRepo).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/products/4", strings.NewReader(`{"name":"Updated Product","price":15.0,"slug":"taken"}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("Invalid GTIN", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/products/3", strings.NewReader(`{"name":"Updated Product","price":15.0,"gtin":"12345"}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Clear GTIN", func(t *testing.T) {
+		empty := ""
+		payload := &models.UpdateProductPayload{Name: "Updated Product", Price: 15.0, GTIN: &empty}
+		mockRepo.On("UpdateProduct", mock.Anything, 5, payload, true).Return(&models.Product{ID: 5, Name: "Updated Product", Price: 15.0, ListPrice: 15.0}, nil).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/products/5", strings.NewReader(`{"name":"Updated Product","price":15.0,"gtin":""}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
 	t.Run("Success", func(t *testing.T) {
 		payload := &models.UpdateProductPayload{Name: "Updated Product", Price: 15.0}
-		mockRepo.On("UpdateProduct", mock.Anything, 3, payload).Return(nil).Times(1)
+		updated := &models.Product{ID: 3, Name: "Updated Product", Price: 12.0, ListPrice: 15.0}
+		mockRepo.On("UpdateProduct", mock.Anything, 3, payload, true).Return(updated, nil).Times(1)
 
 		w := httptest.NewRecorder()
 		req, _ := http.NewRequest("PUT", "/products/3", strings.NewReader(`{"name":"Updated Product","price":15.0}`))
@@ -59,5 +95,34 @@ func TestProductHandler_UpdateProduct(t *testing.T) {
 
 		assert.Equal(t, http.StatusOK, w.Code)
 		assert.Contains(t, w.Body.String(), `"name":"Updated Product"`)
+		assert.Contains(t, w.Body.String(), `"price":12,"list_price":15`)
+	})
+
+	t.Run("Price Change Without Permission", func(t *testing.T) {
+		editor := gin.Default()
+		editor.Use(func(c *gin.Context) {
+			auth.SetPrincipal(c, &auth.Principal{Subject: "user-7", Scopes: []string{auth.ScopeProductsRead, auth.ScopeProductsWrite}})
+		})
+		editor.PUT("/products/:id", handler.UpdateProduct)
+		changed := &models.UpdateProductPayload{Name: "Updated Product", Price: 12.0}
+		mockRepo.On("UpdateProduct", mock.Anything, 6, changed, false).Return(nil, repository.ErrPriceChangeNotAllowed).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/products/6", strings.NewReader(`{"name":"Updated Product","price":12.0}`))
+		editor.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), "products:price:write permission is required to change the price")
+
+		payload := &models.UpdateProductPayload{Name: "Updated Product", Price: 10.0}
+		mockRepo.On("UpdateProduct", mock.Anything, 6, payload, false).Return(&models.Product{ID: 6, Name: "Updated Product", Price: 10.0, ListPrice: 10.0}, nil).Times(1)
+
+		w = httptest.NewRecorder()
+		req, _ = http.NewRequest("PUT", "/products/6", strings.NewReader(`{"name":"Updated Product","price":10.0}`))
+		editor.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
 	})
+
+	mockRepo.AssertExpectations(t)
 }
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mariosker/products_rest_api/internal/auth"
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestProductHandler_Visibility(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockRepo := new(MockProductRepository)
+	mockRelationRepo := new(MockRelationRepository)
+	handler := NewProductHandler(mockRepo, WithRelations(mockRelationRepo))
+	routerWith := func(scopes ...string) *gin.Engine {
+		router := gin.New()
+		router.Use(func(c *gin.Context) {
+			auth.SetPrincipal(c, &auth.Principal{Subject: "user-7", Scopes: scopes})
+		})
+		router.GET("/products", handler.GetProducts)
+		router.GET("/products/:id", handler.GetProduct)
+		router.GET("/products/by-sku/:sku", handler.GetProductBySKU)
+		return router
+	}
+	reader := routerWith(auth.ScopeProductsRead)
+	editor := routerWith(auth.ScopeProductsRead, auth.ScopeProductsWrite)
+
+	t.Run("Reader Cannot Get Draft", func(t *testing.T) {
+		draft := &models.Product{ID: 1, Name: "Prototype", Status: models.ProductStatusDraft}
+		mockRepo.On("GetProductByID", mock.Anything, 1).Return(draft, nil).Times(2)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/products/1", nil)
+		reader.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		w = httptest.NewRecorder()
+		editor.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Reader Cannot Get Archived By SKU", func(t *testing.T) {
+		archived := &models.Product{ID: 2, Name: "Old Mug", SKU: "MUG-1", Status: models.ProductStatusArchived}
+		mockRepo.On("GetProductBySKU", mock.Anything, "MUG-1").Return(archived, nil).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/products/by-sku/MUG-1", nil)
+		reader.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "Product with sku: MUG-1 not found")
+	})
+
+	t.Run("Reader Only Expands Published Relations", func(t *testing.T) {
+		camera := &models.Product{ID: 3, Name: "Camera", Status: models.ProductStatusPublished}
+		mockRepo.On("GetProductByID", mock.Anything, 3).Return(camera, nil).Times(1)
+		mockRelationRepo.On("GetRelations", mock.Anything, 3, "", true).Return([]*models.ProductRelation{}, nil).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/products/3?expand=relations", nil)
+		reader.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Reader Cannot List Unpublished", func(t *testing.T) {
+		for _, status := range []string{"all", "draft", "published,archived"} {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/products?status="+status, nil)
+			reader.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusForbidden, w.Code, status)
+			assert.Contains(t, w.Body.String(), "The products:write permission is required to list unpublished products")
+		}
+		mockRepo.AssertNotCalled(t, "GetProducts", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Anonymous Client Cannot See Unpublished", func(t *testing.T) {
+		anonymous := gin.New()
+		anonymous.GET("/products", handler.GetProducts)
+		anonymous.GET("/products/:id", handler.GetProduct)
+		archived := &models.Product{ID: 4, Name: "Old Lamp", Status: models.ProductStatusArchived}
+		mockRepo.On("GetProductByID", mock.Anything, 4).Return(archived, nil).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/products/4", nil)
+		anonymous.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		w = httptest.NewRecorder()
+		req, _ = http.NewRequest("GET", "/products?status=all", nil)
+		anonymous.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("Hides Product Data Of Unpublished Products", func(t *testing.T) {
+		routerWithData := func(scopes ...string) *gin.Engine {
+			router := gin.New()
+			router.Use(func(c *gin.Context) {
+				auth.SetPrincipal(c, &auth.Principal{Subject: "user-7", Scopes: scopes})
+			}, HideUnpublishedProducts(mockRepo))
+			router.GET("/products/:id/variants", func(c *gin.Context) { c.Status(http.StatusOK) })
+			router.POST("/products/:id/variants", func(c *gin.Context) { c.Status(http.StatusCreated) })
+			return router
+		}
+		draft := &models.Product{ID: 5, Name: "Prototype", Status: models.ProductStatusDraft}
+		mockRepo.On("GetProductByID", mock.Anything, 5).Return(draft, nil).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/products/5/variants", nil)
+		routerWithData(auth.ScopeProductsRead).ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		w = httptest.NewRecorder()
+		routerWithData(auth.ScopeProductsRead, auth.ScopeProductsWrite).ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = httptest.NewRecorder()
+		req, _ = http.NewRequest("POST", "/products/5/variants", nil)
+		routerWithData(auth.ScopeProductsRead).ServeHTTP(w, req)
+		assert.Equal(t, http.StatusCreated, w.Code, "changes are left to the handlers")
+	})
+
+	t.Run("Editor Lists Every Status", func(t *testing.T) {
+		mockRepo.On("GetProducts", mock.Anything, 10, 0, models.ProductFilter{}).Return([]*models.Product{}, nil).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/products?status=all", nil)
+		editor.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
@@ -2,11 +2,21 @@ package handlers
 
 import (
 	"context"
+	"os"
+	"testing"
+	"time"
 
 	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/mariosker/products_rest_api/internal/utils"
 	"github.com/stretchr/testify/mock"
 )
 
+// TestMain registers the custom binding validators used by the payloads, as the server does on startup.
+func TestMain(m *testing.M) {
+	utils.RegisterValidators()
+	os.Exit(m.Run())
+}
+
 // MockProductRepository is a mock implementation of ProductRepository.
 // It is used to simulate the behavior of the actual ProductRepository in tests,
 // allowing for controlled responses and verification of interactions without
@@ -32,11 +42,38 @@ func (m *MockProductRepository) GetProductByID(ctx context.Context, id int) (*mo
 	return nil, args.Error(1)
 }
 
+// GetProductBySKU mocks retrieving a product by its SKU from the mock repository.
+func (m *MockProductRepository) GetProductBySKU(ctx context.Context, sku string) (*models.Product, error) {
+	args := m.Called(ctx, sku)
+	if product, ok := args.Get(0).(*models.Product); ok {
+		return product, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// GetProductBySlug mocks retrieving a product by its slug from the mock repository.
+func (m *MockProductRepository) GetProductBySlug(ctx context.Context, slug string) (*models.Product, error) {
+	args := m.Called(ctx, slug)
+	if product, ok := args.Get(0).(*models.Product); ok {
+		return product, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// GetProductByGTIN mocks retrieving a product by its barcode from the mock repository.
+func (m *MockProductRepository) GetProductByGTIN(ctx context.Context, gtin string) (*models.Product, error) {
+	args := m.Called(ctx, gtin)
+	if product, ok := args.Get(0).(*models.Product); ok {
+		return product, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 // GetProducts mocks the retrieval of a list of products from the repository.
-// It takes a context, a limit for the number of products to retrieve, and an offset for pagination.
+// It takes a context, a limit for the number of products to retrieve, an offset for pagination and a filter.
 // It returns a slice of Product pointers and an error if any.
-func (m *MockProductRepository) GetProducts(ctx context.Context, limit, offset int) ([]*models.Product, error) {
-	args := m.Called(ctx, limit, offset)
+func (m *MockProductRepository) GetProducts(ctx context.Context, limit, offset int, filter models.ProductFilter) ([]*models.Product, error) {
+	args := m.Called(ctx, limit, offset, filter)
 	if products, ok := args.Get(0).([]*models.Product); ok {
 		return products, args.Error(1)
 	}
@@ -44,10 +81,13 @@ func (m *MockProductRepository) GetProducts(ctx context.Context, limit, offset i
 }
 
 // UpdateProduct mocks the update of a product in the repository.
-// It takes a context and a Product, and returns an error if any.
-func (m *MockProductRepository) UpdateProduct(ctx context.Context, id int, payload *models.UpdateProductPayload) error {
-	args := m.Called(ctx, id, payload)
-	return args.Error(0)
+// It takes a context and a Product, and returns the updated product or an error.
+func (m *MockProductRepository) UpdateProduct(ctx context.Context, id int, payload *models.UpdateProductPayload, allowPriceChange bool) (*models.Product, error) {
+	args := m.Called(ctx, id, payload, allowPriceChange)
+	if product, ok := args.Get(0).(*models.Product); ok {
+		return product, args.Error(1)
+	}
+	return nil, args.Error(1)
 }
 
 // DeleteProduct mocks the deletion of a product in the repository.
@@ -56,3 +96,18 @@ func (m *MockProductRepository) DeleteProduct(ctx context.Context, id int) error
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
+
+// ChangeProductStatus mocks moving a product to another lifecycle status.
+func (m *MockProductRepository) ChangeProductStatus(ctx context.Context, id int, status string, publishAt *time.Time) (*models.Product, error) {
+	args := m.Called(ctx, id, status, publishAt)
+	if product, ok := args.Get(0).(*models.Product); ok {
+		return product, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// PublishScheduledProducts mocks publishing the drafts whose publish time has passed.
+func (m *MockProductRepository) PublishScheduledProducts(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/mariosker/products_rest_api/internal/repository"
+	"github.com/mariosker/products_rest_api/internal/utils"
+)
+
+// RelationHandler handles HTTP requests for managing the relations between products.
+type RelationHandler struct {
+	repo repository.RelationRepository
+}
+
+// NewRelationHandler creates a new RelationHandler with the given repository.
+func NewRelationHandler(repo repository.RelationRepository) *RelationHandler {
+	return &RelationHandler{repo: repo}
+}
+
+// GetRelations godoc
+// @Summary List product relations
+// @Description Retrieve the related products, accessories, replacements and upsells of a product ordered by type and position
+// @Tags relations
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param type query string false "Only return relations of this type" Enums(related, accessory, replacement, upsell)
+// @Success 200 {array} models.ProductRelation
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /products/{id}/relations [get]
+func (h *RelationHandler) GetRelations(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid ID: "+c.Param("id"))
+		return
+	}
+	relationType := c.Query("type")
+	if relationType != "" && !isRelationType(relationType) {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid relation type: "+relationType)
+		return
+	}
+
+	relations, err := h.repo.GetRelations(c.Request.Context(), productID, relationType, !canSeeUnpublished(c))
+	if err != nil {
+		sendRelationError(c, err, "Failed to retrieve relations")
+		return
+	}
+
+	c.JSON(http.StatusOK, relations)
+}
+
+// PutRelations godoc
+// @Summary Replace product relations
+// @Description Replace every relation of a product; relations of the same type are shown in the order they are listed
+// @Tags relations
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param relations body models.ProductRelationsPayload true "Product Relations Payload"
+// @Success 200 {array} models.ProductRelation
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /products/{id}/relations [put]
+func (h *RelationHandler) PutRelations(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid ID: "+c.Param("id"))
+		return
+	}
+
+	var payload models.ProductRelationsPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	relations, err := h.repo.PutRelations(c.Request.Context(), productID, payload.Relations)
+	if err != nil {
+		sendRelationError(c, err, "Failed to store relations")
+		return
+	}
+
+	c.JSON(http.StatusOK, relations)
+}
+
+// isRelationType reports whether relationType is a known product relation type.
+func isRelationType(relationType string) bool {
+	switch relationType {
+	case models.RelationTypeRelated, models.RelationTypeAccessory, models.RelationTypeReplacement, models.RelationTypeUpsell:
+		return true
+	default:
+		return false
+	}
+}
+
+// sendRelationError maps repository errors to HTTP responses.
+func sendRelationError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		utils.SendErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, repository.ErrInvalidRelation):
+		utils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+	default:
+		utils.SendErrorResponse(c, http.StatusInternalServerError, fallback)
+	}
+}
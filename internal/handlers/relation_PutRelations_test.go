@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/mariosker/products_rest_api/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRelationHandler_PutRelations(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.Default()
+	mockRepo := new(MockRelationRepository)
+	handler := NewRelationHandler(mockRepo)
+
+	router.PUT("/products/:id/relations", handler.PutRelations)
+
+	t.Run("Success", func(t *testing.T) {
+		relations := []models.ProductRelationPayload{
+			{Type: models.RelationTypeAccessory, ProductID: 5},
+			{Type: models.RelationTypeAccessory, ProductID: 6},
+		}
+		stored := []*models.ProductRelation{
+			{Type: models.RelationTypeAccessory, ProductID: 5, Name: "Lens", Position: 0},
+			{Type: models.RelationTypeAccessory, ProductID: 6, Name: "Tripod", Position: 1},
+		}
+		mockRepo.On("PutRelations", mock.Anything, 1, relations).Return(stored, nil).Times(1)
+
+		w := httptest.NewRecorder()
+		body := `{"relations":[{"type":"accessory","product_id":5},{"type":"accessory","product_id":6}]}`
+		req, _ := http.NewRequest("PUT", "/products/1/relations", strings.NewReader(body))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `[{"type":"accessory","product_id":5,"name":"Lens","position":0},{"type":"accessory","product_id":6,"name":"Tripod","position":1}]`, w.Body.String())
+	})
+
+	t.Run("Unknown Type", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/products/1/relations", strings.NewReader(`{"relations":[{"type":"similar","product_id":5}]}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Related Product Not Found", func(t *testing.T) {
+		err := fmt.Errorf("related product does not exist: %w", repository.ErrInvalidRelation)
+		mockRepo.On("PutRelations", mock.Anything, 1, mock.Anything).Return(nil, err).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/products/1/relations", strings.NewReader(`{"relations":[{"type":"upsell","product_id":99}]}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "related product does not exist")
+	})
+
+	t.Run("Product Not Found", func(t *testing.T) {
+		mockRepo.On("PutRelations", mock.Anything, 9, mock.Anything).Return(nil, fmt.Errorf("product with ID 9: %w", repository.ErrNotFound)).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/products/9/relations", strings.NewReader(`{"relations":[]}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
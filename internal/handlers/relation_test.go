@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRelationRepository is a mock implementation of RelationRepository.
+// It is used to simulate product relations in handler tests without a real database.
+type MockRelationRepository struct {
+	mock.Mock
+}
+
+// PutRelations mocks replacing the relations of a product.
+func (m *MockRelationRepository) PutRelations(ctx context.Context, productID int, relations []models.ProductRelationPayload) ([]*models.ProductRelation, error) {
+	args := m.Called(ctx, productID, relations)
+	if stored, ok := args.Get(0).([]*models.ProductRelation); ok {
+		return stored, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// GetRelations mocks retrieving the relations of a product.
+func (m *MockRelationRepository) GetRelations(ctx context.Context, productID int, relationType string, publishedOnly bool) ([]*models.ProductRelation, error) {
+	args := m.Called(ctx, productID, relationType, publishedOnly)
+	if relations, ok := args.Get(0).([]*models.ProductRelation); ok {
+		return relations, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
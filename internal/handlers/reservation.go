@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/mariosker/products_rest_api/internal/repository"
+	"github.com/mariosker/products_rest_api/internal/utils"
+)
+
+// ReservationHandler handles HTTP requests for holding product stock during checkout.
+type ReservationHandler struct {
+	repo       repository.ReservationRepository
+	defaultTTL time.Duration
+}
+
+// NewReservationHandler creates a new ReservationHandler with the given repository.
+// defaultTTL is used for reservations that do not specify their own lifetime.
+func NewReservationHandler(repo repository.ReservationRepository, defaultTTL time.Duration) *ReservationHandler {
+	return &ReservationHandler{repo: repo, defaultTTL: defaultTTL}
+}
+
+// CreateReservation godoc
+// @Summary Reserve product stock
+// @Description Atomically hold quantities of one or more products until the reservation expires
+// @Tags reservations
+// @Accept json
+// @Produce json
+// @Param reservation body models.CreateReservationPayload true "Reservation Payload"
+// @Success 201 {object} models.Reservation
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /reservations [post]
+func (h *ReservationHandler) CreateReservation(c *gin.Context) {
+	var payload models.CreateReservationPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ttl := h.defaultTTL
+	if payload.TTLSeconds > 0 {
+		ttl = time.Duration(payload.TTLSeconds) * time.Second
+	}
+
+	reservation, err := h.repo.CreateReservation(c.Request.Context(), payload.Items, ttl)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			utils.SendErrorResponse(c, http.StatusNotFound, err.Error())
+		case errors.Is(err, repository.ErrInsufficientStock):
+			utils.SendErrorResponse(c, http.StatusConflict, err.Error())
+		default:
+			utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to create reservation")
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, reservation)
+}
+
+// GetReservation godoc
+// @Summary Get a reservation by ID
+// @Description Retrieve a reservation and the quantities it holds
+// @Tags reservations
+// @Accept json
+// @Produce json
+// @Param id path int true "Reservation ID"
+// @Success 200 {object} models.Reservation
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /reservations/{id} [get]
+func (h *ReservationHandler) GetReservation(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid ID: "+c.Param("id"))
+		return
+	}
+
+	reservation, err := h.repo.GetReservationByID(c.Request.Context(), id)
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusNotFound, "Reservation with id: "+strconv.Itoa(id)+" not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, reservation)
+}
+
+// CommitReservation godoc
+// @Summary Commit a reservation
+// @Description Finalize an active reservation, deducting its quantities from product stock. Fails with 409 if a product's stock
+// @Description was lowered below the reserved quantity since.
+// @Tags reservations
+// @Accept json
+// @Produce json
+// @Param id path int true "Reservation ID"
+// @Success 200 {object} models.Reservation
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /reservations/{id}/commit [post]
+func (h *ReservationHandler) CommitReservation(c *gin.Context) {
+	h.finishReservation(c, h.repo.CommitReservation, "commit")
+}
+
+// ReleaseReservation godoc
+// @Summary Release a reservation
+// @Description Drop an active reservation, returning its quantities to the available stock
+// @Tags reservations
+// @Accept json
+// @Produce json
+// @Param id path int true "Reservation ID"
+// @Success 200 {object} models.Reservation
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /reservations/{id}/release [post]
+func (h *ReservationHandler) ReleaseReservation(c *gin.Context) {
+	h.finishReservation(c, h.repo.ReleaseReservation, "release")
+}
+
+// finishReservation runs a commit or release action and maps repository errors to HTTP responses.
+func (h *ReservationHandler) finishReservation(c *gin.Context, action func(ctx context.Context, id int) (*models.Reservation, error), verb string) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid ID: "+c.Param("id"))
+		return
+	}
+
+	reservation, err := action(c.Request.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			utils.SendErrorResponse(c, http.StatusNotFound, "Reservation with id: "+strconv.Itoa(id)+" not found")
+		case errors.Is(err, repository.ErrReservationNotActive):
+			utils.SendErrorResponse(c, http.StatusConflict, "Reservation with id: "+strconv.Itoa(id)+" is not active")
+		case errors.Is(err, repository.ErrInsufficientStock):
+			utils.SendErrorResponse(c, http.StatusConflict, err.Error())
+		default:
+			utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to "+verb+" reservation with id: "+strconv.Itoa(id))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, reservation)
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/mariosker/products_rest_api/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestReservationHandler_CommitReservation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.Default()
+	mockRepo := new(MockReservationRepository)
+	handler := NewReservationHandler(mockRepo, 10*time.Minute)
+
+	router.POST("/reservations/:id/commit", handler.CommitReservation)
+
+	t.Run("Success", func(t *testing.T) {
+		reservation := &models.Reservation{ID: 3, Status: models.ReservationStatusCommitted}
+		mockRepo.On("CommitReservation", mock.Anything, 3).Return(reservation, nil).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/reservations/3/commit", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"status":"committed"`)
+	})
+
+	t.Run("Invalid ID", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/reservations/invalid/commit", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Reservation Not Found", func(t *testing.T) {
+		mockRepo.On("CommitReservation", mock.Anything, 4).Return(nil, repository.ErrNotFound).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/reservations/4/commit", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Reservation Not Active", func(t *testing.T) {
+		mockRepo.On("CommitReservation", mock.Anything, 5).Return(nil, repository.ErrReservationNotActive).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/reservations/5/commit", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "Reservation with id: 5 is not active")
+	})
+
+	t.Run("Stock Lowered Since Reserved", func(t *testing.T) {
+		errRepo := fmt.Errorf("reservation with ID 7 holds more than the stock of its products: %w", repository.ErrInsufficientStock)
+		mockRepo.On("CommitReservation", mock.Anything, 7).Return(nil, errRepo).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/reservations/7/commit", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "holds more than the stock of its products")
+	})
+
+	t.Run("Commit Error", func(t *testing.T) {
+		mockRepo.On("CommitReservation", mock.Anything, 6).Return(nil, errors.New("database error")).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/reservations/6/commit", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), "Failed to commit reservation with id: 6")
+	})
+}
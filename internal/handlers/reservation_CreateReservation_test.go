@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/mariosker/products_rest_api/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestReservationHandler_CreateReservation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.Default()
+	mockRepo := new(MockReservationRepository)
+	handler := NewReservationHandler(mockRepo, 10*time.Minute)
+
+	router.POST("/reservations", handler.CreateReservation)
+
+	items := []models.ReservationItem{{ProductID: 1, Quantity: 2}, {ProductID: 2, Quantity: 1}}
+
+	t.Run("Success With Default TTL", func(t *testing.T) {
+		reservation := &models.Reservation{ID: 7, Status: models.ReservationStatusActive, Items: items}
+		mockRepo.On("CreateReservation", mock.Anything, items, 10*time.Minute).Return(reservation, nil).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/reservations", strings.NewReader(`{"items":[{"product_id":1,"quantity":2},{"product_id":2,"quantity":1}]}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), `"id":7`)
+		assert.Contains(t, w.Body.String(), `"status":"active"`)
+	})
+
+	t.Run("Success With Custom TTL", func(t *testing.T) {
+		reservation := &models.Reservation{ID: 8, Status: models.ReservationStatusActive, Items: items[:1]}
+		mockRepo.On("CreateReservation", mock.Anything, items[:1], 30*time.Second).Return(reservation, nil).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/reservations", strings.NewReader(`{"items":[{"product_id":1,"quantity":2}],"ttl_seconds":30}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+
+	t.Run("No Items", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/reservations", strings.NewReader(`{"items":[]}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Zero Quantity", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/reservations", strings.NewReader(`{"items":[{"product_id":1,"quantity":0}]}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Product Not Found", func(t *testing.T) {
+		errRepo := fmt.Errorf("product with ID 9: %w", repository.ErrNotFound)
+		mockRepo.On("CreateReservation", mock.Anything, []models.ReservationItem{{ProductID: 9, Quantity: 1}}, 10*time.Minute).Return(nil, errRepo).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/reservations", strings.NewReader(`{"items":[{"product_id":9,"quantity":1}]}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Insufficient Stock", func(t *testing.T) {
+		errRepo := fmt.Errorf("product with ID 3 has 1 available: %w", repository.ErrInsufficientStock)
+		mockRepo.On("CreateReservation", mock.Anything, []models.ReservationItem{{ProductID: 3, Quantity: 5}}, 10*time.Minute).Return(nil, errRepo).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/reservations", strings.NewReader(`{"items":[{"product_id":3,"quantity":5}]}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "insufficient stock")
+	})
+
+	t.Run("Creation Error", func(t *testing.T) {
+		mockRepo.On("CreateReservation", mock.Anything, []models.ReservationItem{{ProductID: 4, Quantity: 1}}, 10*time.Minute).Return(nil, errors.New("database error")).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/reservations", strings.NewReader(`{"items":[{"product_id":4,"quantity":1}]}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), "Failed to create reservation")
+	})
+}
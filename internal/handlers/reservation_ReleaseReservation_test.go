@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/mariosker/products_rest_api/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestReservationHandler_ReleaseReservation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.Default()
+	mockRepo := new(MockReservationRepository)
+	handler := NewReservationHandler(mockRepo, 10*time.Minute)
+
+	router.POST("/reservations/:id/release", handler.ReleaseReservation)
+
+	t.Run("Success", func(t *testing.T) {
+		reservation := &models.Reservation{ID: 3, Status: models.ReservationStatusReleased}
+		mockRepo.On("ReleaseReservation", mock.Anything, 3).Return(reservation, nil).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/reservations/3/release", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"status":"released"`)
+	})
+
+	t.Run("Already Released", func(t *testing.T) {
+		mockRepo.On("ReleaseReservation", mock.Anything, 4).Return(nil, repository.ErrReservationNotActive).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/reservations/4/release", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockReservationRepository is a mock implementation of ReservationRepository.
+// It is used to simulate stock holds in handler tests without a real database.
+type MockReservationRepository struct {
+	mock.Mock
+}
+
+// CreateReservation mocks reserving the given items for the given TTL.
+func (m *MockReservationRepository) CreateReservation(ctx context.Context, items []models.ReservationItem, ttl time.Duration) (*models.Reservation, error) {
+	args := m.Called(ctx, items, ttl)
+	if reservation, ok := args.Get(0).(*models.Reservation); ok {
+		return reservation, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// GetReservationByID mocks retrieving a reservation by its ID.
+func (m *MockReservationRepository) GetReservationByID(ctx context.Context, id int) (*models.Reservation, error) {
+	args := m.Called(ctx, id)
+	if reservation, ok := args.Get(0).(*models.Reservation); ok {
+		return reservation, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// CommitReservation mocks committing a reservation by its ID.
+func (m *MockReservationRepository) CommitReservation(ctx context.Context, id int) (*models.Reservation, error) {
+	args := m.Called(ctx, id)
+	if reservation, ok := args.Get(0).(*models.Reservation); ok {
+		return reservation, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// ReleaseReservation mocks releasing a reservation by its ID.
+func (m *MockReservationRepository) ReleaseReservation(ctx context.Context, id int) (*models.Reservation, error) {
+	args := m.Called(ctx, id)
+	if reservation, ok := args.Get(0).(*models.Reservation); ok {
+		return reservation, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// ReleaseExpiredReservations mocks releasing every expired reservation.
+func (m *MockReservationRepository) ReleaseExpiredReservations(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
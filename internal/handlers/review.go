@@ -0,0 +1,256 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mariosker/products_rest_api/internal/auth"
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/mariosker/products_rest_api/internal/repository"
+	"github.com/mariosker/products_rest_api/internal/utils"
+)
+
+// ReviewHandler handles HTTP requests for managing product reviews.
+type ReviewHandler struct {
+	repo        repository.ReviewRepository
+	autoApprove bool
+}
+
+// NewReviewHandler creates a new ReviewHandler with the given repository. New reviews are
+// approved straight away when autoApprove is set and wait for moderation otherwise.
+func NewReviewHandler(repo repository.ReviewRepository, autoApprove bool) *ReviewHandler {
+	return &ReviewHandler{repo: repo, autoApprove: autoApprove}
+}
+
+// CreateReview godoc
+// @Summary Review a product
+// @Description Rate a product from 1 to 5. The review is counted towards the product's rating once it is approved.
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param review body models.ReviewPayload true "Review Payload"
+// @Success 201 {object} models.Review
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /products/{id}/reviews [post]
+func (h *ReviewHandler) CreateReview(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid ID: "+c.Param("id"))
+		return
+	}
+
+	var payload models.ReviewPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	status := models.ReviewStatusPending
+	if h.autoApprove {
+		status = models.ReviewStatusApproved
+	}
+	review, err := h.repo.CreateReview(c.Request.Context(), productID, &payload, status)
+	if err != nil {
+		sendReviewError(c, err, "Failed to create review")
+		return
+	}
+
+	c.JSON(http.StatusCreated, review)
+}
+
+// GetReviews godoc
+// @Summary List product reviews
+// @Description Retrieve the reviews of a product, newest first, with pagination. Only clients that may moderate or change products can list reviews that are not approved.
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param status query string false "Comma-separated moderation statuses to list, or all; ignored for other clients" default(approved)
+// @Param limit query int false "Limit"
+// @Param offset query int false "Offset"
+// @Success 200 {array} models.Review
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /products/{id}/reviews [get]
+func (h *ReviewHandler) GetReviews(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid ID: "+c.Param("id"))
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit <= 0 {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid limit")
+		return
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid offset")
+		return
+	}
+	status := models.ReviewStatusApproved
+	if canSeeUnapproved(c) {
+		status = c.DefaultQuery("status", models.ReviewStatusApproved)
+	}
+	statuses, err := parseReviewStatusFilter(status)
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	reviews, err := h.repo.GetReviewsByProductID(c.Request.Context(), productID, statuses, limit, offset)
+	if err != nil {
+		sendReviewError(c, err, "Failed to retrieve reviews")
+		return
+	}
+
+	c.JSON(http.StatusOK, reviews)
+}
+
+// GetReview godoc
+// @Summary Get a product review
+// @Description Retrieve a single review of a product. Reviews that are not approved are only found by clients that may moderate or change products.
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param reviewId path int true "Review ID"
+// @Success 200 {object} models.Review
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /products/{id}/reviews/{reviewId} [get]
+func (h *ReviewHandler) GetReview(c *gin.Context) {
+	productID, reviewID, ok := parseReviewPath(c)
+	if !ok {
+		return
+	}
+
+	review, err := h.repo.GetReviewByID(c.Request.Context(), productID, reviewID)
+	if err == nil && review.Status != models.ReviewStatusApproved && !canSeeUnapproved(c) {
+		err = fmt.Errorf("review with ID %d: %w", reviewID, repository.ErrNotFound)
+	}
+	if err != nil {
+		sendReviewError(c, err, "Failed to retrieve review")
+		return
+	}
+
+	c.JSON(http.StatusOK, review)
+}
+
+// ModerateReview godoc
+// @Summary Moderate a product review
+// @Description Approve or reject a review; only approved reviews count towards the product's rating
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param reviewId path int true "Review ID"
+// @Param status body models.ReviewStatusPayload true "Review Status Payload"
+// @Success 200 {object} models.Review
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /products/{id}/reviews/{reviewId}/status [put]
+func (h *ReviewHandler) ModerateReview(c *gin.Context) {
+	productID, reviewID, ok := parseReviewPath(c)
+	if !ok {
+		return
+	}
+
+	var payload models.ReviewStatusPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	review, err := h.repo.ModerateReview(c.Request.Context(), productID, reviewID, payload.Status)
+	if err != nil {
+		sendReviewError(c, err, "Failed to moderate review")
+		return
+	}
+
+	c.JSON(http.StatusOK, review)
+}
+
+// DeleteReview godoc
+// @Summary Delete a product review
+// @Description Delete a review of a product
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param reviewId path int true "Review ID"
+// @Success 204 {} {}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /products/{id}/reviews/{reviewId} [delete]
+func (h *ReviewHandler) DeleteReview(c *gin.Context) {
+	productID, reviewID, ok := parseReviewPath(c)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.DeleteReview(c.Request.Context(), productID, reviewID); err != nil {
+		sendReviewError(c, err, "Failed to delete review")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// parseReviewStatusFilter parses a comma-separated list of review statuses; "all" means any status.
+func parseReviewStatusFilter(value string) ([]string, error) {
+	if value == "all" {
+		return nil, nil
+	}
+	var statuses []string
+	for _, status := range strings.Split(value, ",") {
+		switch status = strings.TrimSpace(status); status {
+		case models.ReviewStatusPending, models.ReviewStatusApproved, models.ReviewStatusRejected:
+			statuses = append(statuses, status)
+		default:
+			return nil, fmt.Errorf("Invalid status: %s", status)
+		}
+	}
+	return statuses, nil
+}
+
+// canSeeUnapproved reports whether the client may read reviews that are pending or rejected.
+func canSeeUnapproved(c *gin.Context) bool {
+	return auth.Allowed(c, auth.ScopeReviewsModerate) || auth.Allowed(c, auth.ScopeProductsWrite)
+}
+
+// parseReviewPath parses the product and review IDs from the path,
+// sending a 400 response and returning false if either is invalid.
+func parseReviewPath(c *gin.Context) (int, int, bool) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid ID: "+c.Param("id"))
+		return 0, 0, false
+	}
+	reviewID, err := strconv.Atoi(c.Param("reviewId"))
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid review ID: "+c.Param("reviewId"))
+		return 0, 0, false
+	}
+	return productID, reviewID, true
+}
+
+// sendReviewError maps repository errors to HTTP responses.
+func sendReviewError(c *gin.Context, err error, fallback string) {
+	if errors.Is(err, repository.ErrNotFound) {
+		utils.SendErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+	utils.SendErrorResponse(c, http.StatusInternalServerError, fallback)
+}
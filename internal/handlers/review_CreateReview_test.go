@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/mariosker/products_rest_api/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestReviewHandler_CreateReview(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.Default()
+	mockRepo := new(MockReviewRepository)
+	handler := NewReviewHandler(mockRepo, false)
+
+	router.POST("/products/:id/reviews", handler.CreateReview)
+
+	createdAt := time.Date(2024, 12, 24, 9, 0, 0, 0, time.UTC)
+
+	t.Run("Held For Moderation", func(t *testing.T) {
+		payload := &models.ReviewPayload{Rating: 4, Text: "Sturdy.", Author: "Sam"}
+		review := &models.Review{ID: 1, ProductID: 3, Rating: 4, Text: "Sturdy.", Author: "Sam", Status: models.ReviewStatusPending, CreatedAt: createdAt}
+		mockRepo.On("CreateReview", mock.Anything, 3, payload, models.ReviewStatusPending).Return(review, nil).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/products/3/reviews", strings.NewReader(`{"rating":4,"text":"Sturdy.","author":"Sam"}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.JSONEq(t, `{"id":1,"product_id":3,"rating":4,"text":"Sturdy.","author":"Sam","status":"pending","created_at":"2024-12-24T09:00:00Z"}`, w.Body.String())
+	})
+
+	t.Run("Auto Approve", func(t *testing.T) {
+		autoRouter := gin.Default()
+		autoRouter.POST("/products/:id/reviews", NewReviewHandler(mockRepo, true).CreateReview)
+		review := &models.Review{ID: 2, ProductID: 3, Rating: 5, Author: "Kim", Status: models.ReviewStatusApproved, CreatedAt: createdAt}
+		mockRepo.On("CreateReview", mock.Anything, 3, mock.Anything, models.ReviewStatusApproved).Return(review, nil).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/products/3/reviews", strings.NewReader(`{"rating":5,"author":"Kim"}`))
+		autoRouter.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), `"status":"approved"`)
+	})
+
+	t.Run("Rating Out Of Range", func(t *testing.T) {
+		for _, body := range []string{`{"rating":0,"author":"Sam"}`, `{"rating":6,"author":"Sam"}`, `{"rating":3}`} {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", "/products/3/reviews", strings.NewReader(body))
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, body)
+		}
+	})
+
+	t.Run("Product Not Found", func(t *testing.T) {
+		mockRepo.On("CreateReview", mock.Anything, 9, mock.Anything, models.ReviewStatusPending).
+			Return(nil, fmt.Errorf("product with ID 9: %w", repository.ErrNotFound)).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/products/9/reviews", strings.NewReader(`{"rating":3,"author":"Sam"}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
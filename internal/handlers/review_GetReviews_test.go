@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mariosker/products_rest_api/internal/auth"
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestReviewHandler_GetReviews(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockRepo := new(MockReviewRepository)
+	handler := NewReviewHandler(mockRepo, false)
+	routerWith := func(scopes ...string) *gin.Engine {
+		router := gin.New()
+		router.Use(func(c *gin.Context) {
+			auth.SetPrincipal(c, &auth.Principal{Subject: "user-7", Scopes: scopes})
+		})
+		router.GET("/products/:id/reviews", handler.GetReviews)
+		router.GET("/products/:id/reviews/:reviewId", handler.GetReview)
+		return router
+	}
+	reader := routerWith(auth.ScopeProductsRead)
+	moderator := routerWith(auth.ScopeProductsRead, auth.ScopeReviewsModerate)
+
+	t.Run("Reader Only Sees Approved", func(t *testing.T) {
+		mockRepo.On("GetReviewsByProductID", mock.Anything, 3, []string{models.ReviewStatusApproved}, 10, 0).Return([]*models.Review{}, nil).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/products/3/reviews?status=pending", nil)
+		reader.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Moderator Filters By Status", func(t *testing.T) {
+		mockRepo.On("GetReviewsByProductID", mock.Anything, 3, []string{models.ReviewStatusPending}, 10, 0).Return([]*models.Review{}, nil).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/products/3/reviews?status=pending", nil)
+		moderator.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Reader Cannot Get Pending Review", func(t *testing.T) {
+		mockRepo.On("GetReviewByID", mock.Anything, 3, 5).Return(&models.Review{ID: 5, ProductID: 3, Status: models.ReviewStatusPending}, nil).Times(2)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/products/3/reviews/5", nil)
+		reader.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		w = httptest.NewRecorder()
+		req, _ = http.NewRequest("GET", "/products/3/reviews/5", nil)
+		moderator.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockReviewRepository is a mock implementation of ReviewRepository.
+// It is used to simulate product reviews in handler tests without a real database.
+type MockReviewRepository struct {
+	mock.Mock
+}
+
+// CreateReview mocks adding a review to a product.
+func (m *MockReviewRepository) CreateReview(ctx context.Context, productID int, payload *models.ReviewPayload, status string) (*models.Review, error) {
+	args := m.Called(ctx, productID, payload, status)
+	if review, ok := args.Get(0).(*models.Review); ok {
+		return review, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// GetReviewByID mocks retrieving a single review.
+func (m *MockReviewRepository) GetReviewByID(ctx context.Context, productID, reviewID int) (*models.Review, error) {
+	args := m.Called(ctx, productID, reviewID)
+	if review, ok := args.Get(0).(*models.Review); ok {
+		return review, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// GetReviewsByProductID mocks retrieving the reviews of a product.
+func (m *MockReviewRepository) GetReviewsByProductID(ctx context.Context, productID int, statuses []string, limit, offset int) ([]*models.Review, error) {
+	args := m.Called(ctx, productID, statuses, limit, offset)
+	if reviews, ok := args.Get(0).([]*models.Review); ok {
+		return reviews, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// ModerateReview mocks changing the moderation status of a review.
+func (m *MockReviewRepository) ModerateReview(ctx context.Context, productID, reviewID int, status string) (*models.Review, error) {
+	args := m.Called(ctx, productID, reviewID, status)
+	if review, ok := args.Get(0).(*models.Review); ok {
+		return review, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// DeleteReview mocks deleting a review.
+func (m *MockReviewRepository) DeleteReview(ctx context.Context, productID, reviewID int) error {
+	args := m.Called(ctx, productID, reviewID)
+	return args.Error(0)
+}
@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/mariosker/products_rest_api/internal/repository"
+	"github.com/mariosker/products_rest_api/internal/tax"
+	"github.com/mariosker/products_rest_api/internal/utils"
+)
+
+// TaxHandler handles HTTP requests for managing tax rates.
+type TaxHandler struct {
+	repo repository.TaxRepository
+}
+
+// NewTaxHandler creates a new TaxHandler with the given repository.
+func NewTaxHandler(repo repository.TaxRepository) *TaxHandler {
+	return &TaxHandler{repo: repo}
+}
+
+// GetTaxRates godoc
+// @Summary List tax rates
+// @Description Retrieve every tax rate ordered by country, region and tax class
+// @Tags taxes
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.TaxRate
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /tax-rates [get]
+func (h *TaxHandler) GetTaxRates(c *gin.Context) {
+	rates, err := h.repo.GetTaxRates(c.Request.Context())
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve tax rates")
+		return
+	}
+
+	c.JSON(http.StatusOK, rates)
+}
+
+// PutTaxRate godoc
+// @Summary Create or replace a tax rate
+// @Description Set the rate of a tax class in a country, such as DE, or in one of its regions, such as US-CA
+// @Tags taxes
+// @Accept json
+// @Produce json
+// @Param region path string true "Country or region code"
+// @Param taxClass path string true "Tax class"
+// @Param rate body models.TaxRatePayload true "Tax Rate Payload"
+// @Success 200 {object} models.TaxRate
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /tax-rates/{region}/{taxClass} [put]
+func (h *TaxHandler) PutTaxRate(c *gin.Context) {
+	country, region, err := tax.ParseRegion(c.Param("region"))
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid region: "+c.Param("region"))
+		return
+	}
+	if len(c.Param("taxClass")) > 32 {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid tax class: "+c.Param("taxClass"))
+		return
+	}
+
+	var payload models.TaxRatePayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rate := models.TaxRate{
+		Country:          country,
+		Region:           region,
+		TaxClass:         c.Param("taxClass"),
+		Rate:             payload.Rate,
+		PricesIncludeTax: payload.PricesIncludeTax,
+	}
+	if err := h.repo.PutTaxRate(c.Request.Context(), &rate); err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to store tax rate")
+		return
+	}
+
+	c.JSON(http.StatusOK, rate)
+}
+
+// DeleteTaxRate godoc
+// @Summary Delete a tax rate
+// @Description Delete the rate of a tax class in a country or region
+// @Tags taxes
+// @Accept json
+// @Produce json
+// @Param region path string true "Country or region code"
+// @Param taxClass path string true "Tax class"
+// @Success 204 {} {}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /tax-rates/{region}/{taxClass} [delete]
+func (h *TaxHandler) DeleteTaxRate(c *gin.Context) {
+	country, region, err := tax.ParseRegion(c.Param("region"))
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid region: "+c.Param("region"))
+		return
+	}
+
+	err = h.repo.DeleteTaxRate(c.Request.Context(), country, region, c.Param("taxClass"))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			utils.SendErrorResponse(c, http.StatusNotFound, err.Error())
+		} else {
+			utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to delete tax rate")
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// taxRegion is the region product prices are taxed in, parsed from ?region=.
+type taxRegion struct {
+	code        string
+	country     string
+	subdivision string
+}
+
+// requestRegion returns the region to tax products in, or nil when taxes are disabled or
+// ?region= is not set, sending a 400 response and returning false if ?region= is invalid.
+func (h *ProductHandler) requestRegion(c *gin.Context) (*taxRegion, bool) {
+	code := c.Query("region")
+	if h.taxRepo == nil || code == "" {
+		return nil, true
+	}
+	country, subdivision, err := tax.ParseRegion(code)
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid region: "+code)
+		return nil, false
+	}
+	return &taxRegion{code: tax.RegionCode(country, subdivision), country: country, subdivision: subdivision}, true
+}
+
+// applyTax sets the tax on the price of products in region. A tax class without a rate in a
+// region that has other rates is zero-rated. It sends a 400 response and returns false if the
+// region has no tax rates at all, and a 500 response if they cannot be retrieved.
+func (h *ProductHandler) applyTax(c *gin.Context, region *taxRegion, products ...*models.Product) bool {
+	if region == nil {
+		return true
+	}
+
+	rates, err := h.taxRepo.GetTaxRatesForRegion(c.Request.Context(), region.country, region.subdivision)
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve tax rates")
+		return false
+	}
+	if len(rates) == 0 {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "No tax rates for region: "+region.code)
+		return false
+	}
+
+	byClass := make(map[string]*models.TaxRate, len(rates))
+	for _, rate := range rates {
+		byClass[rate.TaxClass] = rate
+	}
+	for _, product := range products {
+		rate, ok := byClass[product.TaxClass]
+		if !ok {
+			// Net and gross are equal at a zero rate, so whether the region shows prices
+			// including tax makes no difference.
+			rate = &models.TaxRate{TaxClass: product.TaxClass}
+		}
+
+		amounts := tax.FromNet(product.Price, rate.Rate, h.taxRounding)
+		product.Tax = &models.ProductTax{
+			Region:       region.code,
+			TaxClass:     product.TaxClass,
+			Rate:         rate.Rate,
+			Net:          amounts.Net,
+			Tax:          amounts.Tax,
+			Gross:        amounts.Gross,
+			DisplayPrice: amounts.Net,
+		}
+		if rate.PricesIncludeTax {
+			product.Tax.DisplayPrice = amounts.Gross
+		}
+	}
+	return true
+}
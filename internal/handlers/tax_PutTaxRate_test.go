@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTaxHandler_PutTaxRate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.Default()
+	mockRepo := new(MockTaxRepository)
+	handler := NewTaxHandler(mockRepo)
+
+	router.PUT("/tax-rates/:region/:taxClass", handler.PutTaxRate)
+
+	t.Run("Country", func(t *testing.T) {
+		rate := &models.TaxRate{Country: "DE", TaxClass: "standard", Rate: 19, PricesIncludeTax: true}
+		mockRepo.On("PutTaxRate", mock.Anything, rate).Return(nil).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/tax-rates/de/standard", strings.NewReader(`{"rate":19,"prices_include_tax":true}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"country":"DE","tax_class":"standard","rate":19,"prices_include_tax":true}`, w.Body.String())
+	})
+
+	t.Run("Region", func(t *testing.T) {
+		rate := &models.TaxRate{Country: "US", Region: "CA", TaxClass: "standard", Rate: 7.25}
+		mockRepo.On("PutTaxRate", mock.Anything, rate).Return(nil).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/tax-rates/US-CA/standard", strings.NewReader(`{"rate":7.25}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"region":"CA"`)
+	})
+
+	t.Run("Invalid Region", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/tax-rates/Germany/standard", strings.NewReader(`{"rate":19}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "Invalid region: Germany")
+	})
+
+	t.Run("Rate Above 100", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/tax-rates/DE/standard", strings.NewReader(`{"rate":119}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Store Error", func(t *testing.T) {
+		mockRepo.On("PutTaxRate", mock.Anything, mock.Anything).Return(errors.New("database error")).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/tax-rates/FR/reduced", strings.NewReader(`{"rate":5.5}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
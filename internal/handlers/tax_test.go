@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockTaxRepository is a mock implementation of TaxRepository.
+// It is used to simulate tax rates in handler tests without a real database.
+type MockTaxRepository struct {
+	mock.Mock
+}
+
+// PutTaxRate mocks creating or replacing a tax rate.
+func (m *MockTaxRepository) PutTaxRate(ctx context.Context, rate *models.TaxRate) error {
+	args := m.Called(ctx, rate)
+	return args.Error(0)
+}
+
+// GetTaxRates mocks retrieving every tax rate.
+func (m *MockTaxRepository) GetTaxRates(ctx context.Context) ([]*models.TaxRate, error) {
+	args := m.Called(ctx)
+	if rates, ok := args.Get(0).([]*models.TaxRate); ok {
+		return rates, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// GetTaxRatesForRegion mocks retrieving the tax rates that apply in a region.
+func (m *MockTaxRepository) GetTaxRatesForRegion(ctx context.Context, country, region string) ([]*models.TaxRate, error) {
+	args := m.Called(ctx, country, region)
+	if rates, ok := args.Get(0).([]*models.TaxRate); ok {
+		return rates, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// DeleteTaxRate mocks deleting a tax rate.
+func (m *MockTaxRepository) DeleteTaxRate(ctx context.Context, country, region, taxClass string) error {
+	args := m.Called(ctx, country, region, taxClass)
+	return args.Error(0)
+}
@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/mariosker/products_rest_api/internal/repository"
+	"github.com/mariosker/products_rest_api/internal/utils"
+	"golang.org/x/text/language"
+)
+
+// TranslationHandler handles HTTP requests for managing the translations of a product.
+type TranslationHandler struct {
+	repo          repository.TranslationRepository
+	defaultLocale string
+}
+
+// NewTranslationHandler creates a new TranslationHandler with the given repository.
+// Products are named and described in defaultLocale, so it cannot be translated into.
+func NewTranslationHandler(repo repository.TranslationRepository, defaultLocale string) *TranslationHandler {
+	return &TranslationHandler{repo: repo, defaultLocale: defaultLocale}
+}
+
+// PutTranslation godoc
+// @Summary Create or replace a product translation
+// @Description Set the name and description of a product in a locale other than the default locale
+// @Tags translations
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param locale path string true "BCP 47 locale, e.g. de or pt-BR"
+// @Param translation body models.ProductTranslationPayload true "Translation Payload"
+// @Success 200 {object} models.ProductTranslation
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /products/{id}/translations/{locale} [put]
+func (h *TranslationHandler) PutTranslation(c *gin.Context) {
+	productID, locale, ok := h.parseTranslationPath(c)
+	if !ok {
+		return
+	}
+	if locale == h.defaultLocale {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Products are named in the default locale "+locale+"; update the product instead")
+		return
+	}
+
+	var payload models.ProductTranslationPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	translation := models.ProductTranslation{ProductID: productID, Locale: locale, Name: payload.Name, Description: payload.Description}
+	if err := h.repo.PutTranslation(c.Request.Context(), &translation); err != nil {
+		sendTranslationError(c, err, "Failed to store translation")
+		return
+	}
+
+	c.JSON(http.StatusOK, translation)
+}
+
+// GetTranslations godoc
+// @Summary List product translations
+// @Description Retrieve every translation of a product
+// @Tags translations
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {array} models.ProductTranslation
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /products/{id}/translations [get]
+func (h *TranslationHandler) GetTranslations(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid ID: "+c.Param("id"))
+		return
+	}
+
+	translations, err := h.repo.GetTranslationsByProductID(c.Request.Context(), productID)
+	if err != nil {
+		sendTranslationError(c, err, "Failed to retrieve translations")
+		return
+	}
+
+	c.JSON(http.StatusOK, translations)
+}
+
+// DeleteTranslation godoc
+// @Summary Delete a product translation
+// @Description Delete the translation of a product in a locale
+// @Tags translations
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param locale path string true "BCP 47 locale"
+// @Success 204 {} {}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /products/{id}/translations/{locale} [delete]
+func (h *TranslationHandler) DeleteTranslation(c *gin.Context) {
+	productID, locale, ok := h.parseTranslationPath(c)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.DeleteTranslation(c.Request.Context(), productID, locale); err != nil {
+		sendTranslationError(c, err, "Failed to delete translation")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// parseTranslationPath parses the product ID and canonical locale from the path,
+// sending a 400 response and returning false if either is invalid.
+func (h *TranslationHandler) parseTranslationPath(c *gin.Context) (int, string, bool) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid ID: "+c.Param("id"))
+		return 0, "", false
+	}
+	tag, err := language.Parse(c.Param("locale"))
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid locale: "+c.Param("locale"))
+		return 0, "", false
+	}
+	return productID, tag.String(), true
+}
+
+// sendTranslationError maps repository errors to HTTP responses.
+func sendTranslationError(c *gin.Context, err error, fallback string) {
+	if errors.Is(err, repository.ErrNotFound) {
+		utils.SendErrorResponse(c, http.StatusNotFound, err.Error())
+	} else {
+		utils.SendErrorResponse(c, http.StatusInternalServerError, fallback)
+	}
+}
+
+var wildcardLocale = language.Make("mul")
+
+// preferredLocales returns the locales a request accepts, most preferred first, taken from
+// ?locale= or else the Accept-Language header. Each regional locale is followed by its language
+// ("de-AT", then "de"), and the list stops at defaultLocale, since products are already named
+// and described in it. It returns an error only for an invalid ?locale=.
+func preferredLocales(c *gin.Context, defaultLocale string) ([]string, error) {
+	var tags []language.Tag
+	if locale := c.Query("locale"); locale != "" {
+		tag, err := language.Parse(locale)
+		if err != nil {
+			return nil, err
+		}
+		tags = []language.Tag{tag}
+	} else {
+		// A malformed Accept-Language header is ignored rather than rejected.
+		tags, _, _ = language.ParseAcceptLanguage(c.GetHeader("Accept-Language"))
+	}
+
+	var locales []string
+	seen := map[string]bool{}
+	for _, tag := range tags {
+		// Skip the "*" wildcard, which parses as "mul", and unknown languages.
+		if tag == language.Und || tag == wildcardLocale {
+			continue
+		}
+		candidates := []string{tag.String()}
+		if base, confidence := tag.Base(); confidence != language.No && base.String() != tag.String() {
+			candidates = append(candidates, base.String())
+		}
+		for _, locale := range candidates {
+			if locale == defaultLocale {
+				return locales, nil
+			}
+			if !seen[locale] {
+				seen[locale] = true
+				locales = append(locales, locale)
+			}
+		}
+	}
+	return locales, nil
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/mariosker/products_rest_api/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTranslationHandler_PutTranslation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.Default()
+	mockRepo := new(MockTranslationRepository)
+	handler := NewTranslationHandler(mockRepo, "en")
+
+	router.PUT("/products/:id/translations/:locale", handler.PutTranslation)
+
+	t.Run("Success", func(t *testing.T) {
+		translation := &models.ProductTranslation{ProductID: 1, Locale: "pt-BR", Name: "Caneca", Description: "Caneca de cerâmica"}
+		mockRepo.On("PutTranslation", mock.Anything, translation).Return(nil).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/products/1/translations/pt-br", strings.NewReader(`{"name":"Caneca","description":"Caneca de cerâmica"}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"product_id":1,"locale":"pt-BR","name":"Caneca","description":"Caneca de cerâmica"}`, w.Body.String())
+	})
+
+	t.Run("Default Locale", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/products/1/translations/en", strings.NewReader(`{"name":"Mug"}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Invalid Locale", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/products/1/translations/not_a_locale!", strings.NewReader(`{"name":"Mug"}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "Invalid locale")
+	})
+
+	t.Run("Missing Name", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/products/1/translations/de", strings.NewReader(`{"description":"Becher"}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Product Not Found", func(t *testing.T) {
+		translation := &models.ProductTranslation{ProductID: 9, Locale: "de", Name: "Becher"}
+		errRepo := fmt.Errorf("product with ID %d: %w", 9, repository.ErrNotFound)
+		mockRepo.On("PutTranslation", mock.Anything, translation).Return(errRepo).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/products/9/translations/de", strings.NewReader(`{"name":"Becher"}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestPreferredLocales(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		query          string
+		acceptLanguage string
+		want           []string
+		wantErr        bool
+	}{
+		{"No Preference", "", "", nil, false},
+		{"Accept-Language By Quality", "", "fr;q=0.5, de-AT, it;q=0.8", []string{"de-AT", "de", "it", "fr"}, false},
+		{"Stops At Default Locale", "", "de, en;q=0.9, fr;q=0.8", []string{"de"}, false},
+		{"Regional Default Locale", "", "en-GB, de;q=0.5", []string{"en-GB"}, false},
+		{"Query Overrides Header", "el", "de", []string{"el"}, false},
+		{"Wildcard Ignored", "", "*, de;q=0.5", []string{"de"}, false},
+		{"Malformed Header Ignored", "", "not a header;;;", nil, false},
+		{"Invalid Query", "x_invalid!", "", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request, _ = http.NewRequest("GET", "/products?locale="+tt.query, nil)
+			c.Request.Header.Set("Accept-Language", tt.acceptLanguage)
+
+			got, err := preferredLocales(c, "en")
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
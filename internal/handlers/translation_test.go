@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockTranslationRepository is a mock implementation of TranslationRepository.
+// It is used to simulate product translations in handler tests without a real database.
+type MockTranslationRepository struct {
+	mock.Mock
+}
+
+// PutTranslation mocks creating or replacing a product translation.
+func (m *MockTranslationRepository) PutTranslation(ctx context.Context, translation *models.ProductTranslation) error {
+	args := m.Called(ctx, translation)
+	return args.Error(0)
+}
+
+// GetTranslationsByProductID mocks retrieving every translation of a product.
+func (m *MockTranslationRepository) GetTranslationsByProductID(ctx context.Context, productID int) ([]*models.ProductTranslation, error) {
+	args := m.Called(ctx, productID)
+	if translations, ok := args.Get(0).([]*models.ProductTranslation); ok {
+		return translations, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// GetBestTranslations mocks retrieving the best translation of each product.
+func (m *MockTranslationRepository) GetBestTranslations(ctx context.Context, productIDs []int, locales []string) (map[int]*models.ProductTranslation, error) {
+	args := m.Called(ctx, productIDs, locales)
+	if translations, ok := args.Get(0).(map[int]*models.ProductTranslation); ok {
+		return translations, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// DeleteTranslation mocks deleting a product translation.
+func (m *MockTranslationRepository) DeleteTranslation(ctx context.Context, productID int, locale string) error {
+	args := m.Called(ctx, productID, locale)
+	return args.Error(0)
+}
@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/mariosker/products_rest_api/internal/repository"
+	"github.com/mariosker/products_rest_api/internal/utils"
+)
+
+// VariantHandler handles HTTP requests for managing the variants of a product.
+type VariantHandler struct {
+	repo repository.VariantRepository
+}
+
+// NewVariantHandler creates a new VariantHandler with the given repository.
+func NewVariantHandler(repo repository.VariantRepository) *VariantHandler {
+	return &VariantHandler{repo: repo}
+}
+
+// CreateVariant godoc
+// @Summary Create a product variant
+// @Description Create a new variant with its own SKU, options, price override and stock
+// @Tags variants
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param variant body models.ProductVariantPayload true "Variant Payload"
+// @Success 201 {object} models.ProductVariant
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /products/{id}/variants [post]
+func (h *VariantHandler) CreateVariant(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid ID: "+c.Param("id"))
+		return
+	}
+
+	var payload models.ProductVariantPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	variant, err := h.repo.CreateVariant(c.Request.Context(), productID, &payload)
+	if err != nil {
+		sendVariantError(c, err, "Failed to create variant")
+		return
+	}
+
+	c.JSON(http.StatusCreated, variant)
+}
+
+// GetVariants godoc
+// @Summary List product variants
+// @Description Retrieve every variant of a product
+// @Tags variants
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {array} models.ProductVariant
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /products/{id}/variants [get]
+func (h *VariantHandler) GetVariants(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid ID: "+c.Param("id"))
+		return
+	}
+
+	variants, err := h.repo.GetVariantsByProductID(c.Request.Context(), productID)
+	if err != nil {
+		sendVariantError(c, err, "Failed to retrieve variants")
+		return
+	}
+
+	c.JSON(http.StatusOK, variants)
+}
+
+// GetVariant godoc
+// @Summary Get a product variant
+// @Description Retrieve a single variant of a product
+// @Tags variants
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param variantId path int true "Variant ID"
+// @Success 200 {object} models.ProductVariant
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /products/{id}/variants/{variantId} [get]
+func (h *VariantHandler) GetVariant(c *gin.Context) {
+	productID, variantID, ok := parseVariantPath(c)
+	if !ok {
+		return
+	}
+
+	variant, err := h.repo.GetVariantByID(c.Request.Context(), productID, variantID)
+	if err != nil {
+		sendVariantError(c, err, "Failed to retrieve variant")
+		return
+	}
+
+	c.JSON(http.StatusOK, variant)
+}
+
+// UpdateVariant godoc
+// @Summary Update a product variant
+// @Description Replace the SKU, options, price override and stock of a variant
+// @Tags variants
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param variantId path int true "Variant ID"
+// @Param variant body models.ProductVariantPayload true "Variant Payload"
+// @Success 200 {object} models.ProductVariant
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /products/{id}/variants/{variantId} [put]
+func (h *VariantHandler) UpdateVariant(c *gin.Context) {
+	productID, variantID, ok := parseVariantPath(c)
+	if !ok {
+		return
+	}
+
+	var payload models.ProductVariantPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	variant, err := h.repo.UpdateVariant(c.Request.Context(), productID, variantID, &payload)
+	if err != nil {
+		sendVariantError(c, err, "Failed to update variant with id: "+strconv.Itoa(variantID))
+		return
+	}
+
+	c.JSON(http.StatusOK, variant)
+}
+
+// DeleteVariant godoc
+// @Summary Delete a product variant
+// @Description Delete a single variant of a product
+// @Tags variants
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param variantId path int true "Variant ID"
+// @Success 204 {} {}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /products/{id}/variants/{variantId} [delete]
+func (h *VariantHandler) DeleteVariant(c *gin.Context) {
+	productID, variantID, ok := parseVariantPath(c)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.DeleteVariant(c.Request.Context(), productID, variantID); err != nil {
+		sendVariantError(c, err, "Failed to delete variant with id: "+strconv.Itoa(variantID))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// parseVariantPath parses the product and variant IDs from the path,
+// sending a 400 response and returning false if either is invalid.
+func parseVariantPath(c *gin.Context) (int, int, bool) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid ID: "+c.Param("id"))
+		return 0, 0, false
+	}
+	variantID, err := strconv.Atoi(c.Param("variantId"))
+	if err != nil {
+		utils.SendErrorResponse(c, http.StatusBadRequest, "Invalid variant ID: "+c.Param("variantId"))
+		return 0, 0, false
+	}
+	return productID, variantID, true
+}
+
+// sendVariantError maps repository errors to HTTP responses.
+func sendVariantError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		utils.SendErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, repository.ErrConflict):
+		utils.SendErrorResponse(c, http.StatusConflict, err.Error())
+	case errors.Is(err, repository.ErrVariantOptionsMismatch):
+		utils.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+	default:
+		utils.SendErrorResponse(c, http.StatusInternalServerError, fallback)
+	}
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/mariosker/products_rest_api/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestVariantHandler_CreateVariant(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.Default()
+	mockRepo := new(MockVariantRepository)
+	handler := NewVariantHandler(mockRepo)
+
+	router.POST("/products/:id/variants", handler.CreateVariant)
+
+	price := 12.5
+
+	t.Run("Success", func(t *testing.T) {
+		payload := &models.ProductVariantPayload{SKU: "TS-L-BLUE", Options: map[string]string{"size": "L", "colour": "blue"}, Price: &price, Stock: 4}
+		variant := &models.ProductVariant{ID: 1, ProductID: 2, SKU: "TS-L-BLUE", Options: payload.Options, Price: &price, EffectivePrice: price, Stock: 4}
+		mockRepo.On("CreateVariant", mock.Anything, 2, payload).Return(variant, nil).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/products/2/variants", strings.NewReader(`{"sku":"TS-L-BLUE","options":{"size":"L","colour":"blue"},"price":12.5,"stock":4}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), `"effective_price":12.5`)
+	})
+
+	t.Run("Invalid ID", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/products/invalid/variants", strings.NewReader(`{"sku":"TS-L-BLUE","options":{"size":"L"}}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Missing Options", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/products/2/variants", strings.NewReader(`{"sku":"TS-L-BLUE","options":{}}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Negative Price", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/products/2/variants", strings.NewReader(`{"sku":"TS-L-BLUE","options":{"size":"L"},"price":-1}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Product Not Found", func(t *testing.T) {
+		mockRepo.On("CreateVariant", mock.Anything, 9, mock.Anything).Return(nil, repository.ErrNotFound).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/products/9/variants", strings.NewReader(`{"sku":"X-1","options":{"size":"L"}}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Duplicate SKU", func(t *testing.T) {
+		mockRepo.On("CreateVariant", mock.Anything, 3, mock.Anything).Return(nil, repository.ErrConflict).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/products/3/variants", strings.NewReader(`{"sku":"TS-L-BLUE","options":{"size":"L"}}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("Mismatched Option Axes", func(t *testing.T) {
+		mockRepo.On("CreateVariant", mock.Anything, 4, mock.Anything).Return(nil, repository.ErrVariantOptionsMismatch).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/products/4/variants", strings.NewReader(`{"sku":"TS-XL","options":{"length":"long"}}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Creation Error", func(t *testing.T) {
+		mockRepo.On("CreateVariant", mock.Anything, 5, mock.Anything).Return(nil, errors.New("database error")).Times(1)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/products/5/variants", strings.NewReader(`{"sku":"TS-S","options":{"size":"S"}}`))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), "Failed to create variant")
+	})
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockVariantRepository is a mock implementation of VariantRepository.
+// It is used to simulate product variants in handler tests without a real database.
+type MockVariantRepository struct {
+	mock.Mock
+}
+
+// CreateVariant mocks the creation of a variant for a product.
+func (m *MockVariantRepository) CreateVariant(ctx context.Context, productID int, payload *models.ProductVariantPayload) (*models.ProductVariant, error) {
+	args := m.Called(ctx, productID, payload)
+	if variant, ok := args.Get(0).(*models.ProductVariant); ok {
+		return variant, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// GetVariantByID mocks retrieving a single variant of a product.
+func (m *MockVariantRepository) GetVariantByID(ctx context.Context, productID, variantID int) (*models.ProductVariant, error) {
+	args := m.Called(ctx, productID, variantID)
+	if variant, ok := args.Get(0).(*models.ProductVariant); ok {
+		return variant, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// GetVariantsByProductID mocks retrieving every variant of a product.
+func (m *MockVariantRepository) GetVariantsByProductID(ctx context.Context, productID int) ([]*models.ProductVariant, error) {
+	args := m.Called(ctx, productID)
+	if variants, ok := args.Get(0).([]*models.ProductVariant); ok {
+		return variants, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// UpdateVariant mocks updating a variant of a product.
+func (m *MockVariantRepository) UpdateVariant(ctx context.Context, productID, variantID int, payload *models.ProductVariantPayload) (*models.ProductVariant, error) {
+	args := m.Called(ctx, productID, variantID, payload)
+	if variant, ok := args.Get(0).(*models.ProductVariant); ok {
+		return variant, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// DeleteVariant mocks deleting a variant of a product.
+func (m *MockVariantRepository) DeleteVariant(ctx context.Context, productID, variantID int) error {
+	args := m.Called(ctx, productID, variantID)
+	return args.Error(0)
+}
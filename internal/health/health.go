@@ -0,0 +1,129 @@
+// Package health reports whether the API is alive and whether it is ready to serve traffic.
+package health
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Check statuses reported by readiness.
+const (
+	StatusOK       = "ok"
+	StatusFailing  = "failing"
+	StatusDraining = "draining"
+)
+
+// Check reports whether a dependency can be used, returning an error that says why not.
+type Check func(ctx context.Context) error
+
+// Report is the outcome of the readiness checks.
+type Report struct {
+	Status string                 `json:"status" example:"ok"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+// CheckResult is the outcome of a single readiness check.
+type CheckResult struct {
+	Status     string `json:"status" example:"ok"`
+	Error      string `json:"error,omitempty"`
+	DurationMS int64  `json:"duration_ms" example:"2"`
+}
+
+// Checker serves the liveness and readiness probes of the API.
+type Checker struct {
+	timeout  time.Duration
+	checks   map[string]Check
+	draining atomic.Bool
+}
+
+// NewChecker creates a checker whose readiness checks each fail when they take longer than timeout.
+func NewChecker(timeout time.Duration) *Checker {
+	return &Checker{timeout: timeout, checks: make(map[string]Check)}
+}
+
+// Add adds a readiness check. Checks must all be added before the probes are served.
+func (h *Checker) Add(name string, check Check) {
+	h.checks[name] = check
+}
+
+// Drain makes readiness fail from now on, so load balancers stop sending requests to an instance
+// that is shutting down while it finishes the requests it has.
+func (h *Checker) Drain() {
+	h.draining.Store(true)
+}
+
+// Live godoc
+// @Summary Liveness probe
+// @Description Reports that the process is running. It does not check any dependencies, so it only fails when the API cannot serve requests at all.
+// @Tags health
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Router /livez [get]
+func (h *Checker) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": StatusOK})
+}
+
+// Ready godoc
+// @Summary Readiness probe
+// @Description Checks that the database can be reached and its migrations are applied, reporting the outcome of each check. Readiness fails while the API is shutting down.
+// @Tags health
+// @Produce json
+// @Success 200 {object} health.Report
+// @Failure 503 {object} health.Report
+// @Router /readyz [get]
+func (h *Checker) Ready(c *gin.Context) {
+	report := h.Run(c.Request.Context())
+	status := http.StatusOK
+	if report.Status != StatusOK {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
+
+// Run runs every readiness check at once and reports their outcomes.
+func (h *Checker) Run(ctx context.Context) Report {
+	report := Report{Status: StatusOK, Checks: make(map[string]CheckResult, len(h.checks))}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range h.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result := h.run(ctx, check)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[name] = result
+			if result.Status != StatusOK {
+				report.Status = StatusFailing
+			}
+		}()
+	}
+	wg.Wait()
+
+	if h.draining.Load() {
+		report.Status = StatusDraining
+	}
+	return report
+}
+
+// run runs a single check within the timeout.
+func (h *Checker) run(ctx context.Context, check Check) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check(ctx)
+	result := CheckResult{Status: StatusOK, DurationMS: time.Since(start).Milliseconds()}
+	if err != nil {
+		result.Status = StatusFailing
+		result.Error = err.Error()
+	}
+	return result
+}
@@ -0,0 +1,90 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecker(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ok := func(ctx context.Context) error { return nil }
+	down := func(ctx context.Context) error { return errors.New("connection refused") }
+	hangs := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	send := func(checker *Checker, path string) (int, Report) {
+		router := gin.New()
+		router.GET("/livez", checker.Live)
+		router.GET("/readyz", checker.Ready)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		router.ServeHTTP(w, req)
+
+		var report Report
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+		return w.Code, report
+	}
+
+	t.Run("Ready", func(t *testing.T) {
+		checker := NewChecker(time.Second)
+		checker.Add("database", ok)
+		checker.Add("migrations", ok)
+
+		code, report := send(checker, "/readyz")
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, StatusOK, report.Status)
+		assert.Equal(t, StatusOK, report.Checks["database"].Status)
+		assert.Equal(t, StatusOK, report.Checks["migrations"].Status)
+	})
+
+	t.Run("Failing Check", func(t *testing.T) {
+		checker := NewChecker(time.Second)
+		checker.Add("database", down)
+		checker.Add("migrations", ok)
+
+		code, report := send(checker, "/readyz")
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, StatusFailing, report.Status)
+		assert.Equal(t, CheckResult{Status: StatusFailing, Error: "connection refused"}, report.Checks["database"])
+		assert.Equal(t, StatusOK, report.Checks["migrations"].Status)
+
+		code, report = send(checker, "/livez")
+		assert.Equal(t, http.StatusOK, code, "liveness should not depend on the database")
+		assert.Equal(t, StatusOK, report.Status)
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		checker := NewChecker(10 * time.Millisecond)
+		checker.Add("database", hangs)
+
+		code, report := send(checker, "/readyz")
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks["database"].Error)
+	})
+
+	t.Run("Draining", func(t *testing.T) {
+		checker := NewChecker(time.Second)
+		checker.Add("database", ok)
+		checker.Drain()
+
+		code, report := send(checker, "/readyz")
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, StatusDraining, report.Status)
+
+		code, _ = send(checker, "/livez")
+		assert.Equal(t, http.StatusOK, code)
+	})
+}
@@ -0,0 +1,47 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/mariosker/products_rest_api/internal/database"
+)
+
+// Pinger is a database connection that can be pinged, such as *pgxpool.Pool or *pgx.Conn.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// Database checks that the database can be reached.
+func Database(db Pinger) Check {
+	return func(ctx context.Context) error {
+		return db.Ping(ctx)
+	}
+}
+
+// Migrations checks that the database schema is at version or later, and that no migration failed
+// halfway. Later versions are accepted so that instances still running the previous release stay
+// ready while a newer one migrates the database during a rolling deploy.
+func Migrations(db database.DBConnection, version uint) Check {
+	return func(ctx context.Context) error {
+		ctx = database.WithOperation(ctx, "Migrations")
+		var applied int64
+		var dirty bool
+		err := db.QueryRow(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&applied, &dirty)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errors.New("no migrations have been applied")
+		}
+		if err != nil {
+			return err
+		}
+		if dirty {
+			return fmt.Errorf("migration %d failed and must be fixed by hand", applied)
+		}
+		if applied < int64(version) {
+			return fmt.Errorf("schema is at version %d, expected %d", applied, version)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// unmatchedRoute is the route label of requests that match no route, so that made-up paths do not
+// each create a series.
+const unmatchedRoute = "unmatched"
+
+// otherMethod is the method label of requests with a method outside of knownMethods, for the same reason.
+const otherMethod = "OTHER"
+
+// knownMethods are the request methods that are labelled as themselves.
+var knownMethods = map[string]bool{
+	http.MethodGet: true, http.MethodHead: true, http.MethodPost: true, http.MethodPut: true, http.MethodPatch: true,
+	http.MethodDelete: true, http.MethodConnect: true, http.MethodOptions: true, http.MethodTrace: true,
+}
+
+// HTTP returns middleware that counts requests and measures their latency by method, route
+// template and status, and tracks how many requests are in flight.
+func HTTP(r *Registry) gin.HandlerFunc {
+	requests := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Requests served, by method, route and status.",
+	}, []string{"method", "route", "status"})
+	durations := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Time taken to serve requests, by method, route and status.",
+		Buckets: DefaultBuckets,
+	}, []string{"method", "route", "status"})
+	inFlight := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "http_requests_in_flight",
+		Help: "Requests being served.",
+	})
+	r.MustRegister(requests, durations, inFlight)
+
+	return func(c *gin.Context) {
+		start := time.Now()
+		inFlight.Inc()
+		panicked := true
+		defer func() {
+			inFlight.Dec()
+
+			method := c.Request.Method
+			if !knownMethods[method] {
+				method = otherMethod
+			}
+			route := c.FullPath()
+			if route == "" {
+				route = unmatchedRoute
+			}
+			code := c.Writer.Status()
+			// A panic is only turned into a 500 response by gin.Recovery after it has passed here.
+			if panicked && !c.Writer.Written() {
+				code = http.StatusInternalServerError
+			}
+			status := strconv.Itoa(code)
+			requests.WithLabelValues(method, route, status).Inc()
+			durations.WithLabelValues(method, route, status).Observe(time.Since(start).Seconds())
+		}()
+
+		c.Next()
+		panicked = false
+	}
+}
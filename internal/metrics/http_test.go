@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := NewRegistry()
+	router := gin.New()
+	router.Use(HTTP(r))
+	router.GET("/products/:id", func(c *gin.Context) { c.Status(http.StatusNotFound) })
+
+	for _, path := range []string{"/products/1", "/products/2", "/made-up"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		router.ServeHTTP(w, req)
+	}
+
+	body := scrape(r).Body.String()
+	assert.Contains(t, body, `http_requests_total{method="GET",route="/products/:id",status="404"} 2`)
+	assert.Contains(t, body, `http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+	assert.Contains(t, body, `http_request_duration_seconds_count{method="GET",route="/products/:id",status="404"} 2`)
+	assert.Contains(t, body, "http_requests_in_flight 0\n")
+}
+
+func TestHTTP_Panic(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := NewRegistry()
+	router := gin.New()
+	router.Use(gin.Recovery(), HTTP(r))
+	router.GET("/panic", func(c *gin.Context) { panic("boom") })
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/panic", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	body := scrape(r).Body.String()
+	assert.Contains(t, body, `http_requests_total{method="GET",route="/panic",status="500"} 1`)
+	assert.Contains(t, body, "http_requests_in_flight 0\n")
+}
+
+func TestHTTP_UnknownMethod(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := NewRegistry()
+	router := gin.New()
+	router.Use(HTTP(r))
+
+	for _, method := range []string{"FOO", "BAR"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, "/products", nil)
+		router.ServeHTTP(w, req)
+	}
+
+	body := scrape(r).Body.String()
+	assert.Contains(t, body, `http_requests_total{method="OTHER",route="unmatched",status="404"} 2`)
+	assert.NotContains(t, body, `method="FOO"`)
+}
@@ -0,0 +1,139 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mariosker/products_rest_api/internal/database"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// otherOperation is the method label of queries made by unnamed operations.
+const otherOperation = "other"
+
+// QueryTracer is a pgx query tracer that measures how long queries take, by the method that made them.
+type QueryTracer struct {
+	durations *prometheus.HistogramVec
+}
+
+type queryStartKey struct{}
+
+type queryStart struct {
+	method string
+	start  time.Time
+}
+
+// NewQueryTracer creates and registers a histogram of query durations. Queries are attributed to
+// the operation that made them, as named with database.WithOperation.
+func NewQueryTracer(r *Registry) *QueryTracer {
+	durations := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "db_query_duration_seconds",
+		Help:    "Time taken by database queries, by the method that made them.",
+		Buckets: DefaultBuckets,
+	}, []string{"method"})
+	r.MustRegister(durations)
+	return &QueryTracer{durations: durations}
+}
+
+// TraceQueryStart implements pgx.QueryTracer.
+func (t *QueryTracer) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	method := database.Operation(ctx)
+	if method == "" {
+		method = otherOperation
+	}
+	return context.WithValue(ctx, queryStartKey{}, queryStart{method: method, start: time.Now()})
+}
+
+// TraceQueryEnd implements pgx.QueryTracer. Queries that return rows end when the rows are closed.
+func (t *QueryTracer) TraceQueryEnd(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryEndData) {
+	if start, ok := ctx.Value(queryStartKey{}).(queryStart); ok {
+		t.durations.WithLabelValues(start.method).Observe(time.Since(start.start).Seconds())
+	}
+}
+
+// RegisterPool registers gauges and counters of the connections of pool.
+func RegisterPool(r *Registry, pool *pgxpool.Pool) {
+	gauge := func(name, help string, value func(*pgxpool.Stat) float64) {
+		r.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{Name: name, Help: help},
+			func() float64 { return value(pool.Stat()) }))
+	}
+	counter := func(name, help string, value func(*pgxpool.Stat) float64) {
+		r.MustRegister(prometheus.NewCounterFunc(prometheus.CounterOpts{Name: name, Help: help},
+			func() float64 { return value(pool.Stat()) }))
+	}
+
+	gauge("db_pool_connections_max", "Largest number of connections the pool opens.",
+		func(s *pgxpool.Stat) float64 { return float64(s.MaxConns()) })
+	gauge("db_pool_connections_open", "Connections open, whether in use, idle or being established.",
+		func(s *pgxpool.Stat) float64 { return float64(s.TotalConns()) })
+	gauge("db_pool_connections_acquired", "Connections in use.",
+		func(s *pgxpool.Stat) float64 { return float64(s.AcquiredConns()) })
+	gauge("db_pool_connections_idle", "Idle connections.",
+		func(s *pgxpool.Stat) float64 { return float64(s.IdleConns()) })
+	counter("db_pool_acquires_total", "Connections acquired from the pool.",
+		func(s *pgxpool.Stat) float64 { return float64(s.AcquireCount()) })
+	counter("db_pool_empty_acquires_total", "Acquires that had to wait for a connection because none was idle.",
+		func(s *pgxpool.Stat) float64 { return float64(s.EmptyAcquireCount()) })
+	counter("db_pool_acquire_wait_seconds_total", "Time spent acquiring connections.",
+		func(s *pgxpool.Stat) float64 { return s.AcquireDuration().Seconds() })
+}
+
+// catalogCollectTimeout bounds the queries that count the catalogue on a scrape.
+const catalogCollectTimeout = 5 * time.Second
+
+// catalogCollector counts the products of the catalogue by status on every scrape.
+type catalogCollector struct {
+	db       database.DBConnection
+	products *prometheus.Desc
+}
+
+// RegisterCatalog registers gauges of the contents of the catalogue across every tenant, counted
+// on every scrape.
+func RegisterCatalog(r *Registry, db database.DBConnection) {
+	r.MustRegister(&catalogCollector{
+		db:       db,
+		products: prometheus.NewDesc("catalog_products", "Products in the catalogue, by status.", []string{"status"}, nil),
+	})
+}
+
+// Describe implements prometheus.Collector.
+func (c *catalogCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.products
+}
+
+// Collect implements prometheus.Collector. When the catalogue cannot be counted, the error is
+// reported in place of the gauge.
+func (c *catalogCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(database.WithOperation(context.Background(), "RegisterCatalog"), catalogCollectTimeout)
+	defer cancel()
+
+	counts, err := c.countProducts(ctx)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.products, err)
+		return
+	}
+	for status, count := range counts {
+		ch <- prometheus.MustNewConstMetric(c.products, prometheus.GaugeValue, float64(count), status)
+	}
+}
+
+func (c *catalogCollector) countProducts(ctx context.Context) (map[string]int64, error) {
+	rows, err := c.db.Query(ctx, `SELECT status, count(*) FROM products GROUP BY status`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int64)
+	for rows.Next() {
+		var status string
+		var count int64
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, err
+		}
+		counts[status] = count
+	}
+	return counts, rows.Err()
+}
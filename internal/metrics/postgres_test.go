@@ -0,0 +1,29 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/mariosker/products_rest_api/internal/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryTracer(t *testing.T) {
+	t.Run("Attributed To Operation", func(t *testing.T) {
+		r := NewRegistry()
+		tracer := NewQueryTracer(r)
+
+		ctx := database.WithOperation(context.Background(), "PostgresProductRepository.GetProductByID")
+		ctx = tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "SELECT 1"})
+		tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{})
+		assert.Contains(t, scrape(r).Body.String(), `db_query_duration_seconds_count{method="PostgresProductRepository.GetProductByID"} 1`)
+	})
+
+	t.Run("Unnamed Operation", func(t *testing.T) {
+		tracer := NewQueryTracer(NewRegistry())
+
+		ctx := tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: "SELECT 1"})
+		assert.Equal(t, otherOperation, ctx.Value(queryStartKey{}).(queryStart).method)
+	})
+}
@@ -0,0 +1,40 @@
+// Package metrics collects metrics about the API with the Prometheus client library and serves
+// them on /metrics.
+package metrics
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// DefaultBuckets are the upper bounds, in seconds, of the buckets latency histograms count into.
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Registry holds the metrics served by its handler. Unlike the default Prometheus registry, it
+// only holds the metrics registered by this package.
+type Registry struct {
+	*prometheus.Registry
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{Registry: prometheus.NewRegistry()}
+}
+
+// Handler serves the metrics of the registry. Metrics whose collection fails are left out of the
+// response, so one unreachable dependency does not hide every other metric.
+func (r *Registry) Handler() http.Handler {
+	return promhttp.HandlerFor(r.Registry, promhttp.HandlerOpts{
+		ErrorLog:      log.Default(),
+		ErrorHandling: promhttp.ContinueOnError,
+	})
+}
+
+// GinHandler serves the metrics of the registry on a gin route.
+func (r *Registry) GinHandler() gin.HandlerFunc {
+	return gin.WrapH(r.Handler())
+}
@@ -0,0 +1,57 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+func scrape(r *Registry) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/metrics", nil)
+	r.Handler().ServeHTTP(w, req)
+	return w
+}
+
+// failingCollector is a collector whose dependency is unreachable.
+type failingCollector struct {
+	desc *prometheus.Desc
+}
+
+func (c failingCollector) Describe(ch chan<- *prometheus.Desc) { ch <- c.desc }
+
+func (c failingCollector) Collect(ch chan<- prometheus.Metric) {
+	ch <- prometheus.NewInvalidMetric(c.desc, assert.AnError)
+}
+
+func TestRegistry(t *testing.T) {
+	t.Run("Text Exposition Format", func(t *testing.T) {
+		r := NewRegistry()
+		requests := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "requests_total", Help: "Requests served."}, []string{"route"})
+		r.MustRegister(requests)
+		requests.WithLabelValues("/products/:id").Add(3)
+
+		w := scrape(r)
+		assert.Contains(t, w.Header().Get("Content-Type"), "text/plain; version=0.0.4")
+		assert.Equal(t, `# HELP requests_total Requests served.
+# TYPE requests_total counter
+requests_total{route="/products/:id"} 3
+`, w.Body.String())
+	})
+
+	t.Run("Failing Collector", func(t *testing.T) {
+		r := NewRegistry()
+		r.MustRegister(failingCollector{desc: prometheus.NewDesc("products", "Products.", nil, nil)})
+		requests := prometheus.NewCounter(prometheus.CounterOpts{Name: "requests_total", Help: "Requests served."})
+		r.MustRegister(requests)
+		requests.Inc()
+
+		w := scrape(r)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, w.Body.String(), "products")
+		assert.Contains(t, w.Body.String(), "requests_total 1\n")
+	})
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mariosker/products_rest_api/internal/utils"
+)
+
+// BodyLimit rejects request bodies larger than maxBytes with 413 Request Entity Too Large. Bodies
+// are read before the handler runs, so oversized ones are rejected however the handler reads them.
+// Routes in ownLimits, such as image uploads, enforce a limit of their own and are skipped.
+func BodyLimit(maxBytes int64, ownLimits ...string) gin.HandlerFunc {
+	tooLarge := "Request body must not be larger than " + strconv.FormatInt(maxBytes, 10) + " bytes"
+
+	return func(c *gin.Context) {
+		if c.Request.Body == nil || c.Request.Body == http.NoBody || slices.Contains(ownLimits, c.FullPath()) {
+			c.Next()
+			return
+		}
+		if c.Request.ContentLength > maxBytes {
+			utils.SendErrorResponse(c, http.StatusRequestEntityTooLarge, tooLarge)
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxBytes+1))
+		if err != nil {
+			utils.SendErrorResponse(c, http.StatusBadRequest, "Failed to read request body")
+			c.Abort()
+			return
+		}
+		if int64(len(body)) > maxBytes {
+			utils.SendErrorResponse(c, http.StatusRequestEntityTooLarge, tooLarge)
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		c.Next()
+	}
+}
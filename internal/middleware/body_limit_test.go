@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBodyLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(BodyLimit(16, "/products/:id/images"))
+	echo := func(c *gin.Context) {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		c.String(http.StatusOK, "%s", body)
+	}
+	router.POST("/products", echo)
+	router.POST("/products/:id/images", echo)
+
+	send := func(path, body string, chunked bool) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", path, strings.NewReader(body))
+		if chunked {
+			req.ContentLength = -1
+		}
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		body    string
+		chunked bool
+		status  int
+	}{
+		{name: "Within Limit", path: "/products", body: `{"name":"Mug"}`, status: http.StatusOK},
+		{name: "At Limit", path: "/products", body: strings.Repeat("a", 16), status: http.StatusOK},
+		{name: "Too Large", path: "/products", body: strings.Repeat("a", 17), status: http.StatusRequestEntityTooLarge},
+		{name: "Too Large Without Content-Length", path: "/products", body: strings.Repeat("a", 17), chunked: true, status: http.StatusRequestEntityTooLarge},
+		{name: "Route With Own Limit", path: "/products/1/images", body: strings.Repeat("a", 17), status: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := send(tt.path, tt.body, tt.chunked)
+			assert.Equal(t, tt.status, w.Code)
+			if tt.status == http.StatusOK {
+				assert.Equal(t, tt.body, w.Body.String())
+			} else {
+				assert.Contains(t, w.Body.String(), "Request body must not be larger than 16 bytes")
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+// Package middleware provides the HTTP middleware that protects every route: CORS, security
+// headers and request body limits.
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mariosker/products_rest_api/internal/utils"
+)
+
+// CORSOptions configures which browser origins may call the API.
+type CORSOptions struct {
+	// AllowedOrigins are the origins allowed to make requests, such as "https://admin.example.com",
+	// or "*" for any origin.
+	AllowedOrigins []string
+	AllowedMethods []string
+	AllowedHeaders []string
+	// ExposedHeaders are the response headers browsers let scripts read.
+	ExposedHeaders []string
+	// AllowCredentials lets browsers send cookies and HTTP authentication with requests.
+	AllowCredentials bool
+	// MaxAge is how long browsers may cache the result of a preflight request.
+	MaxAge time.Duration
+}
+
+// CORS answers preflight requests and adds CORS headers to the responses to allowed origins.
+// It must run before authentication, since browsers send preflight requests without credentials.
+func CORS(opts CORSOptions) gin.HandlerFunc {
+	anyOrigin := slices.Contains(opts.AllowedOrigins, "*")
+	methods := strings.Join(opts.AllowedMethods, ", ")
+	headers := strings.Join(opts.AllowedHeaders, ", ")
+	exposed := strings.Join(opts.ExposedHeaders, ", ")
+	maxAge := strconv.Itoa(int(opts.MaxAge.Seconds()))
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if origin == "" {
+			c.Next()
+			return
+		}
+		c.Writer.Header().Add("Vary", "Origin")
+
+		allowed := anyOrigin || slices.Contains(opts.AllowedOrigins, origin)
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+		if preflight {
+			if !allowed {
+				utils.SendErrorResponse(c, http.StatusForbidden, "Origin "+origin+" is not allowed")
+				c.Abort()
+				return
+			}
+			if method := c.GetHeader("Access-Control-Request-Method"); !slices.Contains(opts.AllowedMethods, method) {
+				utils.SendErrorResponse(c, http.StatusForbidden, "Method "+method+" is not allowed")
+				c.Abort()
+				return
+			}
+			for _, header := range strings.Split(c.GetHeader("Access-Control-Request-Headers"), ",") {
+				if header = strings.TrimSpace(header); header != "" && !containsFold(opts.AllowedHeaders, header) {
+					utils.SendErrorResponse(c, http.StatusForbidden, "Header "+header+" is not allowed")
+					c.Abort()
+					return
+				}
+			}
+		}
+		if !allowed {
+			c.Next()
+			return
+		}
+
+		if anyOrigin && !opts.AllowCredentials {
+			c.Header("Access-Control-Allow-Origin", "*")
+		} else {
+			c.Header("Access-Control-Allow-Origin", origin)
+		}
+		if opts.AllowCredentials {
+			c.Header("Access-Control-Allow-Credentials", "true")
+		}
+
+		if preflight {
+			c.Header("Access-Control-Allow-Methods", methods)
+			c.Header("Access-Control-Allow-Headers", headers)
+			c.Header("Access-Control-Max-Age", maxAge)
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+		if exposed != "" {
+			c.Header("Access-Control-Expose-Headers", exposed)
+		}
+		c.Next()
+	}
+}
+
+// containsFold reports whether values contains value, ignoring case as header names do.
+func containsFold(values []string, value string) bool {
+	return slices.ContainsFunc(values, func(v string) bool { return strings.EqualFold(v, value) })
+}
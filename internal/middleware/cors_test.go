@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCORS(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func(opts CORSOptions) *gin.Engine {
+		router := gin.New()
+		router.Use(CORS(opts))
+		router.GET("/products", func(c *gin.Context) { c.Status(http.StatusOK) })
+		return router
+	}
+	opts := CORSOptions{
+		AllowedOrigins: []string{"https://admin.example.com"},
+		AllowedMethods: []string{"GET", "POST"},
+		AllowedHeaders: []string{"Authorization", "Content-Type"},
+		ExposedHeaders: []string{"RateLimit-Remaining"},
+		MaxAge:         10 * time.Minute,
+	}
+	send := func(router *gin.Engine, method, origin string, headers map[string]string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, "/products", nil)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		for key, value := range headers {
+			req.Header.Set(key, value)
+		}
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Allowed Origin", func(t *testing.T) {
+		w := send(newRouter(opts), "GET", "https://admin.example.com", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "https://admin.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "RateLimit-Remaining", w.Header().Get("Access-Control-Expose-Headers"))
+		assert.Equal(t, "Origin", w.Header().Get("Vary"))
+	})
+
+	t.Run("Other Origin", func(t *testing.T) {
+		w := send(newRouter(opts), "GET", "https://evil.example.com", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("Same Origin", func(t *testing.T) {
+		w := send(newRouter(opts), "GET", "", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("Vary"))
+	})
+
+	t.Run("Preflight", func(t *testing.T) {
+		w := send(newRouter(opts), "OPTIONS", "https://admin.example.com", map[string]string{
+			"Access-Control-Request-Method":  "POST",
+			"Access-Control-Request-Headers": "authorization, content-type",
+		})
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, "https://admin.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "GET, POST", w.Header().Get("Access-Control-Allow-Methods"))
+		assert.Equal(t, "Authorization, Content-Type", w.Header().Get("Access-Control-Allow-Headers"))
+		assert.Equal(t, "600", w.Header().Get("Access-Control-Max-Age"))
+	})
+
+	t.Run("Preflight Rejected", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			origin  string
+			headers map[string]string
+		}{
+			{name: "Origin", origin: "https://evil.example.com", headers: map[string]string{"Access-Control-Request-Method": "GET"}},
+			{name: "Method", origin: "https://admin.example.com", headers: map[string]string{"Access-Control-Request-Method": "DELETE"}},
+			{name: "Header", origin: "https://admin.example.com", headers: map[string]string{
+				"Access-Control-Request-Method":  "GET",
+				"Access-Control-Request-Headers": "X-Debug",
+			}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				w := send(newRouter(opts), "OPTIONS", tt.origin, tt.headers)
+				assert.Equal(t, http.StatusForbidden, w.Code)
+				assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+			})
+		}
+	})
+
+	t.Run("Any Origin", func(t *testing.T) {
+		anyOrigin := opts
+		anyOrigin.AllowedOrigins = []string{"*"}
+		w := send(newRouter(anyOrigin), "GET", "https://shop.example.com", nil)
+		assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+
+		anyOrigin.AllowCredentials = true
+		w = send(newRouter(anyOrigin), "GET", "https://shop.example.com", nil)
+		assert.Equal(t, "https://shop.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+	})
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecurityHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	send := func(opts SecurityHeadersOptions) http.Header {
+		router := gin.New()
+		router.Use(SecurityHeaders(opts))
+		router.GET("/products", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/products", nil)
+		router.ServeHTTP(w, req)
+		return w.Header()
+	}
+
+	t.Run("HSTS", func(t *testing.T) {
+		header := send(SecurityHeadersOptions{HSTSMaxAge: 365 * 24 * time.Hour, HSTSIncludeSubdomains: true, FrameOptions: "DENY"})
+		assert.Equal(t, "max-age=31536000; includeSubDomains", header.Get("Strict-Transport-Security"))
+		assert.Equal(t, "nosniff", header.Get("X-Content-Type-Options"))
+		assert.Equal(t, "DENY", header.Get("X-Frame-Options"))
+		assert.Equal(t, "no-referrer", header.Get("Referrer-Policy"))
+	})
+
+	t.Run("Without HSTS", func(t *testing.T) {
+		header := send(SecurityHeadersOptions{FrameOptions: "SAMEORIGIN"})
+		assert.Empty(t, header.Get("Strict-Transport-Security"))
+		assert.Equal(t, "SAMEORIGIN", header.Get("X-Frame-Options"))
+	})
+}
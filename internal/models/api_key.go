@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// APIKey defines a key clients authenticate with. Only a hash of the key itself is stored.
+// @Description APIKey defines a key clients authenticate with
+type APIKey struct {
+	ID   int    `json:"id" db:"id"`
+	Name string `json:"name" db:"name"`
+	// Prefix is the start of the key, to tell keys apart without revealing them.
+	Prefix string   `json:"prefix" db:"prefix"`
+	Scopes []string `json:"scopes" db:"scopes"`
+	Roles  []string `json:"roles,omitempty" db:"roles"`
+	// TenantID binds the key to one tenant; keys without one choose a tenant per request.
+	TenantID  string     `json:"tenant_id,omitempty" db:"tenant_id"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	RotatedAt *time.Time `json:"rotated_at,omitempty" db:"rotated_at"`
+}
+
+// IssuedAPIKey defines a newly issued or rotated API key, which is only ever returned once
+// @Description IssuedAPIKey defines a newly issued or rotated API key, which is only ever returned once
+type IssuedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+// APIKeyPayload defines the payload for issuing an API key
+// @Description APIKeyPayload defines the structure for issuing an API key
+type APIKeyPayload struct {
+	Name string `json:"name" binding:"required,max=100"`
+	// Scopes and Roles grant the key its permissions; at least one of them is required.
+	Scopes []string `json:"scopes,omitempty" binding:"omitempty,dive,oneof=products:read products:write products:price:write reviews:moderate admin"`
+	Roles  []string `json:"roles,omitempty" binding:"omitempty,max=16,dive,required,max=64"`
+	// TenantID binds the key to one tenant. Clients bound to a tenant can only issue keys for it,
+	// and their keys are bound to it when TenantID is omitted.
+	TenantID string `json:"tenant_id,omitempty"`
+	// ExpiresAt is when the key stops working; keys without one work until they are revoked.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// RotateAPIKeyPayload defines the optional payload for rotating an API key
+// @Description RotateAPIKeyPayload defines the structure for rotating an API key
+type RotateAPIKeyPayload struct {
+	// GraceSeconds keeps the old key working for this long after rotation, up to a week.
+	GraceSeconds int `json:"grace_seconds,omitempty" binding:"gte=0,lte=604800"`
+}
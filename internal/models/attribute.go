@@ -0,0 +1,63 @@
+package models
+
+// Attribute types
+const (
+	AttributeTypeString  = "string"
+	AttributeTypeNumber  = "number"
+	AttributeTypeBoolean = "boolean"
+)
+
+// AttributeDefinition defines a typed attribute that products of a category may carry
+// @Description AttributeDefinition defines a typed attribute that products of a category may carry
+type AttributeDefinition struct {
+	Category string `json:"category" db:"category"`
+	Name     string `json:"name" db:"name"`
+	Type     string `json:"type" db:"type"`
+	Required bool   `json:"required" db:"required"`
+	// AllowedValues restricts string attributes to an enumeration.
+	AllowedValues []string `json:"allowed_values,omitempty" db:"allowed_values"`
+	// Min and Max bound number attributes.
+	Min *float64 `json:"min,omitempty" db:"min_value"`
+	Max *float64 `json:"max,omitempty" db:"max_value"`
+}
+
+// AttributeDefinitionPayload defines the payload for creating or replacing an attribute definition
+// @Description AttributeDefinitionPayload defines the structure for creating or replacing an attribute definition
+type AttributeDefinitionPayload struct {
+	Type          string   `json:"type" binding:"required,oneof=string number boolean"`
+	Required      bool     `json:"required"`
+	AllowedValues []string `json:"allowed_values,omitempty" binding:"omitempty,dive,required"`
+	Min           *float64 `json:"min,omitempty"`
+	Max           *float64 `json:"max,omitempty"`
+}
+
+// Attribute filter operators
+const (
+	AttributeOpEq  = "eq"
+	AttributeOpLt  = "lt"
+	AttributeOpLte = "lte"
+	AttributeOpGt  = "gt"
+	AttributeOpGte = "gte"
+)
+
+// AttributeFilter matches products whose attribute Name compares to Value with Op.
+type AttributeFilter struct {
+	Name  string
+	Op    string
+	Value string
+}
+
+// ProductFilter narrows down the products returned by a listing.
+type ProductFilter struct {
+	Attributes []AttributeFilter
+	// Statuses restricts the listing to products in any of the statuses; empty means any status.
+	Statuses []string
+	// Sort orders the listing by one of the ProductSort values; empty leaves it unordered.
+	Sort string
+}
+
+// Product listing orders. Products without reviews are listed last when sorting by rating.
+const (
+	ProductSortRating     = "rating"
+	ProductSortRatingDesc = "-rating"
+)
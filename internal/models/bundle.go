@@ -0,0 +1,47 @@
+package models
+
+// Product types. A bundle is sold as a kit of other products.
+const (
+	ProductTypeSimple = "simple"
+	ProductTypeBundle = "bundle"
+)
+
+// Bundle pricing modes. A fixed bundle keeps its own price; a derived bundle costs the sum of
+// its components less its discount.
+const (
+	BundlePricingFixed   = "fixed"
+	BundlePricingDerived = "derived"
+)
+
+// Policies for deleting a product that is a component of a bundle.
+const (
+	// BundleDeleteRestrict refuses to delete the product.
+	BundleDeleteRestrict = "restrict"
+	// BundleDeleteCascade removes the product from its bundles and deletes it.
+	BundleDeleteCascade = "cascade"
+)
+
+// Bundle defines the components and pricing of a bundle product
+// @Description Bundle defines the components and pricing of a bundle product
+type Bundle struct {
+	ProductID int    `json:"product_id" db:"product_id"`
+	Pricing   string `json:"pricing" db:"bundle_pricing"`
+	// DiscountPercent is taken off the summed component prices of a derived bundle.
+	DiscountPercent float64           `json:"discount_percent,omitempty" db:"bundle_discount"`
+	Components      []BundleComponent `json:"components"`
+}
+
+// BundleComponent defines how many of a product go into a bundle
+// @Description BundleComponent defines how many of a product go into a bundle
+type BundleComponent struct {
+	ProductID int `json:"product_id" db:"component_id" binding:"required"`
+	Quantity  int `json:"quantity" db:"quantity" binding:"required,gt=0"`
+}
+
+// BundlePayload defines the payload for turning a product into a bundle or replacing its components
+// @Description BundlePayload defines the structure for turning a product into a bundle or replacing its components
+type BundlePayload struct {
+	Pricing         string            `json:"pricing" binding:"required,oneof=fixed derived"`
+	DiscountPercent float64           `json:"discount_percent,omitempty" binding:"gte=0,lt=100"`
+	Components      []BundleComponent `json:"components" binding:"required,min=1,max=50,dive"`
+}
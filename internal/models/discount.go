@@ -0,0 +1,98 @@
+package models
+
+// Discount rule types
+const (
+	// DiscountTypePercentage takes Value percent off the unit price.
+	DiscountTypePercentage = "percentage"
+	// DiscountTypeFixed takes Value off the unit price.
+	DiscountTypeFixed = "fixed"
+	// DiscountTypeTiered takes the percentage of the highest tier reached by the line quantity off the unit price.
+	DiscountTypeTiered = "tiered"
+)
+
+// Discount defines a pricing rule applied to the products matched by its target
+// @Description Discount defines a pricing rule applied to the products matched by its target
+type Discount struct {
+	ID     int            `json:"id" db:"id"`
+	Name   string         `json:"name" db:"name"`
+	Type   string         `json:"type" db:"type"`
+	Value  float64        `json:"value,omitempty" db:"value"`
+	Tiers  []DiscountTier `json:"tiers,omitempty" db:"tiers"`
+	Target DiscountTarget `json:"target" db:"target"`
+	// Rules are applied in descending Priority. A rule that is not Stackable is only applied
+	// when no other rule has been, and no further rules are applied after it.
+	Priority  int  `json:"priority" db:"priority"`
+	Stackable bool `json:"stackable" db:"stackable"`
+}
+
+// DiscountTarget selects the products a discount applies to. A product matches when it is
+// listed, is in one of the categories or has one of the tags; an empty target matches every product.
+// @Description DiscountTarget selects the products a discount applies to
+type DiscountTarget struct {
+	ProductIDs []int    `json:"product_ids,omitempty"`
+	Categories []string `json:"categories,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+}
+
+// DiscountTier defines the percentage taken off once a line reaches MinQuantity
+// @Description DiscountTier defines the percentage taken off once a line reaches min_quantity
+type DiscountTier struct {
+	MinQuantity int     `json:"min_quantity" binding:"required,gt=0"`
+	Percent     float64 `json:"percent" binding:"required,gt=0,lte=100"`
+}
+
+// DiscountPayload defines the payload for creating or updating a discount
+// @Description DiscountPayload defines the structure for creating or updating a discount
+type DiscountPayload struct {
+	Name string `json:"name" binding:"required,max=255"`
+	Type string `json:"type" binding:"required,oneof=percentage fixed tiered"`
+	// Value is the percentage for percentage rules and the amount for fixed rules.
+	Value     float64        `json:"value,omitempty" binding:"gte=0"`
+	Tiers     []DiscountTier `json:"tiers,omitempty" binding:"omitempty,dive"`
+	Target    DiscountTarget `json:"target"`
+	Priority  int            `json:"priority"`
+	Stackable bool           `json:"stackable"`
+}
+
+// QuotePayload defines the line items to price
+// @Description QuotePayload defines the structure for requesting a price quote
+type QuotePayload struct {
+	Items []QuoteItem `json:"items" binding:"required,min=1,max=100,dive"`
+}
+
+// QuoteItem defines the quantity of a single product to price
+// @Description QuoteItem defines the quantity of a single product to price
+type QuoteItem struct {
+	ProductID int `json:"product_id" binding:"required"`
+	Quantity  int `json:"quantity" binding:"required,gt=0"`
+}
+
+// Quote defines the discounted prices of a set of line items
+// @Description Quote defines the discounted prices of a set of line items
+type Quote struct {
+	Items    []QuoteLine `json:"items"`
+	Subtotal float64     `json:"subtotal"`
+	Discount float64     `json:"discount"`
+	Total    float64     `json:"total"`
+}
+
+// QuoteLine defines the price of a single line item and the discounts applied to it
+// @Description QuoteLine defines the price of a single line item and the discounts applied to it
+type QuoteLine struct {
+	ProductID int     `json:"product_id"`
+	Quantity  int     `json:"quantity"`
+	UnitPrice float64 `json:"unit_price"`
+	Subtotal  float64 `json:"subtotal"`
+	Discount  float64 `json:"discount"`
+	Total     float64 `json:"total"`
+	// AppliedDiscounts lists the discounts in the order they were applied.
+	AppliedDiscounts []AppliedDiscount `json:"applied_discounts"`
+}
+
+// AppliedDiscount defines how much a discount took off a line
+// @Description AppliedDiscount defines how much a discount took off a line
+type AppliedDiscount struct {
+	DiscountID int     `json:"discount_id"`
+	Name       string  `json:"name"`
+	Amount     float64 `json:"amount"`
+}
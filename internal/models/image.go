@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// ProductImage defines the metadata of an image uploaded for a product
+// @Description ProductImage defines the metadata of an image uploaded for a product
+type ProductImage struct {
+	ID        int `json:"id" db:"id"`
+	ProductID int `json:"product_id" db:"product_id"`
+	// StorageKey locates the image content in the storage backend.
+	StorageKey string `json:"-" db:"storage_key"`
+	// ContentHash is the hex SHA-256 of the image content; it is empty for images uploaded before it was recorded.
+	ContentHash string    `json:"-" db:"content_sha256"`
+	URL         string    `json:"url"`
+	ContentType string    `json:"content_type" db:"content_type"`
+	Size        int64     `json:"size" db:"size_bytes"`
+	Position    int       `json:"position" db:"position"`
+	IsPrimary   bool      `json:"is_primary" db:"is_primary"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// PriceSchedule defines a price that applies to a product between StartsAt and EndsAt
+// @Description PriceSchedule defines a price that applies to a product between starts_at and ends_at
+type PriceSchedule struct {
+	ID        int       `json:"id" db:"id"`
+	ProductID int       `json:"product_id" db:"product_id"`
+	Price     float64   `json:"price" db:"price"`
+	StartsAt  time.Time `json:"starts_at" db:"starts_at"`
+	// EndsAt is nil for a permanent price change.
+	EndsAt *time.Time `json:"ends_at,omitempty" db:"ends_at"`
+}
+
+// PriceSchedulePayload defines the payload for creating or updating a price schedule
+// @Description PriceSchedulePayload defines the structure for creating or updating a price schedule
+type PriceSchedulePayload struct {
+	Price    float64    `json:"price" binding:"required,gt=0"`
+	StartsAt time.Time  `json:"starts_at" binding:"required"`
+	EndsAt   *time.Time `json:"ends_at,omitempty"`
+}
@@ -1,18 +1,74 @@
 package models
 
+import "time"
+
+// Product statuses. Products move from draft to published to archived, and back from archived to draft.
+const (
+	ProductStatusDraft     = "draft"
+	ProductStatusPublished = "published"
+	ProductStatusArchived  = "archived"
+)
+
 // Product defines the structure for a product
 // @Description Product defines the structure for a product
 type Product struct {
-	ID    int     `json:"id" db:"id"`
-	Name  string  `json:"name" db:"name"`
-	Price float64 `json:"price" db:"price"`
+	ID          int     `json:"id" db:"id"`
+	Name        string  `json:"name" db:"name"`
+	Description string  `json:"description,omitempty" db:"description"`
+	Price       float64 `json:"price" db:"price"`
+	// ListPrice is the base price; Price is the active scheduled price, or ListPrice when none is active.
+	ListPrice float64 `json:"list_price,omitempty" db:"list_price"`
+	// Locale is the locale of Name and Description when translations are enabled.
+	Locale string `json:"locale,omitempty"`
+	SKU    string `json:"sku,omitempty" db:"sku"`
+	Slug   string `json:"slug,omitempty" db:"slug"`
+	// GTIN is the barcode normalized to 14 digits.
+	GTIN       string         `json:"gtin,omitempty" db:"gtin"`
+	Category   string         `json:"category,omitempty" db:"category"`
+	Attributes map[string]any `json:"attributes,omitempty" db:"attributes"`
+	Tags       []string       `json:"tags,omitempty" db:"tags"`
+	TaxClass   string         `json:"tax_class,omitempty" db:"tax_class"`
+	// Tax is only populated when requested with ?region=.
+	Tax *ProductTax `json:"tax,omitempty"`
+	// Stock is the quantity on hand; Available subtracts active reservations.
+	Stock     int `json:"stock,omitempty" db:"stock"`
+	Available int `json:"available,omitempty"`
+	// Status is draft, published or archived; PublishAt is when a draft is scheduled to be published.
+	Status    string     `json:"status,omitempty" db:"status"`
+	PublishAt *time.Time `json:"publish_at,omitempty" db:"publish_at"`
+	// RatingAverage is the average rating of the approved reviews, or nil when there are none.
+	RatingAverage *float64 `json:"rating_average,omitempty" db:"rating_average"`
+	ReviewCount   int      `json:"review_count,omitempty" db:"review_count"`
+	// Type is simple or bundle. A derived bundle's prices and a bundle's stock are computed from its components.
+	Type string `json:"type,omitempty" db:"type"`
+	// Variants is only populated when requested with ?expand=variants.
+	Variants []*ProductVariant `json:"variants,omitempty"`
+	// Relations is only populated when requested with ?expand=relations.
+	Relations []*ProductRelation `json:"relations,omitempty"`
 }
 
 // CreateProductPayload defines the payload for creating a product
 // @Description CreateProductPayload defines the structure for creating a new product
 type CreateProductPayload struct {
-	Name  string  `json:"name" binding:"required"`
-	Price float64 `json:"price" db:"price" binding:"required,gt=0"`
+	Name        string  `json:"name" binding:"required"`
+	Description string  `json:"description,omitempty" db:"description"`
+	Price       float64 `json:"price" db:"price" binding:"required,gt=0"`
+	Stock       int     `json:"stock" db:"stock" binding:"gte=0"`
+	SKU         string  `json:"sku,omitempty" db:"sku" binding:"omitempty,max=64"`
+	// Slug is generated from Name when omitted.
+	Slug string `json:"slug,omitempty" db:"slug" binding:"omitempty,max=255"`
+	// GTIN accepts GTIN-8, UPC-A, EAN-13 and GTIN-14 barcodes.
+	GTIN     string `json:"gtin,omitempty" db:"gtin" binding:"omitempty,gtin"`
+	Category string `json:"category,omitempty" db:"category" binding:"omitempty,max=64"`
+	// Attributes are checked against the attribute definitions of Category.
+	Attributes map[string]any `json:"attributes,omitempty" db:"attributes"`
+	Tags       []string       `json:"tags,omitempty" db:"tags" binding:"omitempty,max=32,dive,required,max=64"`
+	// TaxClass defaults to standard.
+	TaxClass string `json:"tax_class,omitempty" db:"tax_class" binding:"omitempty,max=32"`
+	// Status defaults to published; create a draft to prepare a product before it goes live.
+	Status string `json:"status,omitempty" db:"status" binding:"omitempty,oneof=draft published"`
+	// PublishAt schedules a draft to be published.
+	PublishAt *time.Time `json:"publish_at,omitempty" db:"publish_at"`
 }
 
 // CreateProductResponse defines the response for creating a product
@@ -26,4 +82,29 @@ type CreateProductResponse struct {
 type UpdateProductPayload struct {
 	Name  string  `json:"name" binding:"required"`
 	Price float64 `json:"price" db:"price" binding:"required,gt=0"`
+	// Description is left unchanged when omitted and cleared when empty.
+	Description *string `json:"description,omitempty" db:"description"`
+	// Stock is left unchanged when omitted.
+	Stock *int `json:"stock,omitempty" db:"stock" binding:"omitempty,gte=0"`
+	// SKU is left unchanged when omitted and cleared when empty.
+	SKU *string `json:"sku,omitempty" db:"sku" binding:"omitempty,max=64"`
+	// Slug is left unchanged when omitted.
+	Slug *string `json:"slug,omitempty" db:"slug" binding:"omitempty,max=255"`
+	// GTIN is left unchanged when omitted and cleared when empty.
+	GTIN *string `json:"gtin,omitempty" db:"gtin" binding:"omitempty,len=0|gtin"`
+	// Category is left unchanged when omitted and cleared when empty.
+	Category *string `json:"category,omitempty" db:"category" binding:"omitempty,max=64"`
+	// Attributes replace the existing attributes when present.
+	Attributes map[string]any `json:"attributes,omitempty" db:"attributes"`
+	// Tags replace the existing tags when present.
+	Tags []string `json:"tags,omitempty" db:"tags" binding:"omitempty,max=32,dive,required,max=64"`
+	// TaxClass is left unchanged when omitted.
+	TaxClass *string `json:"tax_class,omitempty" db:"tax_class" binding:"omitempty,min=1,max=32"`
+}
+
+// PublishProductPayload defines the optional payload for publishing a product
+// @Description PublishProductPayload defines the structure for publishing a product now or at a later time
+type PublishProductPayload struct {
+	// PublishAt schedules the product to be published; it is published immediately when omitted or in the past.
+	PublishAt *time.Time `json:"publish_at,omitempty"`
 }
@@ -0,0 +1,34 @@
+package models
+
+// Product relation types. Relations are directional: an accessory of a camera is not a camera of its accessory.
+const (
+	RelationTypeRelated     = "related"
+	RelationTypeAccessory   = "accessory"
+	RelationTypeReplacement = "replacement"
+	RelationTypeUpsell      = "upsell"
+)
+
+// ProductRelation defines a link from a product to a related product
+// @Description ProductRelation defines a link from a product to a related product
+type ProductRelation struct {
+	Type string `json:"type" db:"type"`
+	// ProductID is the related product; Name and Slug are its own.
+	ProductID int    `json:"product_id" db:"related_id"`
+	Name      string `json:"name" db:"name"`
+	Slug      string `json:"slug,omitempty" db:"slug"`
+	// Position orders the relations of the same type, starting from 0.
+	Position int `json:"position" db:"position"`
+}
+
+// ProductRelationPayload defines a single relation in a ProductRelationsPayload
+// @Description ProductRelationPayload defines a single relation in a ProductRelationsPayload
+type ProductRelationPayload struct {
+	Type      string `json:"type" binding:"required,oneof=related accessory replacement upsell"`
+	ProductID int    `json:"product_id" binding:"required"`
+}
+
+// ProductRelationsPayload defines the payload for replacing the relations of a product
+// @Description ProductRelationsPayload defines the structure for replacing the relations of a product, listed in display order
+type ProductRelationsPayload struct {
+	Relations []ProductRelationPayload `json:"relations" binding:"max=200,dive"`
+}
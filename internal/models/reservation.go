@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// Reservation statuses
+const (
+	ReservationStatusActive    = "active"
+	ReservationStatusCommitted = "committed"
+	ReservationStatusReleased  = "released"
+	ReservationStatusExpired   = "expired"
+)
+
+// Reservation defines a temporary hold on product stock
+// @Description Reservation defines a temporary hold on product stock
+type Reservation struct {
+	ID        int       `json:"id" db:"id"`
+	Status    string    `json:"status" db:"status"`
+	ExpiresAt time.Time `json:"expires_at" db:"expires_at"`
+	// Items lists the held products, with bundles replaced by their components.
+	Items []ReservationItem `json:"items"`
+}
+
+// ReservationItem defines the quantity of a single product held by a reservation
+// @Description ReservationItem defines the quantity of a single product held by a reservation
+type ReservationItem struct {
+	ProductID int `json:"product_id" db:"product_id" binding:"required"`
+	Quantity  int `json:"quantity" db:"quantity" binding:"required,gt=0"`
+}
+
+// CreateReservationPayload defines the payload for creating a reservation
+// @Description CreateReservationPayload defines the structure for reserving stock across products
+type CreateReservationPayload struct {
+	Items []ReservationItem `json:"items" binding:"required,min=1,dive"`
+	// TTLSeconds overrides the default reservation lifetime.
+	TTLSeconds int `json:"ttl_seconds" binding:"omitempty,gt=0,lte=86400"`
+}
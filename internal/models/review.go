@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// Review moderation statuses. Only approved reviews are shown and counted towards a product's rating.
+const (
+	ReviewStatusPending  = "pending"
+	ReviewStatusApproved = "approved"
+	ReviewStatusRejected = "rejected"
+)
+
+// Review defines a customer's rating of a product
+// @Description Review defines a customer's rating of a product
+type Review struct {
+	ID        int       `json:"id" db:"id"`
+	ProductID int       `json:"product_id" db:"product_id"`
+	Rating    int       `json:"rating" db:"rating"`
+	Text      string    `json:"text,omitempty" db:"text"`
+	Author    string    `json:"author" db:"author"`
+	Status    string    `json:"status" db:"status"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// ReviewPayload defines the payload for creating a review
+// @Description ReviewPayload defines the structure for creating a review
+type ReviewPayload struct {
+	Rating int    `json:"rating" binding:"required,min=1,max=5"`
+	Text   string `json:"text,omitempty" binding:"max=5000"`
+	Author string `json:"author" binding:"required,max=100"`
+}
+
+// ReviewStatusPayload defines the payload for moderating a review
+// @Description ReviewStatusPayload defines the structure for approving or rejecting a review
+type ReviewStatusPayload struct {
+	Status string `json:"status" binding:"required,oneof=pending approved rejected"`
+}
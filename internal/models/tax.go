@@ -0,0 +1,37 @@
+package models
+
+// TaxClassStandard is the tax class of products that do not set one.
+const TaxClassStandard = "standard"
+
+// TaxRate defines the tax charged on a tax class in a country or one of its regions
+// @Description TaxRate defines the tax charged on a tax class in a country or one of its regions
+type TaxRate struct {
+	Country string `json:"country" db:"country"`
+	// Region is the ISO 3166-2 subdivision, or empty for the whole country.
+	Region   string  `json:"region,omitempty" db:"region"`
+	TaxClass string  `json:"tax_class" db:"tax_class"`
+	Rate     float64 `json:"rate" db:"rate"`
+	// PricesIncludeTax shows prices gross in this region instead of net.
+	PricesIncludeTax bool `json:"prices_include_tax" db:"prices_include_tax"`
+}
+
+// TaxRatePayload defines the payload for creating or replacing a tax rate
+// @Description TaxRatePayload defines the structure for creating or replacing a tax rate
+type TaxRatePayload struct {
+	// Rate is a percentage, e.g. 19 for 19%.
+	Rate             float64 `json:"rate" binding:"gte=0,lte=100"`
+	PricesIncludeTax bool    `json:"prices_include_tax"`
+}
+
+// ProductTax defines the tax on a product's price in a region
+// @Description ProductTax defines the tax on a product's price in a region
+type ProductTax struct {
+	Region   string  `json:"region"`
+	TaxClass string  `json:"tax_class"`
+	Rate     float64 `json:"rate"`
+	Net      float64 `json:"net"`
+	Tax      float64 `json:"tax"`
+	Gross    float64 `json:"gross"`
+	// DisplayPrice is Gross where prices are shown including tax and Net elsewhere.
+	DisplayPrice float64 `json:"display_price"`
+}
@@ -0,0 +1,17 @@
+package models
+
+// ProductTranslation defines the name and description of a product in a locale
+// @Description ProductTranslation defines the name and description of a product in a locale
+type ProductTranslation struct {
+	ProductID   int    `json:"product_id" db:"product_id"`
+	Locale      string `json:"locale" db:"locale"`
+	Name        string `json:"name" db:"name"`
+	Description string `json:"description,omitempty" db:"description"`
+}
+
+// ProductTranslationPayload defines the payload for creating or replacing a product translation
+// @Description ProductTranslationPayload defines the structure for creating or replacing a product translation
+type ProductTranslationPayload struct {
+	Name        string `json:"name" binding:"required,max=255"`
+	Description string `json:"description,omitempty"`
+}
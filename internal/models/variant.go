@@ -0,0 +1,23 @@
+package models
+
+// ProductVariant defines a purchasable variation of a product, such as a size or colour
+// @Description ProductVariant defines a purchasable variation of a product, such as a size or colour
+type ProductVariant struct {
+	ID        int               `json:"id" db:"id"`
+	ProductID int               `json:"product_id" db:"product_id"`
+	SKU       string            `json:"sku" db:"sku"`
+	Options   map[string]string `json:"options" db:"options"`
+	// Price overrides the product price when set; EffectivePrice is the price that applies.
+	Price          *float64 `json:"price,omitempty" db:"price"`
+	EffectivePrice float64  `json:"effective_price"`
+	Stock          int      `json:"stock" db:"stock"`
+}
+
+// ProductVariantPayload defines the payload for creating or updating a product variant
+// @Description ProductVariantPayload defines the structure for creating or updating a product variant
+type ProductVariantPayload struct {
+	SKU     string            `json:"sku" binding:"required,max=64"`
+	Options map[string]string `json:"options" binding:"required,min=1,dive,keys,required,endkeys,required"`
+	Price   *float64          `json:"price,omitempty" binding:"omitempty,gt=0"`
+	Stock   int               `json:"stock" binding:"gte=0"`
+}
@@ -0,0 +1,137 @@
+// Package pricing applies discount rules to line items to produce a price quote.
+package pricing
+
+import (
+	"math"
+	"slices"
+	"sort"
+
+	"github.com/mariosker/products_rest_api/internal/models"
+)
+
+// Line is a quantity of a product to be priced.
+type Line struct {
+	Product  *models.Product
+	Quantity int
+}
+
+// Quote prices each line at its product's current price and applies the matching discounts.
+// Discounts are tried in descending priority, then ascending ID. A discount that is not stackable
+// is only applied to a line no other discount has been applied to, and ends the line's discounts;
+// stackable discounts apply one after another to the already discounted price.
+// Amounts are rounded to cents per line.
+func Quote(discounts []*models.Discount, lines []Line) *models.Quote {
+	ordered := slices.Clone(discounts)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if ordered[i].Priority != ordered[j].Priority {
+			return ordered[i].Priority > ordered[j].Priority
+		}
+		return ordered[i].ID < ordered[j].ID
+	})
+
+	quote := &models.Quote{Items: make([]models.QuoteLine, 0, len(lines))}
+	var subtotal, total int64
+	for _, line := range lines {
+		quoted, lineSubtotal, lineTotal := quoteLine(ordered, line)
+		quote.Items = append(quote.Items, quoted)
+		subtotal += lineSubtotal
+		total += lineTotal
+	}
+	quote.Subtotal = fromCents(subtotal)
+	quote.Discount = fromCents(subtotal - total)
+	quote.Total = fromCents(total)
+	return quote
+}
+
+// quoteLine prices a single line, returning it with its subtotal and total in cents.
+func quoteLine(discounts []*models.Discount, line Line) (models.QuoteLine, int64, int64) {
+	price := line.Product.Price
+	subtotal := toCents(price, line.Quantity)
+	total := subtotal
+	applied := []models.AppliedDiscount{}
+
+	for _, discount := range discounts {
+		if !Matches(discount, line.Product) {
+			continue
+		}
+		if len(applied) > 0 && !discount.Stackable {
+			continue
+		}
+		off := unitDiscount(discount, price, line.Quantity)
+		if off <= 0 {
+			continue
+		}
+
+		price -= off
+		discounted := toCents(price, line.Quantity)
+		applied = append(applied, models.AppliedDiscount{
+			DiscountID: discount.ID,
+			Name:       discount.Name,
+			Amount:     fromCents(total - discounted),
+		})
+		total = discounted
+		if !discount.Stackable {
+			break
+		}
+	}
+
+	return models.QuoteLine{
+		ProductID:        line.Product.ID,
+		Quantity:         line.Quantity,
+		UnitPrice:        line.Product.Price,
+		Subtotal:         fromCents(subtotal),
+		Discount:         fromCents(subtotal - total),
+		Total:            fromCents(total),
+		AppliedDiscounts: applied,
+	}, subtotal, total
+}
+
+// Matches reports whether discount targets product. A product matches when it is listed,
+// is in one of the categories or has one of the tags; an empty target matches every product.
+func Matches(discount *models.Discount, product *models.Product) bool {
+	target := discount.Target
+	if len(target.ProductIDs) == 0 && len(target.Categories) == 0 && len(target.Tags) == 0 {
+		return true
+	}
+	if slices.Contains(target.ProductIDs, product.ID) {
+		return true
+	}
+	if product.Category != "" && slices.Contains(target.Categories, product.Category) {
+		return true
+	}
+	for _, tag := range product.Tags {
+		if slices.Contains(target.Tags, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// unitDiscount returns how much discount takes off a unit priced at price when buying quantity units.
+// The result never exceeds price.
+func unitDiscount(discount *models.Discount, price float64, quantity int) float64 {
+	var off float64
+	switch discount.Type {
+	case models.DiscountTypePercentage:
+		off = price * discount.Value / 100
+	case models.DiscountTypeFixed:
+		off = discount.Value
+	case models.DiscountTypeTiered:
+		reached := 0
+		for _, tier := range discount.Tiers {
+			if tier.MinQuantity <= quantity && tier.MinQuantity > reached {
+				reached = tier.MinQuantity
+				off = price * tier.Percent / 100
+			}
+		}
+	}
+	return math.Min(off, price)
+}
+
+func toCents(price float64, quantity int) int64 {
+	return int64(math.Round(price * float64(quantity) * 100))
+}
+
+func fromCents(cents int64) float64 {
+	return float64(cents) / 100
+}
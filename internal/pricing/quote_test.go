@@ -0,0 +1,175 @@
+package pricing
+
+import (
+	"testing"
+
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuote(t *testing.T) {
+	laptop := &models.Product{ID: 1, Price: 1000, Category: "laptops", Tags: []string{"clearance"}}
+	mouse := &models.Product{ID: 2, Price: 19.99, Category: "accessories"}
+	cable := &models.Product{ID: 3, Price: 5, Category: "accessories", Tags: []string{"bulk"}}
+
+	laptops20 := &models.Discount{ID: 1, Name: "20% off laptops", Type: models.DiscountTypePercentage, Value: 20,
+		Target: models.DiscountTarget{Categories: []string{"laptops"}}}
+	clearance50 := &models.Discount{ID: 2, Name: "Clearance", Type: models.DiscountTypeFixed, Value: 50,
+		Target: models.DiscountTarget{Tags: []string{"clearance"}}}
+	buyThree := &models.Discount{ID: 3, Name: "Buy 3 get 10% off", Type: models.DiscountTypeTiered,
+		Tiers: []models.DiscountTier{{MinQuantity: 3, Percent: 10}, {MinQuantity: 10, Percent: 25}}}
+	members5 := &models.Discount{ID: 4, Name: "Members 5%", Type: models.DiscountTypePercentage, Value: 5, Stackable: true}
+
+	tests := []struct {
+		name      string
+		discounts []*models.Discount
+		lines     []Line
+		totals    []float64
+		applied   [][]int
+		total     float64
+	}{
+		{
+			name:    "No Discounts",
+			lines:   []Line{{Product: mouse, Quantity: 3}},
+			totals:  []float64{59.97},
+			applied: [][]int{{}},
+			total:   59.97,
+		},
+		{
+			name:      "Percentage By Category",
+			discounts: []*models.Discount{laptops20},
+			lines:     []Line{{Product: laptop, Quantity: 1}, {Product: mouse, Quantity: 1}},
+			totals:    []float64{800, 19.99},
+			applied:   [][]int{{1}, {}},
+			total:     819.99,
+		},
+		{
+			name:      "Fixed By Tag",
+			discounts: []*models.Discount{clearance50},
+			lines:     []Line{{Product: laptop, Quantity: 2}},
+			totals:    []float64{1900},
+			applied:   [][]int{{2}},
+			total:     1900,
+		},
+		{
+			name: "Fixed Never Goes Below Zero",
+			discounts: []*models.Discount{{ID: 5, Name: "Free cables", Type: models.DiscountTypeFixed, Value: 10,
+				Target: models.DiscountTarget{ProductIDs: []int{3}}}},
+			lines:   []Line{{Product: cable, Quantity: 2}},
+			totals:  []float64{0},
+			applied: [][]int{{5}},
+			total:   0,
+		},
+		{
+			name:      "Tier Not Reached",
+			discounts: []*models.Discount{buyThree},
+			lines:     []Line{{Product: cable, Quantity: 2}},
+			totals:    []float64{10},
+			applied:   [][]int{{}},
+			total:     10,
+		},
+		{
+			name:      "Highest Tier Reached",
+			discounts: []*models.Discount{buyThree},
+			lines:     []Line{{Product: cable, Quantity: 3}, {Product: mouse, Quantity: 12}},
+			totals:    []float64{13.5, 179.91},
+			applied:   [][]int{{3}, {3}},
+			total:     193.41,
+		},
+		{
+			name:      "Highest Priority Exclusive Rule Wins",
+			discounts: []*models.Discount{laptops20, {ID: 6, Name: "Clearance", Type: models.DiscountTypeFixed, Value: 50, Priority: 10, Target: models.DiscountTarget{Tags: []string{"clearance"}}}},
+			lines:     []Line{{Product: laptop, Quantity: 1}},
+			totals:    []float64{950},
+			applied:   [][]int{{6}},
+			total:     950,
+		},
+		{
+			name:      "Equal Priority Falls Back To ID",
+			discounts: []*models.Discount{clearance50, laptops20},
+			lines:     []Line{{Product: laptop, Quantity: 1}},
+			totals:    []float64{800},
+			applied:   [][]int{{1}},
+			total:     800,
+		},
+		{
+			name:      "Stackable Rule Applies After Exclusive One Is Skipped",
+			discounts: []*models.Discount{{ID: 7, Name: "Members 5%", Type: models.DiscountTypePercentage, Value: 5, Stackable: true, Priority: 1}, laptops20},
+			lines:     []Line{{Product: laptop, Quantity: 1}},
+			totals:    []float64{950},
+			applied:   [][]int{{7}},
+			total:     950,
+		},
+		{
+			name: "Stackable Rules Compound",
+			discounts: []*models.Discount{
+				{ID: 8, Name: "Laptops 20%", Type: models.DiscountTypePercentage, Value: 20, Stackable: true, Priority: 1, Target: models.DiscountTarget{Categories: []string{"laptops"}}},
+				members5,
+			},
+			lines:   []Line{{Product: laptop, Quantity: 1}},
+			totals:  []float64{760},
+			applied: [][]int{{8, 4}},
+			total:   760,
+		},
+		{
+			name:      "Exclusive Rule Ends Stacking",
+			discounts: []*models.Discount{laptops20, {ID: 9, Name: "Members 5%", Type: models.DiscountTypePercentage, Value: 5, Stackable: true, Priority: -1}},
+			lines:     []Line{{Product: laptop, Quantity: 1}},
+			totals:    []float64{800},
+			applied:   [][]int{{1}},
+			total:     800,
+		},
+		{
+			name:      "Rounds To Cents",
+			discounts: []*models.Discount{{ID: 10, Name: "A third off", Type: models.DiscountTypePercentage, Value: 33.33}},
+			lines:     []Line{{Product: mouse, Quantity: 1}},
+			totals:    []float64{13.33},
+			applied:   [][]int{{10}},
+			total:     13.33,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quote := Quote(tt.discounts, tt.lines)
+			assert.Len(t, quote.Items, len(tt.lines))
+			for i, line := range quote.Items {
+				assert.Equal(t, tt.totals[i], line.Total, "line %d total", i)
+				ids := []int{}
+				var amount float64
+				for _, applied := range line.AppliedDiscounts {
+					ids = append(ids, applied.DiscountID)
+					amount += applied.Amount
+				}
+				assert.Equal(t, tt.applied[i], ids, "line %d discounts", i)
+				assert.InDelta(t, line.Discount, amount, 0.001, "line %d discount amounts", i)
+				assert.InDelta(t, line.Subtotal-line.Discount, line.Total, 0.001, "line %d total", i)
+			}
+			assert.Equal(t, tt.total, quote.Total)
+			assert.InDelta(t, quote.Subtotal-quote.Discount, quote.Total, 0.001)
+		})
+	}
+}
+
+func TestMatches(t *testing.T) {
+	product := &models.Product{ID: 7, Category: "shoes", Tags: []string{"summer", "sale"}}
+
+	tests := []struct {
+		name   string
+		target models.DiscountTarget
+		want   bool
+	}{
+		{name: "Empty Target Matches Everything", want: true},
+		{name: "Product ID", target: models.DiscountTarget{ProductIDs: []int{3, 7}}, want: true},
+		{name: "Category", target: models.DiscountTarget{Categories: []string{"shoes"}}, want: true},
+		{name: "Any Tag", target: models.DiscountTarget{Tags: []string{"winter", "sale"}}, want: true},
+		{name: "Any Selector", target: models.DiscountTarget{ProductIDs: []int{1}, Categories: []string{"shoes"}}, want: true},
+		{name: "No Selector Matches", target: models.DiscountTarget{ProductIDs: []int{1}, Categories: []string{"hats"}, Tags: []string{"winter"}}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Matches(&models.Discount{Target: tt.target}, product))
+		})
+	}
+}
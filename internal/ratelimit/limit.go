@@ -0,0 +1,85 @@
+// Package ratelimit limits how many requests each client can make with token buckets.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit allows Requests requests per Period. A client that has been idle can make all of them at
+// once, after which its bucket refills evenly over the period.
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// ParseLimit parses a limit such as "60/m", "10/s", "1000/h" or "100/30s".
+func ParseLimit(s string) (Limit, error) {
+	requests, period, ok := strings.Cut(s, "/")
+	n, err := strconv.Atoi(requests)
+	if !ok || err != nil || n <= 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit %q: want requests/period, e.g. 60/m", s)
+	}
+
+	var d time.Duration
+	switch period {
+	case "s":
+		d = time.Second
+	case "m":
+		d = time.Minute
+	case "h":
+		d = time.Hour
+	default:
+		if d, err = time.ParseDuration(period); err != nil || d <= 0 {
+			return Limit{}, fmt.Errorf("invalid rate limit period %q in %q", period, s)
+		}
+	}
+	return Limit{Requests: n, Period: d}, nil
+}
+
+// refill returns the time it takes to refill the given number of tokens.
+func (l Limit) refill(tokens float64) time.Duration {
+	return time.Duration(math.Ceil(tokens * float64(l.Period) / float64(l.Requests)))
+}
+
+// Result is the outcome of taking a token from a bucket.
+type Result struct {
+	Allowed bool
+	// Remaining is how many whole tokens are left in the bucket.
+	Remaining int
+	// RetryAfter is how long until the next token, when the request was not allowed.
+	RetryAfter time.Duration
+	// Reset is how long until the bucket is full again.
+	Reset time.Duration
+}
+
+// Store holds the token buckets of every client.
+type Store interface {
+	// Take takes a token from the bucket of key, creating a full bucket for new keys.
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+	// Peek returns what Take would, without taking a token.
+	Peek(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// take refills a bucket holding tokens by the time elapsed since it was last used, then takes a
+// token from it if there is one. It returns the tokens left and the outcome.
+func take(tokens float64, elapsed time.Duration, limit Limit) (float64, Result) {
+	burst := float64(limit.Requests)
+	if elapsed > 0 {
+		tokens = math.Min(burst, tokens+float64(elapsed)*burst/float64(limit.Period))
+	}
+
+	result := Result{Allowed: tokens >= 1}
+	if result.Allowed {
+		tokens--
+	} else {
+		result.RetryAfter = limit.refill(1 - tokens)
+	}
+	result.Remaining = int(tokens)
+	result.Reset = limit.refill(burst - tokens)
+	return tokens, result
+}
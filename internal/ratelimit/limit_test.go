@@ -0,0 +1,78 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		value string
+		limit Limit
+		valid bool
+	}{
+		{value: "60/m", limit: Limit{Requests: 60, Period: time.Minute}, valid: true},
+		{value: "10/s", limit: Limit{Requests: 10, Period: time.Second}, valid: true},
+		{value: "1000/h", limit: Limit{Requests: 1000, Period: time.Hour}, valid: true},
+		{value: "100/30s", limit: Limit{Requests: 100, Period: 30 * time.Second}, valid: true},
+		{value: "60"},
+		{value: "0/m"},
+		{value: "ten/m"},
+		{value: "60/fortnight"},
+		{value: "60/-1m"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			limit, err := ParseLimit(tt.value)
+			if !tt.valid {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.limit, limit)
+		})
+	}
+}
+
+func TestMemoryStore_Take(t *testing.T) {
+	now := time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+	limit := Limit{Requests: 3, Period: 3 * time.Second}
+	ctx := context.Background()
+
+	for remaining := 2; remaining >= 0; remaining-- {
+		result, err := store.Take(ctx, "client:a", limit)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, remaining, result.Remaining)
+	}
+
+	result, err := store.Take(ctx, "client:a", limit)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, time.Second, result.RetryAfter)
+	assert.Equal(t, 3*time.Second, result.Reset)
+
+	other, err := store.Take(ctx, "client:b", limit)
+	require.NoError(t, err)
+	assert.True(t, other.Allowed, "buckets are per key")
+
+	now = now.Add(1500 * time.Millisecond)
+	result, err = store.Take(ctx, "client:a", limit)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed, "a token refills every second")
+	assert.Equal(t, 0, result.Remaining)
+
+	now = now.Add(time.Hour)
+	result, err = store.Take(ctx, "client:a", limit)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Remaining, "buckets refill up to the limit")
+
+	store.sweep(now.Add(time.Hour))
+	assert.Empty(t, store.buckets)
+}
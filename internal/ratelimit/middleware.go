@@ -0,0 +1,111 @@
+package ratelimit
+
+import (
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mariosker/products_rest_api/internal/auth"
+	"github.com/mariosker/products_rest_api/internal/utils"
+)
+
+// Route groups, each limited separately.
+const (
+	GroupRead  = "read"
+	GroupWrite = "write"
+	GroupAdmin = "admin"
+)
+
+// Group returns the route group of a request: admin below /admin and for /metrics, read for
+// requests that only read, and write for the rest.
+func Group(r *http.Request) string {
+	switch {
+	case r.URL.Path == "/admin" || strings.HasPrefix(r.URL.Path, "/admin/") || r.URL.Path == "/metrics":
+		return GroupAdmin
+	case r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions:
+		return GroupRead
+	default:
+		return GroupWrite
+	}
+}
+
+// Middleware limits the requests of each client per route group. Authenticated clients are told
+// apart by their credentials and others by IP address, so it must run after authentication.
+// Route groups without a limit are not limited. When the store fails, requests are let through.
+func Middleware(store Store, limits map[string]Limit) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		group := Group(c.Request)
+		limit, ok := limits[group]
+		if !ok {
+			c.Next()
+			return
+		}
+
+		result, err := store.Take(c.Request.Context(), group+":"+clientKey(c), limit)
+		if err != nil {
+			log.Printf("Failed to check rate limit: %v", err)
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Limit", strconv.Itoa(limit.Requests))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(seconds(result.Reset)))
+		c.Header("RateLimit-Policy", strconv.Itoa(limit.Requests)+";w="+strconv.Itoa(seconds(limit.Period)))
+		if !result.Allowed {
+			retryAfter := strconv.Itoa(seconds(result.RetryAfter))
+			c.Header("Retry-After", retryAfter)
+			utils.SendErrorResponse(c, http.StatusTooManyRequests, "Rate limit exceeded, retry in "+retryAfter+" seconds")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// AuthFailures limits how many failed authentications each IP address can make, so that credentials
+// cannot be guessed by brute force. It must run before authentication: once an address has used up
+// its limit, its requests are rejected without checking their credentials until its bucket refills.
+// When the store fails, requests are let through.
+func AuthFailures(store Store, limit Limit) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := "auth:ip:" + c.ClientIP()
+		result, err := store.Peek(c.Request.Context(), key, limit)
+		if err != nil {
+			log.Printf("Failed to check authentication rate limit: %v", err)
+			c.Next()
+			return
+		}
+		if !result.Allowed {
+			retryAfter := strconv.Itoa(seconds(result.RetryAfter))
+			c.Header("Retry-After", retryAfter)
+			utils.SendErrorResponse(c, http.StatusTooManyRequests, "Too many failed authentications, retry in "+retryAfter+" seconds")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+		if c.Writer.Status() == http.StatusUnauthorized {
+			if _, err := store.Take(c.Request.Context(), key, limit); err != nil {
+				log.Printf("Failed to count failed authentication: %v", err)
+			}
+		}
+	}
+}
+
+// clientKey identifies the client of a request by its credentials, or by IP address when it has none.
+func clientKey(c *gin.Context) string {
+	if principal := auth.PrincipalFrom(c); principal != nil && principal.Subject != "" {
+		return "client:" + principal.Subject
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// seconds rounds d up to whole seconds, as the rate limit headers expect.
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,142 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mariosker/products_rest_api/internal/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+// failingStore is a store whose database is unreachable.
+type failingStore struct{}
+
+func (failingStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	return Result{}, errors.New("connection refused")
+}
+
+func (failingStore) Peek(ctx context.Context, key string, limit Limit) (Result, error) {
+	return Result{}, errors.New("connection refused")
+}
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func(store Store) *gin.Engine {
+		router := gin.New()
+		router.Use(func(c *gin.Context) {
+			if key := c.GetHeader("X-API-Key"); key != "" {
+				auth.SetPrincipal(c, &auth.Principal{Subject: key})
+			}
+		})
+		router.Use(Middleware(store, map[string]Limit{
+			GroupRead:  {Requests: 2, Period: time.Minute},
+			GroupWrite: {Requests: 1, Period: time.Minute},
+		}))
+		ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+		router.GET("/products", ok)
+		router.POST("/products", ok)
+		router.GET("/admin/api-keys", ok)
+		return router
+	}
+	send := func(router *gin.Engine, method, path, key string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, nil)
+		req.RemoteAddr = "192.0.2.1:1234"
+		if key != "" {
+			req.Header.Set("X-API-Key", key)
+		}
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Headers And 429", func(t *testing.T) {
+		router := newRouter(NewMemoryStore())
+
+		w := send(router, "GET", "/products", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "2", w.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, "1", w.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "30", w.Header().Get("RateLimit-Reset"))
+		assert.Equal(t, "2;w=60", w.Header().Get("RateLimit-Policy"))
+
+		assert.Equal(t, http.StatusOK, send(router, "GET", "/products", "").Code)
+		w = send(router, "GET", "/products", "")
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "30", w.Header().Get("Retry-After"))
+		assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+	})
+
+	t.Run("Groups Are Limited Separately", func(t *testing.T) {
+		router := newRouter(NewMemoryStore())
+
+		assert.Equal(t, http.StatusOK, send(router, "POST", "/products", "").Code)
+		assert.Equal(t, http.StatusTooManyRequests, send(router, "POST", "/products", "").Code)
+		assert.Equal(t, http.StatusOK, send(router, "GET", "/products", "").Code)
+
+		w := send(router, "GET", "/admin/api-keys", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("RateLimit-Limit"), "groups without a limit are not limited")
+	})
+
+	t.Run("Clients Are Limited Separately", func(t *testing.T) {
+		router := newRouter(NewMemoryStore())
+
+		assert.Equal(t, http.StatusOK, send(router, "POST", "/products", "api-key:1").Code)
+		assert.Equal(t, http.StatusTooManyRequests, send(router, "POST", "/products", "api-key:1").Code)
+		assert.Equal(t, http.StatusOK, send(router, "POST", "/products", "api-key:2").Code)
+		assert.Equal(t, http.StatusOK, send(router, "POST", "/products", "").Code)
+	})
+
+	t.Run("Store Failure Lets Requests Through", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, send(newRouter(failingStore{}), "GET", "/products", "").Code)
+	})
+}
+
+func TestAuthFailures(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func(store Store) *gin.Engine {
+		router := gin.New()
+		router.Use(AuthFailures(store, Limit{Requests: 2, Period: time.Minute}))
+		router.Use(func(c *gin.Context) {
+			if c.GetHeader("X-API-Key") != "valid" {
+				c.AbortWithStatus(http.StatusUnauthorized)
+			}
+		})
+		router.GET("/products", func(c *gin.Context) { c.Status(http.StatusOK) })
+		return router
+	}
+	send := func(router *gin.Engine, ip, key string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/products", nil)
+		req.RemoteAddr = ip + ":1234"
+		req.Header.Set("X-API-Key", key)
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Blocks After Failures", func(t *testing.T) {
+		router := newRouter(NewMemoryStore())
+
+		for i := 0; i < 3; i++ {
+			assert.Equal(t, http.StatusOK, send(router, "192.0.2.1", "valid").Code, "successes are not counted")
+		}
+		assert.Equal(t, http.StatusUnauthorized, send(router, "192.0.2.1", "guess-1").Code)
+		assert.Equal(t, http.StatusUnauthorized, send(router, "192.0.2.1", "guess-2").Code)
+
+		w := send(router, "192.0.2.1", "valid")
+		assert.Equal(t, http.StatusTooManyRequests, w.Code, "credentials are not checked once blocked")
+		assert.Equal(t, "30", w.Header().Get("Retry-After"))
+		assert.Equal(t, http.StatusOK, send(router, "192.0.2.2", "valid").Code, "addresses are limited separately")
+	})
+
+	t.Run("Store Failure Lets Requests Through", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, send(newRouter(failingStore{}), "192.0.2.1", "valid").Code)
+	})
+}
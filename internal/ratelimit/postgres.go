@@ -0,0 +1,86 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/mariosker/products_rest_api/internal/database"
+)
+
+// PostgresStore keeps token buckets in the rate_limit_buckets table, so that limits hold across
+// every instance of the API.
+type PostgresStore struct {
+	dbConnection database.DBConnection
+	takes        atomic.Int64
+}
+
+// NewPostgresStore creates a store that keeps token buckets in the database.
+func NewPostgresStore(dbConnection database.DBConnection) *PostgresStore {
+	return &PostgresStore{dbConnection: dbConnection}
+}
+
+// Take takes a token from the bucket of key. The bucket row is locked while it is updated, and
+// elapsed time is measured by the database clock so that instances need not agree on the time.
+func (s *PostgresStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	ctx = database.WithOperation(ctx, "PostgresStore.Take")
+	tx, err := s.dbConnection.Begin(ctx)
+	if err != nil {
+		return Result{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO rate_limit_buckets (key, tokens, updated_at, full_at) VALUES ($1, $2, now(), now())
+		ON CONFLICT (key) DO NOTHING`, key, limit.Requests)
+	if err != nil {
+		return Result{}, err
+	}
+
+	var tokens, elapsed float64
+	err = tx.QueryRow(ctx, `
+		SELECT tokens, EXTRACT(EPOCH FROM now() - updated_at)::float8
+		FROM rate_limit_buckets WHERE key = $1 FOR UPDATE`, key).Scan(&tokens, &elapsed)
+	if err != nil {
+		return Result{}, err
+	}
+
+	tokens, result := take(tokens, time.Duration(elapsed*float64(time.Second)), limit)
+	_, err = tx.Exec(ctx, `
+		UPDATE rate_limit_buckets SET tokens = $2, updated_at = now(), full_at = now() + make_interval(secs => $3::float8)
+		WHERE key = $1`, key, tokens, result.Reset.Seconds())
+	if err != nil {
+		return Result{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return Result{}, err
+	}
+
+	if s.takes.Add(1)%sweepEvery == 0 {
+		// Buckets that have refilled behave like missing ones.
+		if _, err := s.dbConnection.Exec(ctx, "DELETE FROM rate_limit_buckets WHERE full_at < now()"); err != nil {
+			log.Printf("Failed to delete refilled rate limit buckets: %v", err)
+		}
+	}
+	return result, nil
+}
+
+// Peek returns what Take would for the bucket of key, without taking a token or locking the bucket.
+func (s *PostgresStore) Peek(ctx context.Context, key string, limit Limit) (Result, error) {
+	ctx = database.WithOperation(ctx, "PostgresStore.Peek")
+	var tokens, elapsed float64
+	err := s.dbConnection.QueryRow(ctx, `
+		SELECT tokens, EXTRACT(EPOCH FROM now() - updated_at)::float8
+		FROM rate_limit_buckets WHERE key = $1`, key).Scan(&tokens, &elapsed)
+	if errors.Is(err, pgx.ErrNoRows) {
+		tokens, elapsed = float64(limit.Requests), 0
+	} else if err != nil {
+		return Result{}, err
+	}
+
+	_, result := take(tokens, time.Duration(elapsed*float64(time.Second)), limit)
+	return result, nil
+}
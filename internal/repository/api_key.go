@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/mariosker/products_rest_api/internal/database"
+	"github.com/mariosker/products_rest_api/internal/models"
+)
+
+type APIKeyRepository interface {
+	CreateAPIKey(ctx context.Context, key *models.APIKey, hash []byte) error
+	GetAPIKeys(ctx context.Context, tenantID string) ([]*models.APIKey, error)
+	GetAPIKeyByHash(ctx context.Context, hash []byte) (*models.APIKey, error)
+	HasActiveAPIKeys(ctx context.Context) (bool, error)
+	RotateAPIKey(ctx context.Context, id int, tenantID, prefix string, hash []byte, grace time.Duration) (*models.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id int, tenantID string) error
+}
+
+type PostgresAPIKeyRepository struct {
+	dbConnection database.DBConnection
+}
+
+func NewPostgresAPIKeyRepository(dbConnection database.DBConnection) *PostgresAPIKeyRepository {
+	return &PostgresAPIKeyRepository{dbConnection: dbConnection}
+}
+
+// apiKeyColumns selects the columns scanned by scanAPIKey.
+const apiKeyColumns = "id, name, prefix, scopes, roles, COALESCE(tenant_id, ''), expires_at, revoked_at, created_at, rotated_at"
+
+// CreateAPIKey stores a new API key by its hash and sets its ID and creation time.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+// - key: the name, prefix, scopes, roles, tenant and expiry of the key.
+// - hash: the hash of the key itself.
+func (r *PostgresAPIKeyRepository) CreateAPIKey(ctx context.Context, key *models.APIKey, hash []byte) error {
+	ctx = database.WithOperation(ctx, "PostgresAPIKeyRepository.CreateAPIKey")
+	return r.dbConnection.QueryRow(ctx, `
+		INSERT INTO api_keys (name, prefix, key_hash, scopes, roles, tenant_id, expires_at)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7)
+		RETURNING id, created_at`,
+		key.Name, key.Prefix, hash, key.Scopes, key.Roles, key.TenantID, key.ExpiresAt).Scan(&key.ID, &key.CreatedAt)
+}
+
+// GetAPIKeys retrieves every API key of a tenant, including revoked ones, ordered by ID.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+// - tenantID: the tenant whose keys are retrieved, or "" for the keys of every tenant and unbound keys.
+func (r *PostgresAPIKeyRepository) GetAPIKeys(ctx context.Context, tenantID string) ([]*models.APIKey, error) {
+	ctx = database.WithOperation(ctx, "PostgresAPIKeyRepository.GetAPIKeys")
+	rows, err := r.dbConnection.Query(ctx, "SELECT "+apiKeyColumns+" FROM api_keys WHERE $1 = '' OR tenant_id = $1 ORDER BY id", tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []*models.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+// GetAPIKeyByHash retrieves the API key with the given hash if it is neither revoked nor expired.
+// The previous key of a rotated API key matches until its grace period ends.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+// - hash: the hash of the key presented by the client.
+func (r *PostgresAPIKeyRepository) GetAPIKeyByHash(ctx context.Context, hash []byte) (*models.APIKey, error) {
+	ctx = database.WithOperation(ctx, "PostgresAPIKeyRepository.GetAPIKeyByHash")
+	query := `
+		SELECT ` + apiKeyColumns + ` FROM api_keys
+		WHERE (key_hash = $1 OR (previous_key_hash = $1 AND previous_valid_until > now()))
+		AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > now())`
+	key, err := scanAPIKey(r.dbConnection.QueryRow(ctx, query, hash))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("API key: %w", ErrNotFound)
+	}
+	return key, err
+}
+
+// HasActiveAPIKeys reports whether any API key is neither revoked nor expired.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+func (r *PostgresAPIKeyRepository) HasActiveAPIKeys(ctx context.Context) (bool, error) {
+	ctx = database.WithOperation(ctx, "PostgresAPIKeyRepository.HasActiveAPIKeys")
+	var exists bool
+	err := r.dbConnection.QueryRow(ctx,
+		"SELECT EXISTS (SELECT 1 FROM api_keys WHERE revoked_at IS NULL AND (expires_at IS NULL OR expires_at > now()))").Scan(&exists)
+	return exists, err
+}
+
+// RotateAPIKey replaces the key of an active API key, keeping its name, scopes and expiry.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+// - id: the ID of the API key to rotate.
+// - tenantID: the tenant the key must be bound to, or "" for a key of any tenant.
+// - prefix: the prefix of the new key.
+// - hash: the hash of the new key.
+// - grace: how long the old key keeps working, or 0 to stop it working now.
+func (r *PostgresAPIKeyRepository) RotateAPIKey(ctx context.Context, id int, tenantID, prefix string, hash []byte, grace time.Duration) (*models.APIKey, error) {
+	ctx = database.WithOperation(ctx, "PostgresAPIKeyRepository.RotateAPIKey")
+	query := `
+		UPDATE api_keys SET
+			previous_key_hash = CASE WHEN $4::int > 0 THEN key_hash END,
+			previous_valid_until = CASE WHEN $4::int > 0 THEN now() + make_interval(secs => $4::int) END,
+			prefix = $2, key_hash = $3, rotated_at = now()
+		WHERE id = $1 AND revoked_at IS NULL AND ($5 = '' OR tenant_id = $5)
+		RETURNING ` + apiKeyColumns
+	key, err := scanAPIKey(r.dbConnection.QueryRow(ctx, query, id, prefix, hash, int(grace.Seconds()), tenantID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("API key with ID %d: %w", id, ErrNotFound)
+	}
+	return key, err
+}
+
+// RevokeAPIKey stops an API key, including the previous key of a rotated key, from working.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+// - id: the ID of the API key to revoke.
+// - tenantID: the tenant the key must be bound to, or "" for a key of any tenant.
+func (r *PostgresAPIKeyRepository) RevokeAPIKey(ctx context.Context, id int, tenantID string) error {
+	ctx = database.WithOperation(ctx, "PostgresAPIKeyRepository.RevokeAPIKey")
+	result, err := r.dbConnection.Exec(ctx,
+		"UPDATE api_keys SET revoked_at = now() WHERE id = $1 AND revoked_at IS NULL AND ($2 = '' OR tenant_id = $2)", id, tenantID)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("API key with ID %d: %w", id, ErrNotFound)
+	}
+	return nil
+}
+
+func scanAPIKey(row pgx.Row) (*models.APIKey, error) {
+	var key models.APIKey
+	err := row.Scan(&key.ID, &key.Name, &key.Prefix, &key.Scopes, &key.Roles, &key.TenantID, &key.ExpiresAt, &key.RevokedAt, &key.CreatedAt, &key.RotatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/mariosker/products_rest_api/internal/database"
+	"github.com/mariosker/products_rest_api/internal/models"
+)
+
+type AttributeRepository interface {
+	GetAttributeDefinitions(ctx context.Context, category string) ([]*models.AttributeDefinition, error)
+	PutAttributeDefinition(ctx context.Context, definition *models.AttributeDefinition) error
+	DeleteAttributeDefinition(ctx context.Context, category, name string) error
+}
+
+type PostgresAttributeRepository struct {
+	dbConnection database.DBConnection
+}
+
+func NewPostgresAttributeRepository(dbConnection database.DBConnection) *PostgresAttributeRepository {
+	return &PostgresAttributeRepository{dbConnection: dbConnection}
+}
+
+// GetAttributeDefinitions retrieves the attribute definitions of a category of the tenant in ctx ordered by name.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+// - category: the category whose definitions are retrieved.
+func (r *PostgresAttributeRepository) GetAttributeDefinitions(ctx context.Context, category string) ([]*models.AttributeDefinition, error) {
+	ctx = database.WithOperation(ctx, "PostgresAttributeRepository.GetAttributeDefinitions")
+	var definitions []*models.AttributeDefinition
+	err := inTenant(ctx, r.dbConnection, func(tx pgx.Tx) error {
+		var err error
+		definitions, err = getAttributeDefinitions(ctx, tx, category)
+		return err
+	})
+	return definitions, err
+}
+
+// PutAttributeDefinition creates or replaces an attribute definition.
+// Existing products are not revalidated; the definition applies to later creates and updates.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+// - definition: the attribute definition to be stored.
+func (r *PostgresAttributeRepository) PutAttributeDefinition(ctx context.Context, definition *models.AttributeDefinition) error {
+	ctx = database.WithOperation(ctx, "PostgresAttributeRepository.PutAttributeDefinition")
+	return inTenant(ctx, r.dbConnection, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO attribute_definitions (category, name, type, required, allowed_values, min_value, max_value)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (tenant_id, category, name) DO UPDATE SET
+				type = EXCLUDED.type, required = EXCLUDED.required, allowed_values = EXCLUDED.allowed_values,
+				min_value = EXCLUDED.min_value, max_value = EXCLUDED.max_value`,
+			definition.Category, definition.Name, definition.Type, definition.Required,
+			definition.AllowedValues, definition.Min, definition.Max)
+		return err
+	})
+}
+
+// DeleteAttributeDefinition deletes an attribute definition.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+// - category: the category the definition belongs to.
+// - name: the name of the attribute.
+func (r *PostgresAttributeRepository) DeleteAttributeDefinition(ctx context.Context, category, name string) error {
+	ctx = database.WithOperation(ctx, "PostgresAttributeRepository.DeleteAttributeDefinition")
+	return inTenant(ctx, r.dbConnection, func(tx pgx.Tx) error {
+		result, err := tx.Exec(ctx, "DELETE FROM attribute_definitions WHERE category = $1 AND name = $2", category, name)
+		if err != nil {
+			return err
+		}
+		if result.RowsAffected() == 0 {
+			return fmt.Errorf("attribute %q of category %q: %w", name, category, ErrNotFound)
+		}
+		return nil
+	})
+}
+
+func getAttributeDefinitions(ctx context.Context, q database.DBConnection, category string) ([]*models.AttributeDefinition, error) {
+	rows, err := q.Query(ctx, `
+		SELECT category, name, type, required, allowed_values, min_value, max_value
+		FROM attribute_definitions WHERE category = $1 ORDER BY name`, category)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	definitions := []*models.AttributeDefinition{}
+	for rows.Next() {
+		var d models.AttributeDefinition
+		if err := rows.Scan(&d.Category, &d.Name, &d.Type, &d.Required, &d.AllowedValues, &d.Min, &d.Max); err != nil {
+			return nil, err
+		}
+		definitions = append(definitions, &d)
+	}
+	return definitions, rows.Err()
+}
@@ -0,0 +1,288 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/mariosker/products_rest_api/internal/database"
+	"github.com/mariosker/products_rest_api/internal/models"
+)
+
+type BundleRepository interface {
+	PutBundle(ctx context.Context, productID int, payload *models.BundlePayload) (*models.Bundle, error)
+	GetBundle(ctx context.Context, productID int) (*models.Bundle, error)
+	DeleteBundle(ctx context.Context, productID int) error
+	DeleteProductCascade(ctx context.Context, productID int) error
+}
+
+type PostgresBundleRepository struct {
+	dbConnection database.DBConnection
+}
+
+func NewPostgresBundleRepository(dbConnection database.DBConnection) *PostgresBundleRepository {
+	return &PostgresBundleRepository{dbConnection: dbConnection}
+}
+
+// PutBundle turns a product into a bundle, or replaces the components and pricing of a bundle.
+// Components must be distinct simple products other than the bundle itself, and a product
+// that is a component of another bundle cannot become a bundle.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+// - productID: the ID of the bundle product.
+// - payload: the components and pricing of the bundle.
+func (r *PostgresBundleRepository) PutBundle(ctx context.Context, productID int, payload *models.BundlePayload) (*models.Bundle, error) {
+	ctx = database.WithOperation(ctx, "PostgresBundleRepository.PutBundle")
+	componentIDs := make([]int, 0, len(payload.Components))
+	quantities := make([]int, 0, len(payload.Components))
+	seen := make(map[int]bool, len(payload.Components))
+	for _, component := range payload.Components {
+		if component.ProductID == productID {
+			return nil, fmt.Errorf("bundle cannot contain itself: %w", ErrInvalidBundle)
+		}
+		if seen[component.ProductID] {
+			return nil, fmt.Errorf("component %d is listed more than once: %w", component.ProductID, ErrInvalidBundle)
+		}
+		seen[component.ProductID] = true
+		componentIDs = append(componentIDs, component.ProductID)
+		quantities = append(quantities, component.Quantity)
+	}
+
+	tx, err := beginTenant(ctx, r.dbConnection)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Lock the bundle before its components, so that a concurrent PutBundle using this
+	// product as a component waits for it to become a bundle and is then rejected.
+	var exists bool
+	err = tx.QueryRow(ctx, "SELECT true FROM products WHERE id = $1 FOR UPDATE", productID).Scan(&exists)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("product with ID %d: %w", productID, ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var usedInBundle bool
+	err = tx.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM bundle_components WHERE component_id = $1)", productID).Scan(&usedInBundle)
+	if err != nil {
+		return nil, err
+	}
+	if usedInBundle {
+		return nil, fmt.Errorf("product with ID %d is a component of another bundle: %w", productID, ErrInvalidBundle)
+	}
+
+	rows, err := tx.Query(ctx, "SELECT id, type FROM products WHERE id = ANY($1) FOR SHARE", componentIDs)
+	if err != nil {
+		return nil, err
+	}
+	types := make(map[int]string, len(componentIDs))
+	for rows.Next() {
+		var id int
+		var productType string
+		if err := rows.Scan(&id, &productType); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		types[id] = productType
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	for _, id := range componentIDs {
+		switch types[id] {
+		case "":
+			return nil, fmt.Errorf("component product with ID %d does not exist: %w", id, ErrInvalidBundle)
+		case models.ProductTypeBundle:
+			return nil, fmt.Errorf("component product with ID %d is itself a bundle: %w", id, ErrInvalidBundle)
+		}
+	}
+
+	_, err = tx.Exec(ctx, "UPDATE products SET type = $1, bundle_pricing = $2, bundle_discount = $3 WHERE id = $4",
+		models.ProductTypeBundle, payload.Pricing, payload.DiscountPercent, productID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(ctx, "DELETE FROM bundle_components WHERE bundle_id = $1", productID); err != nil {
+		return nil, err
+	}
+	_, err = tx.Exec(ctx, `
+		INSERT INTO bundle_components (bundle_id, component_id, quantity)
+		SELECT $1, component_id, quantity FROM unnest($2::int[], $3::int[]) AS c (component_id, quantity)`,
+		productID, componentIDs, quantities)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle, err := getBundle(ctx, tx, productID)
+	if err != nil {
+		return nil, err
+	}
+	return bundle, tx.Commit(ctx)
+}
+
+// GetBundle retrieves the components and pricing of a bundle.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+// - productID: the ID of the bundle product.
+func (r *PostgresBundleRepository) GetBundle(ctx context.Context, productID int) (*models.Bundle, error) {
+	ctx = database.WithOperation(ctx, "PostgresBundleRepository.GetBundle")
+	var bundle *models.Bundle
+	err := inTenant(ctx, r.dbConnection, func(tx pgx.Tx) error {
+		var err error
+		bundle, err = getBundle(ctx, tx, productID)
+		return err
+	})
+	return bundle, err
+}
+
+// DeleteBundle turns a bundle back into a simple product without components.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+// - productID: the ID of the bundle product.
+func (r *PostgresBundleRepository) DeleteBundle(ctx context.Context, productID int) error {
+	ctx = database.WithOperation(ctx, "PostgresBundleRepository.DeleteBundle")
+	tx, err := beginTenant(ctx, r.dbConnection)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx, "UPDATE products SET type = $1, bundle_pricing = NULL, bundle_discount = 0 WHERE id = $2 AND type = $3",
+		models.ProductTypeSimple, productID, models.ProductTypeBundle)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("bundle with product ID %d: %w", productID, ErrNotFound)
+	}
+	if _, err := tx.Exec(ctx, "DELETE FROM bundle_components WHERE bundle_id = $1", productID); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// DeleteProductCascade removes a product from every bundle it is a component of and deletes it.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+// - productID: the ID of the product to be deleted.
+func (r *PostgresBundleRepository) DeleteProductCascade(ctx context.Context, productID int) error {
+	ctx = database.WithOperation(ctx, "PostgresBundleRepository.DeleteProductCascade")
+	tx, err := beginTenant(ctx, r.dbConnection)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "DELETE FROM bundle_components WHERE component_id = $1", productID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, "DELETE FROM products WHERE id = $1", productID); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func getBundle(ctx context.Context, q database.DBConnection, productID int) (*models.Bundle, error) {
+	bundle := models.Bundle{ProductID: productID, Components: []models.BundleComponent{}}
+	err := q.QueryRow(ctx, "SELECT bundle_pricing, bundle_discount FROM products WHERE id = $1 AND type = $2",
+		productID, models.ProductTypeBundle).Scan(&bundle.Pricing, &bundle.DiscountPercent)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("bundle with product ID %d: %w", productID, ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := q.Query(ctx, "SELECT component_id, quantity FROM bundle_components WHERE bundle_id = $1 ORDER BY component_id", productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var component models.BundleComponent
+		if err := rows.Scan(&component.ProductID, &component.Quantity); err != nil {
+			return nil, err
+		}
+		bundle.Components = append(bundle.Components, component)
+	}
+	return &bundle, rows.Err()
+}
+
+// deriveBundles sets the stock and availability of the bundles among products to the number
+// of complete kits their components make up, and the prices of derived bundles to the summed
+// component prices less the bundle discount.
+func deriveBundles(ctx context.Context, q database.DBConnection, products ...*models.Product) error {
+	bundles := make(map[int]*models.Product)
+	var ids []int
+	for _, product := range products {
+		if product.Type == models.ProductTypeBundle {
+			bundles[product.ID] = product
+			ids = append(ids, product.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	rows, err := q.Query(ctx, `
+		SELECT bc.bundle_id, COALESCE(b.bundle_pricing, ''), b.bundle_discount, bc.quantity,
+			`+effectivePriceColumn+`, p.price, p.stock, `+availableStockColumn+`
+		FROM bundle_components bc
+		JOIN products b ON b.id = bc.bundle_id
+		JOIN products p ON p.id = bc.component_id
+		WHERE bc.bundle_id = ANY($1)`, ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	type totals struct {
+		derived          bool
+		discount         float64
+		price, listPrice float64
+		stock, available int
+	}
+	sums := make(map[int]*totals, len(ids))
+	for rows.Next() {
+		var bundleID, quantity, stock, available int
+		var pricing string
+		var discount, price, listPrice float64
+		if err := rows.Scan(&bundleID, &pricing, &discount, &quantity, &price, &listPrice, &stock, &available); err != nil {
+			return err
+		}
+
+		sum, ok := sums[bundleID]
+		if !ok {
+			sum = &totals{derived: pricing == models.BundlePricingDerived, discount: discount, stock: math.MaxInt, available: math.MaxInt}
+			sums[bundleID] = sum
+		}
+		sum.price += price * float64(quantity)
+		sum.listPrice += listPrice * float64(quantity)
+		sum.stock = min(sum.stock, max(stock, 0)/quantity)
+		sum.available = min(sum.available, max(available, 0)/quantity)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for id, bundle := range bundles {
+		sum, ok := sums[id]
+		if !ok {
+			bundle.Stock, bundle.Available = 0, 0
+			continue
+		}
+		bundle.Stock, bundle.Available = sum.stock, sum.available
+		if sum.derived {
+			bundle.Price = math.Round(sum.price*(100-sum.discount)) / 100
+			bundle.ListPrice = math.Round(sum.listPrice*(100-sum.discount)) / 100
+		}
+	}
+	return nil
+}
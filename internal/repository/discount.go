@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/mariosker/products_rest_api/internal/database"
+	"github.com/mariosker/products_rest_api/internal/models"
+)
+
+type DiscountRepository interface {
+	CreateDiscount(ctx context.Context, payload *models.DiscountPayload) (*models.Discount, error)
+	GetDiscountByID(ctx context.Context, id int) (*models.Discount, error)
+	GetDiscounts(ctx context.Context) ([]*models.Discount, error)
+	UpdateDiscount(ctx context.Context, id int, payload *models.DiscountPayload) (*models.Discount, error)
+	DeleteDiscount(ctx context.Context, id int) error
+}
+
+type PostgresDiscountRepository struct {
+	dbConnection database.DBConnection
+}
+
+func NewPostgresDiscountRepository(dbConnection database.DBConnection) *PostgresDiscountRepository {
+	return &PostgresDiscountRepository{dbConnection: dbConnection}
+}
+
+// discountColumns selects the columns scanned by scanDiscount.
+const discountColumns = "id, name, type, value, tiers, target, priority, stackable"
+
+// CreateDiscount inserts a new discount rule.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+// - payload: the discount rule to be created.
+func (r *PostgresDiscountRepository) CreateDiscount(ctx context.Context, payload *models.DiscountPayload) (*models.Discount, error) {
+	ctx = database.WithOperation(ctx, "PostgresDiscountRepository.CreateDiscount")
+	query := `
+		INSERT INTO discounts (name, type, value, tiers, target, priority, stackable)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING ` + discountColumns
+	var discount *models.Discount
+	err := inTenant(ctx, r.dbConnection, func(tx pgx.Tx) error {
+		var err error
+		discount, err = scanDiscount(tx.QueryRow(ctx, query, discountArgs(payload)...))
+		return err
+	})
+	return discount, err
+}
+
+// GetDiscountByID retrieves a discount rule by its ID.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+// - id: the ID of the discount to be retrieved.
+func (r *PostgresDiscountRepository) GetDiscountByID(ctx context.Context, id int) (*models.Discount, error) {
+	ctx = database.WithOperation(ctx, "PostgresDiscountRepository.GetDiscountByID")
+	var discount *models.Discount
+	err := inTenant(ctx, r.dbConnection, func(tx pgx.Tx) error {
+		var err error
+		discount, err = scanDiscount(tx.QueryRow(ctx, "SELECT "+discountColumns+" FROM discounts WHERE id = $1", id))
+		return err
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("discount with ID %d: %w", id, ErrNotFound)
+	}
+	return discount, err
+}
+
+// GetDiscounts retrieves every discount rule of the tenant in ctx in the order they are applied.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+func (r *PostgresDiscountRepository) GetDiscounts(ctx context.Context) ([]*models.Discount, error) {
+	ctx = database.WithOperation(ctx, "PostgresDiscountRepository.GetDiscounts")
+	tx, err := beginTenant(ctx, r.dbConnection)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, "SELECT "+discountColumns+" FROM discounts ORDER BY priority DESC, id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	discounts := []*models.Discount{}
+	for rows.Next() {
+		discount, err := scanDiscount(rows)
+		if err != nil {
+			return nil, err
+		}
+		discounts = append(discounts, discount)
+	}
+	return discounts, rows.Err()
+}
+
+// UpdateDiscount replaces a discount rule.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+// - id: the ID of the discount to be updated.
+// - payload: the discount rule to replace it with.
+func (r *PostgresDiscountRepository) UpdateDiscount(ctx context.Context, id int, payload *models.DiscountPayload) (*models.Discount, error) {
+	ctx = database.WithOperation(ctx, "PostgresDiscountRepository.UpdateDiscount")
+	query := `
+		UPDATE discounts SET name = $1, type = $2, value = $3, tiers = $4, target = $5, priority = $6, stackable = $7
+		WHERE id = $8
+		RETURNING ` + discountColumns
+	var discount *models.Discount
+	err := inTenant(ctx, r.dbConnection, func(tx pgx.Tx) error {
+		var err error
+		discount, err = scanDiscount(tx.QueryRow(ctx, query, append(discountArgs(payload), id)...))
+		return err
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("discount with ID %d: %w", id, ErrNotFound)
+	}
+	return discount, err
+}
+
+// DeleteDiscount deletes a discount rule by its ID.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+// - id: the ID of the discount to be deleted.
+func (r *PostgresDiscountRepository) DeleteDiscount(ctx context.Context, id int) error {
+	ctx = database.WithOperation(ctx, "PostgresDiscountRepository.DeleteDiscount")
+	return inTenant(ctx, r.dbConnection, func(tx pgx.Tx) error {
+		result, err := tx.Exec(ctx, "DELETE FROM discounts WHERE id = $1", id)
+		if err != nil {
+			return err
+		}
+		if result.RowsAffected() == 0 {
+			return fmt.Errorf("discount with ID %d: %w", id, ErrNotFound)
+		}
+		return nil
+	})
+}
+
+// discountArgs returns the column values of payload in the order of the discounts insert.
+func discountArgs(payload *models.DiscountPayload) []any {
+	tiers := payload.Tiers
+	if tiers == nil {
+		tiers = []models.DiscountTier{}
+	}
+	return []any{payload.Name, payload.Type, payload.Value, tiers, payload.Target, payload.Priority, payload.Stackable}
+}
+
+func scanDiscount(row pgx.Row) (*models.Discount, error) {
+	var discount models.Discount
+	err := row.Scan(&discount.ID, &discount.Name, &discount.Type, &discount.Value, &discount.Tiers, &discount.Target,
+		&discount.Priority, &discount.Stackable)
+	if err != nil {
+		return nil, err
+	}
+	return &discount, nil
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var (
+	// ErrNotFound is returned when the requested row does not exist.
+	ErrNotFound = errors.New("not found")
+	// ErrConflict is returned when a write violates a uniqueness constraint.
+	ErrConflict = errors.New("conflict")
+	// ErrInsufficientStock is returned when a reservation exceeds the available stock.
+	ErrInsufficientStock = errors.New("insufficient stock")
+	// ErrReservationNotActive is returned when committing or releasing a reservation
+	// that has already been committed, released or has expired.
+	ErrReservationNotActive = errors.New("reservation is not active")
+	// ErrVariantOptionsMismatch is returned when a variant's option axes differ from
+	// those of the product's other variants.
+	ErrVariantOptionsMismatch = errors.New("variant options must use the same axes as the product's other variants")
+	// ErrPriceChangeNotAllowed is returned when an update that may not change a product's price
+	// asks for a different one.
+	ErrPriceChangeNotAllowed = errors.New("price change not allowed")
+	// ErrInvalidStatusTransition is returned when a product cannot move from its current status to the requested one.
+	ErrInvalidStatusTransition = errors.New("invalid status transition")
+	// ErrInvalidBundle is returned when a bundle's components cannot be put together,
+	// e.g. because a component is itself a bundle.
+	ErrInvalidBundle = errors.New("invalid bundle")
+	// ErrInvalidRelation is returned when a product relation cannot be stored,
+	// e.g. because the related product does not exist.
+	ErrInvalidRelation = errors.New("invalid relation")
+)
+
+// Postgres error codes mapped onto repository errors.
+const (
+	pgUniqueViolation      = "23505"
+	pgForeignKeyViolation  = "23503"
+	pgRowSecurityViolation = "42501"
+)
+
+// isUniqueViolation reports whether err is a Postgres unique constraint violation.
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation
+}
+
+// isForeignKeyViolation reports whether err is a Postgres foreign key constraint violation.
+func isForeignKeyViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgForeignKeyViolation
+}
+
+// isRowSecurityViolation reports whether err is a Postgres row-level security violation, as raised when
+// a tenant-scoped transaction writes a row referencing a product of another tenant.
+func isRowSecurityViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgRowSecurityViolation
+}
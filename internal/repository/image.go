@@ -0,0 +1,225 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/mariosker/products_rest_api/internal/database"
+	"github.com/mariosker/products_rest_api/internal/models"
+)
+
+type ImageRepository interface {
+	CreateImage(ctx context.Context, image *models.ProductImage) (*models.ProductImage, error)
+	GetImageByID(ctx context.Context, productID, imageID int) (*models.ProductImage, error)
+	GetImagesByProductID(ctx context.Context, productID int) ([]*models.ProductImage, error)
+	DeleteImage(ctx context.Context, productID, imageID int) (*models.ProductImage, error)
+	GetOrphanedImageKeys(ctx context.Context, limit int) ([]string, error)
+	DeleteOrphanedImageKeys(ctx context.Context, keys []string) error
+}
+
+type PostgresImageRepository struct {
+	dbConnection database.DBConnection
+}
+
+func NewPostgresImageRepository(dbConnection database.DBConnection) *PostgresImageRepository {
+	return &PostgresImageRepository{dbConnection: dbConnection}
+}
+
+const imageColumns = "id, product_id, storage_key, COALESCE(content_sha256, ''), content_type, size_bytes, position, is_primary, created_at"
+
+// CreateImage stores the metadata of an uploaded image.
+// An image without a position is placed after the product's other images.
+// The first image of a product becomes its primary image, as does any image marked primary,
+// in which case the previous primary image is demoted.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+// - image: the image metadata to be stored; Position is ignored when negative.
+func (r *PostgresImageRepository) CreateImage(ctx context.Context, image *models.ProductImage) (*models.ProductImage, error) {
+	ctx = database.WithOperation(ctx, "PostgresImageRepository.CreateImage")
+	tx, err := beginTenant(ctx, r.dbConnection)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Lock the product so concurrent uploads agree on positions and the primary image.
+	var productID int
+	err = tx.QueryRow(ctx, "SELECT id FROM products WHERE id = $1 FOR UPDATE", image.ProductID).Scan(&productID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("product with ID %d: %w", image.ProductID, ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var count, nextPosition int
+	err = tx.QueryRow(ctx,
+		"SELECT COUNT(*), COALESCE(MAX(position) + 1, 0) FROM product_images WHERE product_id = $1",
+		image.ProductID).Scan(&count, &nextPosition)
+	if err != nil {
+		return nil, err
+	}
+
+	position := image.Position
+	if position < 0 {
+		position = nextPosition
+	}
+	primary := image.IsPrimary || count == 0
+	if primary {
+		if _, err := tx.Exec(ctx, "UPDATE product_images SET is_primary = FALSE WHERE product_id = $1 AND is_primary", image.ProductID); err != nil {
+			return nil, err
+		}
+	}
+
+	created, err := scanImage(tx.QueryRow(ctx, `
+		INSERT INTO product_images (product_id, storage_key, content_sha256, content_type, size_bytes, position, is_primary)
+		VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6, $7)
+		RETURNING `+imageColumns,
+		image.ProductID, image.StorageKey, image.ContentHash, image.ContentType, image.Size, position, primary))
+	if err != nil {
+		return nil, err
+	}
+	return created, tx.Commit(ctx)
+}
+
+// GetImageByID retrieves the metadata of a single image of a product.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+// - productID: the ID of the product the image belongs to.
+// - imageID: the ID of the image to be retrieved.
+func (r *PostgresImageRepository) GetImageByID(ctx context.Context, productID, imageID int) (*models.ProductImage, error) {
+	ctx = database.WithOperation(ctx, "PostgresImageRepository.GetImageByID")
+	query := "SELECT " + imageColumns + " FROM product_images WHERE product_id = $1 AND id = $2"
+	var image *models.ProductImage
+	err := inTenant(ctx, r.dbConnection, func(tx pgx.Tx) error {
+		var err error
+		image, err = scanImage(tx.QueryRow(ctx, query, productID, imageID))
+		return err
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("image with ID %d: %w", imageID, ErrNotFound)
+	}
+	return image, err
+}
+
+// GetImagesByProductID retrieves the metadata of every image of a product ordered by position.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+// - productID: the ID of the product whose images are retrieved.
+func (r *PostgresImageRepository) GetImagesByProductID(ctx context.Context, productID int) ([]*models.ProductImage, error) {
+	ctx = database.WithOperation(ctx, "PostgresImageRepository.GetImagesByProductID")
+	tx, err := beginTenant(ctx, r.dbConnection)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var exists bool
+	if err := tx.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM products WHERE id = $1)", productID).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("product with ID %d: %w", productID, ErrNotFound)
+	}
+
+	query := "SELECT " + imageColumns + " FROM product_images WHERE product_id = $1 ORDER BY position, id"
+	rows, err := tx.Query(ctx, query, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	images := []*models.ProductImage{}
+	for rows.Next() {
+		image, err := scanImage(rows)
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, image)
+	}
+	return images, rows.Err()
+}
+
+// DeleteImage deletes the metadata of an image and returns it so the caller can remove the stored content.
+// Its storage key is also queued in orphaned_images, in case the content is not removed.
+// When the primary image is deleted, the next image by position becomes primary.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+// - productID: the ID of the product the image belongs to.
+// - imageID: the ID of the image to be deleted.
+func (r *PostgresImageRepository) DeleteImage(ctx context.Context, productID, imageID int) (*models.ProductImage, error) {
+	ctx = database.WithOperation(ctx, "PostgresImageRepository.DeleteImage")
+	tx, err := beginTenant(ctx, r.dbConnection)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	image, err := scanImage(tx.QueryRow(ctx,
+		"DELETE FROM product_images WHERE product_id = $1 AND id = $2 RETURNING "+imageColumns,
+		productID, imageID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("image with ID %d: %w", imageID, ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if image.IsPrimary {
+		_, err = tx.Exec(ctx, `
+			UPDATE product_images SET is_primary = TRUE
+			WHERE id = (SELECT id FROM product_images WHERE product_id = $1 ORDER BY position, id LIMIT 1)`,
+			productID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return image, tx.Commit(ctx)
+}
+
+// GetOrphanedImageKeys returns the storage keys of deleted images whose content may still be stored,
+// oldest first. Images deleted on their own and those deleted with their product are both included.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+// - limit: the maximum number of keys to return.
+func (r *PostgresImageRepository) GetOrphanedImageKeys(ctx context.Context, limit int) ([]string, error) {
+	ctx = database.WithOperation(ctx, "PostgresImageRepository.GetOrphanedImageKeys")
+	rows, err := r.dbConnection.Query(ctx,
+		"SELECT storage_key FROM orphaned_images ORDER BY orphaned_at, storage_key LIMIT $1", limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []string{}
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+// DeleteOrphanedImageKeys forgets the storage keys of deleted images once their content has been removed.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+// - keys: the storage keys whose content has been removed.
+func (r *PostgresImageRepository) DeleteOrphanedImageKeys(ctx context.Context, keys []string) error {
+	ctx = database.WithOperation(ctx, "PostgresImageRepository.DeleteOrphanedImageKeys")
+	_, err := r.dbConnection.Exec(ctx, "DELETE FROM orphaned_images WHERE storage_key = ANY($1)", keys)
+	return err
+}
+
+func scanImage(row pgx.Row) (*models.ProductImage, error) {
+	var image models.ProductImage
+	err := row.Scan(&image.ID, &image.ProductID, &image.StorageKey, &image.ContentHash, &image.ContentType, &image.Size,
+		&image.Position, &image.IsPrimary, &image.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &image, nil
+}
@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/mariosker/products_rest_api/internal/database"
+	"github.com/mariosker/products_rest_api/internal/models"
+)
+
+type PriceScheduleRepository interface {
+	CreatePriceSchedule(ctx context.Context, productID int, payload *models.PriceSchedulePayload) (*models.PriceSchedule, error)
+	GetPriceScheduleByID(ctx context.Context, productID, scheduleID int) (*models.PriceSchedule, error)
+	GetPriceSchedulesByProductID(ctx context.Context, productID int) ([]*models.PriceSchedule, error)
+	UpdatePriceSchedule(ctx context.Context, productID, scheduleID int, payload *models.PriceSchedulePayload) (*models.PriceSchedule, error)
+	DeletePriceSchedule(ctx context.Context, productID, scheduleID int) error
+}
+
+type PostgresPriceScheduleRepository struct {
+	dbConnection database.DBConnection
+}
+
+func NewPostgresPriceScheduleRepository(dbConnection database.DBConnection) *PostgresPriceScheduleRepository {
+	return &PostgresPriceScheduleRepository{dbConnection: dbConnection}
+}
+
+// priceScheduleColumns selects the columns scanned by scanPriceSchedule.
+const priceScheduleColumns = "id, product_id, price, starts_at, ends_at"
+
+// CreatePriceSchedule schedules a price for the given product.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+// - productID: the ID of the product the price applies to.
+// - payload: the price and the period it applies in.
+func (r *PostgresPriceScheduleRepository) CreatePriceSchedule(ctx context.Context, productID int, payload *models.PriceSchedulePayload) (*models.PriceSchedule, error) {
+	ctx = database.WithOperation(ctx, "PostgresPriceScheduleRepository.CreatePriceSchedule")
+	query := `
+		INSERT INTO price_schedules (product_id, price, starts_at, ends_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING ` + priceScheduleColumns
+	var schedule *models.PriceSchedule
+	err := inTenant(ctx, r.dbConnection, func(tx pgx.Tx) error {
+		var err error
+		schedule, err = scanPriceSchedule(tx.QueryRow(ctx, query, productID, payload.Price, payload.StartsAt, payload.EndsAt))
+		return err
+	})
+	if isForeignKeyViolation(err) || isRowSecurityViolation(err) {
+		return nil, fmt.Errorf("product with ID %d: %w", productID, ErrNotFound)
+	}
+	return schedule, err
+}
+
+// GetPriceScheduleByID retrieves a single price schedule of a product.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+// - productID: the ID of the product the schedule belongs to.
+// - scheduleID: the ID of the schedule to be retrieved.
+func (r *PostgresPriceScheduleRepository) GetPriceScheduleByID(ctx context.Context, productID, scheduleID int) (*models.PriceSchedule, error) {
+	ctx = database.WithOperation(ctx, "PostgresPriceScheduleRepository.GetPriceScheduleByID")
+	query := "SELECT " + priceScheduleColumns + " FROM price_schedules WHERE product_id = $1 AND id = $2"
+	var schedule *models.PriceSchedule
+	err := inTenant(ctx, r.dbConnection, func(tx pgx.Tx) error {
+		var err error
+		schedule, err = scanPriceSchedule(tx.QueryRow(ctx, query, productID, scheduleID))
+		return err
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("price schedule with ID %d: %w", scheduleID, ErrNotFound)
+	}
+	return schedule, err
+}
+
+// GetPriceSchedulesByProductID retrieves every price schedule of a product ordered by start time.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+// - productID: the ID of the product whose schedules are retrieved.
+func (r *PostgresPriceScheduleRepository) GetPriceSchedulesByProductID(ctx context.Context, productID int) ([]*models.PriceSchedule, error) {
+	ctx = database.WithOperation(ctx, "PostgresPriceScheduleRepository.GetPriceSchedulesByProductID")
+	tx, err := beginTenant(ctx, r.dbConnection)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var exists bool
+	if err := tx.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM products WHERE id = $1)", productID).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("product with ID %d: %w", productID, ErrNotFound)
+	}
+
+	query := "SELECT " + priceScheduleColumns + " FROM price_schedules WHERE product_id = $1 ORDER BY starts_at, id"
+	rows, err := tx.Query(ctx, query, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	schedules := []*models.PriceSchedule{}
+	for rows.Next() {
+		schedule, err := scanPriceSchedule(rows)
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, schedule)
+	}
+	return schedules, rows.Err()
+}
+
+// UpdatePriceSchedule replaces the price and period of a price schedule.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+// - productID: the ID of the product the schedule belongs to.
+// - scheduleID: the ID of the schedule to be updated.
+// - payload: the price and the period it applies in.
+func (r *PostgresPriceScheduleRepository) UpdatePriceSchedule(ctx context.Context, productID, scheduleID int, payload *models.PriceSchedulePayload) (*models.PriceSchedule, error) {
+	ctx = database.WithOperation(ctx, "PostgresPriceScheduleRepository.UpdatePriceSchedule")
+	query := `
+		UPDATE price_schedules SET price = $3, starts_at = $4, ends_at = $5
+		WHERE product_id = $1 AND id = $2
+		RETURNING ` + priceScheduleColumns
+	var schedule *models.PriceSchedule
+	err := inTenant(ctx, r.dbConnection, func(tx pgx.Tx) error {
+		var err error
+		schedule, err = scanPriceSchedule(tx.QueryRow(ctx, query, productID, scheduleID, payload.Price, payload.StartsAt, payload.EndsAt))
+		return err
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("price schedule with ID %d: %w", scheduleID, ErrNotFound)
+	}
+	return schedule, err
+}
+
+// DeletePriceSchedule deletes a price schedule of a product.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+// - productID: the ID of the product the schedule belongs to.
+// - scheduleID: the ID of the schedule to be deleted.
+func (r *PostgresPriceScheduleRepository) DeletePriceSchedule(ctx context.Context, productID, scheduleID int) error {
+	ctx = database.WithOperation(ctx, "PostgresPriceScheduleRepository.DeletePriceSchedule")
+	return inTenant(ctx, r.dbConnection, func(tx pgx.Tx) error {
+		result, err := tx.Exec(ctx, "DELETE FROM price_schedules WHERE product_id = $1 AND id = $2", productID, scheduleID)
+		if err != nil {
+			return err
+		}
+		if result.RowsAffected() == 0 {
+			return fmt.Errorf("price schedule with ID %d: %w", scheduleID, ErrNotFound)
+		}
+		return nil
+	})
+}
+
+func scanPriceSchedule(row pgx.Row) (*models.PriceSchedule, error) {
+	var schedule models.PriceSchedule
+	err := row.Scan(&schedule.ID, &schedule.ProductID, &schedule.Price, &schedule.StartsAt, &schedule.EndsAt)
+	if err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
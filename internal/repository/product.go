@@ -2,41 +2,138 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/mariosker/products_rest_api/internal/attributes"
 	"github.com/mariosker/products_rest_api/internal/database"
 	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/mariosker/products_rest_api/internal/utils"
 )
 
 type ProductRepository interface {
 	CreateProduct(ctx context.Context, product *models.CreateProductPayload) (int, error)
 	GetProductByID(ctx context.Context, id int) (*models.Product, error)
-	GetProducts(ctx context.Context, limit, offset int) ([]*models.Product, error)
-	UpdateProduct(ctx context.Context, id int, payload *models.UpdateProductPayload) error
+	GetProductBySKU(ctx context.Context, sku string) (*models.Product, error)
+	GetProductBySlug(ctx context.Context, slug string) (*models.Product, error)
+	GetProductByGTIN(ctx context.Context, gtin string) (*models.Product, error)
+	GetProducts(ctx context.Context, limit, offset int, filter models.ProductFilter) ([]*models.Product, error)
+	UpdateProduct(ctx context.Context, id int, payload *models.UpdateProductPayload, allowPriceChange bool) (*models.Product, error)
 	DeleteProduct(ctx context.Context, id int) error
+	ChangeProductStatus(ctx context.Context, id int, status string, publishAt *time.Time) (*models.Product, error)
+	PublishScheduledProducts(ctx context.Context) (int64, error)
 }
 
 type PostgresProductRepository struct {
 	dbConnection database.DBConnection
 }
 
+// availableStockColumn computes the stock of products p that is not held by an active reservation.
+const availableStockColumn = `p.stock - COALESCE((
+	SELECT SUM(ri.quantity) FROM reservation_items ri
+	JOIN reservations r ON r.id = ri.reservation_id
+	WHERE ri.product_id = p.id AND r.status = 'active' AND r.expires_at > now()
+), 0)`
+
+// effectivePriceColumn computes the price of products p under the active price schedule, falling
+// back to the list price. When schedules overlap, the one that started last applies.
+const effectivePriceColumn = `COALESCE((
+	SELECT ps.price FROM price_schedules ps
+	WHERE ps.product_id = p.id AND ps.starts_at <= now() AND (ps.ends_at IS NULL OR ps.ends_at > now())
+	ORDER BY ps.starts_at DESC, ps.id DESC LIMIT 1
+), p.price)`
+
+// productColumns selects the columns scanned by scanProduct from products p.
+const productColumns = "p.id, p.name, COALESCE(p.description, ''), " + effectivePriceColumn + ", p.price, COALESCE(p.sku, ''), p.slug, COALESCE(p.gtin, ''), COALESCE(p.category, ''), p.attributes, p.tags, p.tax_class, p.stock, p.status, p.publish_at, " + availableStockColumn + ", p.type, p.rating_average, p.review_count"
+
+// Unique constraints on products, used to tell slug collisions apart from other conflicts.
+const (
+	productsSKUKey  = "products_sku_key"
+	productsSlugKey = "products_slug_key"
+	productsGTINKey = "products_gtin_key"
+)
+
+// maxSlugAttempts bounds the retries when a generated slug is taken concurrently.
+const maxSlugAttempts = 3
+
 func NewPostgresProductRepository(dbConnection database.DBConnection) *PostgresProductRepository {
 	return &PostgresProductRepository{dbConnection: dbConnection}
 }
 
 // CreateProduct inserts a new product into the database and returns the new product's ID.
+// When no slug is given, one is generated from the name with a numeric suffix on collision.
 // Parameters:
 // - ctx: The context for managing request-scoped values, cancelation, and deadlines.
 // - product: The payload containing the product details to be created.
 func (r *PostgresProductRepository) CreateProduct(ctx context.Context, product *models.CreateProductPayload) (int, error) {
-	var id int
+	ctx = database.WithOperation(ctx, "PostgresProductRepository.CreateProduct")
+	gtin, _ := utils.NormalizeGTIN(product.GTIN)
+	attrs := product.Attributes
+	if attrs == nil {
+		attrs = map[string]any{}
+	}
+	tags := product.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	taxClass := product.TaxClass
+	if taxClass == "" {
+		taxClass = models.TaxClassStandard
+	}
+	status := product.Status
+	if status == "" {
+		status = models.ProductStatusPublished
+	}
 
-	err := r.dbConnection.QueryRow(ctx, "INSERT INTO products (name, price) VALUES ($1, $2) RETURNING id", product.Name, product.Price).Scan(&id)
+	tx, err := beginTenant(ctx, r.dbConnection)
 	if err != nil {
 		return -1, err
 	}
+	defer tx.Rollback(ctx)
 
-	return id, nil
+	if err := validateAttributes(ctx, tx, product.Category, attrs); err != nil {
+		return -1, err
+	}
+
+	for attempt := 1; ; attempt++ {
+		slug := product.Slug
+		if slug == "" {
+			if slug, err = uniqueSlug(ctx, tx, utils.Slugify(product.Name)); err != nil {
+				return -1, err
+			}
+		}
+
+		// The insert runs in a savepoint so that it can be retried when the generated slug is taken.
+		savepoint, err := tx.Begin(ctx)
+		if err != nil {
+			return -1, err
+		}
+		var id int
+		err = savepoint.QueryRow(ctx,
+			`INSERT INTO products (name, description, price, stock, sku, slug, gtin, category, attributes, tags, tax_class, status, publish_at)
+			VALUES ($1, NULLIF($2, ''), $3, $4, NULLIF($5, ''), $6, NULLIF($7, ''), NULLIF($8, ''), $9, $10, $11, $12, $13) RETURNING id`,
+			product.Name, product.Description, product.Price, product.Stock, product.SKU, slug, gtin, product.Category, attrs, tags, taxClass, status, product.PublishAt,
+		).Scan(&id)
+		if err == nil {
+			if err := savepoint.Commit(ctx); err != nil {
+				return -1, err
+			}
+			return id, tx.Commit(ctx)
+		}
+		savepoint.Rollback(ctx)
+
+		// Another request took the generated slug between lookup and insert; pick the next one.
+		if product.Slug == "" && attempt < maxSlugAttempts && violatesConstraint(err, productsSlugKey) {
+			continue
+		}
+		return -1, productConflict(err, product.SKU, slug, product.GTIN)
+	}
 }
 
 // GetProductByID retrieves a product from the database by its ID.
@@ -44,12 +141,75 @@ func (r *PostgresProductRepository) CreateProduct(ctx context.Context, product *
 // - ctx: context for managing request deadlines and cancellation signals.
 // - id: the ID of the product to be retrieved.
 func (r *PostgresProductRepository) GetProductByID(ctx context.Context, id int) (*models.Product, error) {
-	var product models.Product
-	err := r.dbConnection.QueryRow(ctx, "SELECT id, name, price FROM products WHERE id = $1", id).Scan(&product.ID, &product.Name, &product.Price)
+	ctx = database.WithOperation(ctx, "PostgresProductRepository.GetProductByID")
+	product, err := r.getProduct(ctx, "SELECT "+productColumns+" FROM products p WHERE p.id = $1", id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("product with ID %d: %w", id, ErrNotFound)
+	}
+	return product, err
+}
+
+// GetProductBySKU retrieves a product from the database by its stock keeping unit.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+// - sku: the SKU of the product to be retrieved.
+func (r *PostgresProductRepository) GetProductBySKU(ctx context.Context, sku string) (*models.Product, error) {
+	ctx = database.WithOperation(ctx, "PostgresProductRepository.GetProductBySKU")
+	product, err := r.getProduct(ctx, "SELECT "+productColumns+" FROM products p WHERE p.sku = $1", sku)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("product with SKU %q: %w", sku, ErrNotFound)
+	}
+	return product, err
+}
+
+// GetProductBySlug retrieves a product from the database by its URL slug.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+// - slug: the slug of the product to be retrieved.
+func (r *PostgresProductRepository) GetProductBySlug(ctx context.Context, slug string) (*models.Product, error) {
+	ctx = database.WithOperation(ctx, "PostgresProductRepository.GetProductBySlug")
+	product, err := r.getProduct(ctx, "SELECT "+productColumns+" FROM products p WHERE p.slug = $1", slug)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("product with slug %q: %w", slug, ErrNotFound)
+	}
+	return product, err
+}
+
+// GetProductByGTIN retrieves a product from the database by its barcode.
+// UPC-A, EAN-13 and other GTIN forms of the same code all match.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+// - gtin: the GTIN-8, GTIN-12, GTIN-13 or GTIN-14 of the product to be retrieved.
+func (r *PostgresProductRepository) GetProductByGTIN(ctx context.Context, gtin string) (*models.Product, error) {
+	ctx = database.WithOperation(ctx, "PostgresProductRepository.GetProductByGTIN")
+	normalized, ok := utils.NormalizeGTIN(gtin)
+	if !ok {
+		return nil, fmt.Errorf("product with GTIN %q: %w", gtin, ErrNotFound)
+	}
+
+	product, err := r.getProduct(ctx, "SELECT "+productColumns+" FROM products p WHERE p.gtin = $1", normalized)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("product with GTIN %q: %w", gtin, ErrNotFound)
+	}
+	return product, err
+}
+
+// getProduct retrieves the product selected by query, which takes a single argument.
+func (r *PostgresProductRepository) getProduct(ctx context.Context, query string, arg any) (*models.Product, error) {
+	tx, err := beginTenant(ctx, r.dbConnection)
 	if err != nil {
 		return nil, err
 	}
-	return &product, nil
+	defer tx.Rollback(ctx)
+
+	product, err := scanProduct(tx.QueryRow(ctx, query, arg))
+	if err != nil {
+		return nil, err
+	}
+	if err := deriveBundles(ctx, tx, product); err != nil {
+		return nil, err
+	}
+	return product, tx.Commit(ctx)
 }
 
 // GetProducts retrieves a list of products from the database with pagination support.
@@ -57,8 +217,20 @@ func (r *PostgresProductRepository) GetProductByID(ctx context.Context, id int)
 // - ctx: context for managing request deadlines and cancellation signals.
 // - limit: the maximum number of products to return.
 // - offset: the number of products to skip before starting to return products.
-func (r *PostgresProductRepository) GetProducts(ctx context.Context, limit, offset int) ([]*models.Product, error) {
-	rows, err := r.dbConnection.Query(ctx, "SELECT id, name, price FROM products LIMIT $1 OFFSET $2", limit, offset)
+// - filter: conditions the returned products must match, and the order to return them in.
+func (r *PostgresProductRepository) GetProducts(ctx context.Context, limit, offset int, filter models.ProductFilter) ([]*models.Product, error) {
+	ctx = database.WithOperation(ctx, "PostgresProductRepository.GetProducts")
+	where, args := productFilterClause(filter)
+	args = append(args, limit, offset)
+	query := fmt.Sprintf("SELECT %s FROM products p %s %s LIMIT $%d OFFSET $%d", productColumns, where, productOrders[filter.Sort], len(args)-1, len(args))
+
+	tx, err := beginTenant(ctx, r.dbConnection)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -66,41 +238,323 @@ func (r *PostgresProductRepository) GetProducts(ctx context.Context, limit, offs
 
 	var products []*models.Product
 	for rows.Next() {
-		var product models.Product
-		if err := rows.Scan(&product.ID, &product.Name, &product.Price); err != nil {
+		product, err := scanProduct(rows)
+		if err != nil {
 			return nil, err
 		}
-		products = append(products, &product)
+		products = append(products, product)
 	}
-	return products, nil
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+	if err := deriveBundles(ctx, tx, products...); err != nil {
+		return nil, err
+	}
+	return products, tx.Commit(ctx)
 }
 
-// UpdateProduct updates an existing product in the database.
+// UpdateProduct updates an existing product in the database and returns it as stored.
+// Description, stock, SKU, slug, GTIN, category, attributes, tags and tax class are left unchanged when omitted from the payload.
+// The resulting attributes are validated against the resulting category.
 // Parameters:
 // - ctx: context for managing request deadlines and cancellation signals.
 // - id: the ID of the product to be updated.
 // - payload: the product data to be updated.
-func (r *PostgresProductRepository) UpdateProduct(ctx context.Context, id int, payload *models.UpdateProductPayload) error {
-	result, err := r.dbConnection.Exec(ctx, "UPDATE products SET name=$1, price=$2 WHERE id=$3", payload.Name, payload.Price, id)
+// - allowPriceChange: whether payload may change the list price; if not, it must send the current one.
+func (r *PostgresProductRepository) UpdateProduct(ctx context.Context, id int, payload *models.UpdateProductPayload, allowPriceChange bool) (*models.Product, error) {
+	ctx = database.WithOperation(ctx, "PostgresProductRepository.UpdateProduct")
+	var gtin *string
+	if payload.GTIN != nil {
+		normalized, _ := utils.NormalizeGTIN(*payload.GTIN)
+		gtin = &normalized
+	}
+
+	tx, err := beginTenant(ctx, r.dbConnection)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// The price is checked under the row lock, so a concurrent update cannot change it in between.
+	var price float64
+	var category string
+	var attrs map[string]any
+	err = tx.QueryRow(ctx, "SELECT price, COALESCE(category, ''), attributes FROM products WHERE id = $1 FOR UPDATE", id).Scan(&price, &category, &attrs)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("product with ID %d: %w", id, ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !allowPriceChange && price != payload.Price {
+		return nil, ErrPriceChangeNotAllowed
+	}
+	if payload.Category != nil {
+		category = *payload.Category
+	}
+	if payload.Attributes != nil {
+		attrs = payload.Attributes
+	}
+	if err := validateAttributes(ctx, tx, category, attrs); err != nil {
+		return nil, err
 	}
 
-	if result.RowsAffected() == 0 {
-		return fmt.Errorf("product with ID %d not found", id)
+	_, err = tx.Exec(ctx, `
+		UPDATE products SET name=$1, price=$2, stock=COALESCE($3, stock),
+			sku=CASE WHEN $4::text IS NULL THEN sku ELSE NULLIF($4, '') END,
+			slug=COALESCE($5, slug),
+			gtin=CASE WHEN $6::text IS NULL THEN gtin ELSE NULLIF($6, '') END,
+			category=NULLIF($7, ''), attributes=$8,
+			description=CASE WHEN $9::text IS NULL THEN description ELSE NULLIF($9, '') END,
+			tags=COALESCE($10, tags), tax_class=COALESCE($11, tax_class)
+		WHERE id=$12`,
+		payload.Name, payload.Price, payload.Stock, payload.SKU, payload.Slug, gtin, category, attrs, payload.Description, payload.Tags, payload.TaxClass, id)
+	if err != nil {
+		return nil, productConflict(err, deref(payload.SKU), deref(payload.Slug), deref(payload.GTIN))
 	}
 
-	return nil
+	product, err := scanProduct(tx.QueryRow(ctx, "SELECT "+productColumns+" FROM products p WHERE p.id = $1", id))
+	if err != nil {
+		return nil, err
+	}
+	if err := deriveBundles(ctx, tx, product); err != nil {
+		return nil, err
+	}
+	return product, tx.Commit(ctx)
 }
 
 // DeleteProduct deletes a product from the database by its ID.
+// A product that is still a component of a bundle cannot be deleted.
 // Parameters:
 // - ctx: context for managing request deadlines and cancellation signals.
 // - id: the ID of the product to be deleted.
 func (r *PostgresProductRepository) DeleteProduct(ctx context.Context, id int) error {
-	_, err := r.dbConnection.Exec(ctx, "DELETE FROM products WHERE id = $1", id)
+	ctx = database.WithOperation(ctx, "PostgresProductRepository.DeleteProduct")
+	tx, err := beginTenant(ctx, r.dbConnection)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, "DELETE FROM products WHERE id = $1", id)
+	if isForeignKeyViolation(err) {
+		return fmt.Errorf("product with ID %d is a component of a bundle: %w", id, ErrConflict)
+	}
+	if err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// uniqueSlug returns base, or base suffixed with the lowest free "-N" (N >= 2).
+func uniqueSlug(ctx context.Context, q database.DBConnection, base string) (string, error) {
+	rows, err := q.Query(ctx, "SELECT slug FROM products WHERE slug = $1 OR slug LIKE $1 || '-%'", base)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	taken := make(map[string]bool)
+	for rows.Next() {
+		var slug string
+		if err := rows.Scan(&slug); err != nil {
+			return "", err
+		}
+		taken[slug] = true
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+
+	if !taken[base] {
+		return base, nil
+	}
+	for n := 2; ; n++ {
+		if candidate := base + "-" + strconv.Itoa(n); !taken[candidate] {
+			return candidate, nil
+		}
+	}
+}
+
+// productStatusTransitions lists the statuses each product status can move to.
+var productStatusTransitions = map[string][]string{
+	models.ProductStatusDraft:     {models.ProductStatusPublished},
+	models.ProductStatusPublished: {models.ProductStatusArchived},
+	models.ProductStatusArchived:  {models.ProductStatusDraft},
+}
+
+// ChangeProductStatus moves a product to status, enforcing the allowed lifecycle transitions,
+// and returns the updated product. A publishAt time schedules a draft to be published by
+// PublishScheduledProducts instead of publishing it now; any other change clears the schedule.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+// - id: the ID of the product.
+// - status: the status to move the product to.
+// - publishAt: when to publish the product, or nil to change its status now.
+func (r *PostgresProductRepository) ChangeProductStatus(ctx context.Context, id int, status string, publishAt *time.Time) (*models.Product, error) {
+	ctx = database.WithOperation(ctx, "PostgresProductRepository.ChangeProductStatus")
+	tx, err := beginTenant(ctx, r.dbConnection)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var current string
+	err = tx.QueryRow(ctx, "SELECT status FROM products WHERE id = $1 FOR UPDATE", id).Scan(&current)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("product with ID %d: %w", id, ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(productStatusTransitions[current], status) {
+		return nil, fmt.Errorf("cannot change product status from %s to %s: %w", current, status, ErrInvalidStatusTransition)
+	}
+
+	if publishAt != nil {
+		_, err = tx.Exec(ctx, "UPDATE products SET publish_at = $1 WHERE id = $2", publishAt, id)
+	} else {
+		_, err = tx.Exec(ctx, "UPDATE products SET status = $1, publish_at = NULL WHERE id = $2", status, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := scanProduct(tx.QueryRow(ctx, "SELECT "+productColumns+" FROM products p WHERE p.id = $1", id))
+	if err != nil {
+		return nil, err
+	}
+	if err := deriveBundles(ctx, tx, product); err != nil {
+		return nil, err
+	}
+	return product, tx.Commit(ctx)
+}
+
+// PublishScheduledProducts publishes every draft whose publish time has passed, of every tenant
+// unless ctx carries one, and returns how many were published.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+func (r *PostgresProductRepository) PublishScheduledProducts(ctx context.Context) (int64, error) {
+	ctx = database.WithOperation(ctx, "PostgresProductRepository.PublishScheduledProducts")
+	tx, err := beginTenant(ctx, r.dbConnection)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx,
+		"UPDATE products SET status = $1, publish_at = NULL WHERE status = $2 AND publish_at <= now()",
+		models.ProductStatusPublished, models.ProductStatusDraft)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), tx.Commit(ctx)
+}
+
+// validateAttributes checks attrs against the attribute definitions of category.
+func validateAttributes(ctx context.Context, q database.DBConnection, category string, attrs map[string]any) error {
+	if category == "" {
+		return nil
+	}
+	definitions, err := getAttributeDefinitions(ctx, q, category)
 	if err != nil {
 		return err
 	}
-	return nil
+	return attributes.Validate(definitions, attrs)
+}
+
+// attributeComparisons maps range filter operators to SQL comparison operators.
+var attributeComparisons = map[string]string{
+	models.AttributeOpLt:  "<",
+	models.AttributeOpLte: "<=",
+	models.AttributeOpGt:  ">",
+	models.AttributeOpGte: ">=",
+}
+
+// productOrders maps product listing orders to ORDER BY clauses.
+var productOrders = map[string]string{
+	models.ProductSortRating:     "ORDER BY p.rating_average ASC NULLS LAST, p.review_count DESC, p.id",
+	models.ProductSortRatingDesc: "ORDER BY p.rating_average DESC NULLS LAST, p.review_count DESC, p.id",
+}
+
+// productFilterClause builds the WHERE clause and its arguments for a product listing.
+// Equality filters use JSONB containment so they are served by the GIN index on attributes;
+// a value that parses as a number or boolean also matches attributes stored with that type.
+func productFilterClause(filter models.ProductFilter) (string, []any) {
+	var conditions []string
+	var args []any
+	arg := func(v any) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	if len(filter.Statuses) > 0 {
+		conditions = append(conditions, "p.status = ANY("+arg(filter.Statuses)+"::text[])")
+	}
+
+	for _, f := range filter.Attributes {
+		if f.Op == models.AttributeOpEq {
+			matches := []string{"p.attributes @> " + arg(map[string]any{f.Name: f.Value})}
+			if n, err := strconv.ParseFloat(f.Value, 64); err == nil {
+				matches = append(matches, "p.attributes @> "+arg(map[string]any{f.Name: n}))
+			}
+			if b, err := strconv.ParseBool(f.Value); err == nil {
+				matches = append(matches, "p.attributes @> "+arg(map[string]any{f.Name: b}))
+			}
+			conditions = append(conditions, "("+strings.Join(matches, " OR ")+")")
+			continue
+		}
+
+		name := arg(f.Name)
+		conditions = append(conditions, fmt.Sprintf(
+			"(CASE WHEN jsonb_typeof(p.attributes -> %s) = 'number' THEN (p.attributes ->> %s)::numeric %s %s::numeric ELSE false END)",
+			name, name, attributeComparisons[f.Op], arg(f.Value)))
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// productConflict wraps unique violations on products in ErrConflict and returns other errors unchanged.
+func productConflict(err error, sku, slug, gtin string) error {
+	switch {
+	case violatesConstraint(err, productsSKUKey):
+		return fmt.Errorf("product with SKU %q already exists: %w", sku, ErrConflict)
+	case violatesConstraint(err, productsSlugKey):
+		return fmt.Errorf("product with slug %q already exists: %w", slug, ErrConflict)
+	case violatesConstraint(err, productsGTINKey):
+		return fmt.Errorf("product with GTIN %q already exists: %w", gtin, ErrConflict)
+	case isUniqueViolation(err):
+		return fmt.Errorf("%v: %w", err, ErrConflict)
+	default:
+		return err
+	}
+}
+
+// violatesConstraint reports whether err is a unique violation of the named constraint.
+func violatesConstraint(err error, constraint string) bool {
+	var pgErr *pgconn.PgError
+	return isUniqueViolation(err) && errors.As(err, &pgErr) && pgErr.ConstraintName == constraint
+}
+
+// deref returns the string s points to, or "" when s is nil.
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func scanProduct(row pgx.Row) (*models.Product, error) {
+	var product models.Product
+	err := row.Scan(&product.ID, &product.Name, &product.Description, &product.Price, &product.ListPrice, &product.SKU, &product.Slug, &product.GTIN,
+		&product.Category, &product.Attributes, &product.Tags, &product.TaxClass, &product.Stock, &product.Status, &product.PublishAt, &product.Available, &product.Type,
+		&product.RatingAverage, &product.ReviewCount)
+	if err != nil {
+		return nil, err
+	}
+	return &product, nil
 }
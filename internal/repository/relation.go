@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/mariosker/products_rest_api/internal/database"
+	"github.com/mariosker/products_rest_api/internal/models"
+)
+
+type RelationRepository interface {
+	PutRelations(ctx context.Context, productID int, relations []models.ProductRelationPayload) ([]*models.ProductRelation, error)
+	GetRelations(ctx context.Context, productID int, relationType string, publishedOnly bool) ([]*models.ProductRelation, error)
+}
+
+type PostgresRelationRepository struct {
+	dbConnection database.DBConnection
+}
+
+func NewPostgresRelationRepository(dbConnection database.DBConnection) *PostgresRelationRepository {
+	return &PostgresRelationRepository{dbConnection: dbConnection}
+}
+
+// PutRelations replaces every relation of a product. Relations of the same type are
+// positioned in the order they are listed.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+// - productID: the ID of the product the relations start from.
+// - relations: the related products and relation types, in display order.
+func (r *PostgresRelationRepository) PutRelations(ctx context.Context, productID int, relations []models.ProductRelationPayload) ([]*models.ProductRelation, error) {
+	ctx = database.WithOperation(ctx, "PostgresRelationRepository.PutRelations")
+	types := make([]string, 0, len(relations))
+	relatedIDs := make([]int, 0, len(relations))
+	positions := make([]int, 0, len(relations))
+	next := make(map[string]int)
+	seen := make(map[models.ProductRelationPayload]bool, len(relations))
+	for _, relation := range relations {
+		if relation.ProductID == productID {
+			return nil, fmt.Errorf("product cannot be related to itself: %w", ErrInvalidRelation)
+		}
+		if seen[relation] {
+			return nil, fmt.Errorf("%s relation to product %d is listed more than once: %w", relation.Type, relation.ProductID, ErrInvalidRelation)
+		}
+		seen[relation] = true
+		types = append(types, relation.Type)
+		relatedIDs = append(relatedIDs, relation.ProductID)
+		positions = append(positions, next[relation.Type])
+		next[relation.Type]++
+	}
+
+	tx, err := beginTenant(ctx, r.dbConnection)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var exists bool
+	err = tx.QueryRow(ctx, "SELECT true FROM products WHERE id = $1 FOR UPDATE", productID).Scan(&exists)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("product with ID %d: %w", productID, ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec(ctx, "DELETE FROM product_relations WHERE product_id = $1", productID); err != nil {
+		return nil, err
+	}
+	_, err = tx.Exec(ctx, `
+		INSERT INTO product_relations (product_id, type, related_id, position)
+		SELECT $1, type, related_id, position FROM unnest($2::text[], $3::int[], $4::int[]) AS r (type, related_id, position)`,
+		productID, types, relatedIDs, positions)
+	if isForeignKeyViolation(err) || isRowSecurityViolation(err) {
+		return nil, fmt.Errorf("related product does not exist: %w", ErrInvalidRelation)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := getRelations(ctx, tx, productID, "", false)
+	if err != nil {
+		return nil, err
+	}
+	return stored, tx.Commit(ctx)
+}
+
+// GetRelations retrieves the relations of a product ordered by type and position.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+// - productID: the ID of the product the relations start from.
+// - relationType: the type of relations to retrieve, or "" for every type.
+// - publishedOnly: whether to treat unpublished products, on either end of a relation, as missing.
+func (r *PostgresRelationRepository) GetRelations(ctx context.Context, productID int, relationType string, publishedOnly bool) ([]*models.ProductRelation, error) {
+	ctx = database.WithOperation(ctx, "PostgresRelationRepository.GetRelations")
+	tx, err := beginTenant(ctx, r.dbConnection)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var exists bool
+	err = tx.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM products WHERE id = $1 AND (NOT $2 OR status = $3))",
+		productID, publishedOnly, models.ProductStatusPublished).Scan(&exists)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("product with ID %d: %w", productID, ErrNotFound)
+	}
+	return getRelations(ctx, tx, productID, relationType, publishedOnly)
+}
+
+func getRelations(ctx context.Context, q database.DBConnection, productID int, relationType string, publishedOnly bool) ([]*models.ProductRelation, error) {
+	rows, err := q.Query(ctx, `
+		SELECT pr.type, pr.related_id, p.name, p.slug, pr.position
+		FROM product_relations pr JOIN products p ON p.id = pr.related_id
+		WHERE pr.product_id = $1 AND ($2::text = '' OR pr.type = $2) AND (NOT $3 OR p.status = $4)
+		ORDER BY pr.type, pr.position`, productID, relationType, publishedOnly, models.ProductStatusPublished)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	relations := []*models.ProductRelation{}
+	for rows.Next() {
+		var relation models.ProductRelation
+		if err := rows.Scan(&relation.Type, &relation.ProductID, &relation.Name, &relation.Slug, &relation.Position); err != nil {
+			return nil, err
+		}
+		relations = append(relations, &relation)
+	}
+	return relations, rows.Err()
+}
@@ -0,0 +1,319 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/mariosker/products_rest_api/internal/database"
+	"github.com/mariosker/products_rest_api/internal/models"
+)
+
+type ReservationRepository interface {
+	CreateReservation(ctx context.Context, items []models.ReservationItem, ttl time.Duration) (*models.Reservation, error)
+	GetReservationByID(ctx context.Context, id int) (*models.Reservation, error)
+	CommitReservation(ctx context.Context, id int) (*models.Reservation, error)
+	ReleaseReservation(ctx context.Context, id int) (*models.Reservation, error)
+	ReleaseExpiredReservations(ctx context.Context) (int64, error)
+}
+
+type PostgresReservationRepository struct {
+	dbConnection database.DBConnection
+}
+
+func NewPostgresReservationRepository(dbConnection database.DBConnection) *PostgresReservationRepository {
+	return &PostgresReservationRepository{dbConnection: dbConnection}
+}
+
+// CreateReservation atomically holds the requested quantities of every product for the given TTL.
+// Either all items are reserved or none are. Bundles hold no stock of their own, so they are
+// reserved as their components.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+// - items: the products and quantities to reserve. Duplicate products are merged.
+// - ttl: how long the reservation is held before the sweeper releases it.
+func (r *PostgresReservationRepository) CreateReservation(ctx context.Context, items []models.ReservationItem, ttl time.Duration) (*models.Reservation, error) {
+	ctx = database.WithOperation(ctx, "PostgresReservationRepository.CreateReservation")
+	tx, err := beginTenant(ctx, r.dbConnection)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	items, err = expandBundleItems(ctx, tx, mergeReservationItems(items))
+	if err != nil {
+		return nil, err
+	}
+	productIDs := make([]int, len(items))
+	for i, item := range items {
+		productIDs[i] = item.ProductID
+	}
+
+	// Lock the product rows in a stable order so that concurrent reservations
+	// for overlapping products serialize instead of deadlocking. Availability is
+	// read in a separate statement so it sees holds committed while we waited.
+	if _, err := tx.Exec(ctx, "SELECT id FROM products WHERE id = ANY($1) ORDER BY id FOR UPDATE", productIDs); err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Query(ctx, "SELECT p.id, "+availableStockColumn+" FROM products p WHERE p.id = ANY($1)", productIDs)
+	if err != nil {
+		return nil, err
+	}
+	available := make(map[int]int, len(items))
+	for rows.Next() {
+		var id, qty int
+		if err := rows.Scan(&id, &qty); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		available[id] = qty
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, item := range items {
+		qty, ok := available[item.ProductID]
+		if !ok {
+			return nil, fmt.Errorf("product with ID %d: %w", item.ProductID, ErrNotFound)
+		}
+		if qty < item.Quantity {
+			return nil, fmt.Errorf("product with ID %d has %d available: %w", item.ProductID, qty, ErrInsufficientStock)
+		}
+	}
+
+	reservation := models.Reservation{Status: models.ReservationStatusActive, Items: items}
+	err = tx.QueryRow(ctx,
+		"INSERT INTO reservations (status, expires_at) VALUES ($1, now() + make_interval(secs => $2)) RETURNING id, expires_at",
+		reservation.Status, ttl.Seconds(),
+	).Scan(&reservation.ID, &reservation.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range items {
+		_, err := tx.Exec(ctx,
+			"INSERT INTO reservation_items (reservation_id, product_id, quantity) VALUES ($1, $2, $3)",
+			reservation.ID, item.ProductID, item.Quantity)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+// GetReservationByID retrieves a reservation and its items by ID.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+// - id: the ID of the reservation to be retrieved.
+func (r *PostgresReservationRepository) GetReservationByID(ctx context.Context, id int) (*models.Reservation, error) {
+	ctx = database.WithOperation(ctx, "PostgresReservationRepository.GetReservationByID")
+	var reservation *models.Reservation
+	err := inTenant(ctx, r.dbConnection, func(tx pgx.Tx) error {
+		var err error
+		reservation, err = getReservation(ctx, tx, id, false)
+		return err
+	})
+	return reservation, err
+}
+
+// CommitReservation finalizes an active reservation by deducting its quantities from product stock.
+// The reservation stays active if a product no longer has the stock it holds.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+// - id: the ID of the reservation to be committed.
+func (r *PostgresReservationRepository) CommitReservation(ctx context.Context, id int) (*models.Reservation, error) {
+	ctx = database.WithOperation(ctx, "PostgresReservationRepository.CommitReservation")
+	tx, err := beginTenant(ctx, r.dbConnection)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	reservation, err := getReservation(ctx, tx, id, true)
+	if err != nil {
+		return nil, err
+	}
+	if reservation.Status != models.ReservationStatusActive || !reservation.ExpiresAt.After(time.Now()) {
+		return nil, ErrReservationNotActive
+	}
+
+	// The stock of a product may have been lowered below the held quantity since it was reserved.
+	result, err := tx.Exec(ctx, `
+		UPDATE products p SET stock = p.stock - ri.quantity
+		FROM reservation_items ri
+		WHERE ri.reservation_id = $1 AND ri.product_id = p.id AND p.stock >= ri.quantity`, id)
+	if err != nil {
+		return nil, err
+	}
+	if result.RowsAffected() < int64(len(reservation.Items)) {
+		return nil, fmt.Errorf("reservation with ID %d holds more than the stock of its products: %w", id, ErrInsufficientStock)
+	}
+
+	reservation.Status = models.ReservationStatusCommitted
+	if _, err := tx.Exec(ctx, "UPDATE reservations SET status = $1 WHERE id = $2", reservation.Status, id); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return reservation, nil
+}
+
+// ReleaseReservation drops an active reservation, returning its quantities to the available stock.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+// - id: the ID of the reservation to be released.
+func (r *PostgresReservationRepository) ReleaseReservation(ctx context.Context, id int) (*models.Reservation, error) {
+	ctx = database.WithOperation(ctx, "PostgresReservationRepository.ReleaseReservation")
+	tx, err := beginTenant(ctx, r.dbConnection)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	reservation, err := getReservation(ctx, tx, id, true)
+	if err != nil {
+		return nil, err
+	}
+	if reservation.Status != models.ReservationStatusActive {
+		return nil, ErrReservationNotActive
+	}
+
+	reservation.Status = models.ReservationStatusReleased
+	if _, err := tx.Exec(ctx, "UPDATE reservations SET status = $1 WHERE id = $2", reservation.Status, id); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return reservation, nil
+}
+
+// ReleaseExpiredReservations marks every active reservation past its expiry as expired
+// and returns how many were released.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+func (r *PostgresReservationRepository) ReleaseExpiredReservations(ctx context.Context) (int64, error) {
+	ctx = database.WithOperation(ctx, "PostgresReservationRepository.ReleaseExpiredReservations")
+	result, err := r.dbConnection.Exec(ctx,
+		"UPDATE reservations SET status = $1 WHERE status = $2 AND expires_at <= now()",
+		models.ReservationStatusExpired, models.ReservationStatusActive)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+// getReservation loads a reservation with its items, optionally locking the reservation row.
+func getReservation(ctx context.Context, q database.DBConnection, id int, forUpdate bool) (*models.Reservation, error) {
+	query := "SELECT id, status, expires_at FROM reservations WHERE id = $1"
+	if forUpdate {
+		query += " FOR UPDATE"
+	}
+
+	var reservation models.Reservation
+	err := q.QueryRow(ctx, query, id).Scan(&reservation.ID, &reservation.Status, &reservation.ExpiresAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("reservation with ID %d: %w", id, ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := q.Query(ctx, "SELECT product_id, quantity FROM reservation_items WHERE reservation_id = $1 ORDER BY product_id", id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item models.ReservationItem
+		if err := rows.Scan(&item.ProductID, &item.Quantity); err != nil {
+			return nil, err
+		}
+		reservation.Items = append(reservation.Items, item)
+	}
+	return &reservation, rows.Err()
+}
+
+// expandBundleItems replaces the bundles among items with their components, multiplying the
+// component quantities by the number of bundles, and merges the result.
+func expandBundleItems(ctx context.Context, q database.DBConnection, items []models.ReservationItem) ([]models.ReservationItem, error) {
+	productIDs := make([]int, len(items))
+	for i, item := range items {
+		productIDs[i] = item.ProductID
+	}
+
+	rows, err := q.Query(ctx, `
+		SELECT p.id, bc.component_id, bc.quantity
+		FROM products p LEFT JOIN bundle_components bc ON bc.bundle_id = p.id
+		WHERE p.id = ANY($1) AND p.type = $2`, productIDs, models.ProductTypeBundle)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	components := make(map[int][]models.ReservationItem)
+	for rows.Next() {
+		var bundleID int
+		var componentID, quantity *int
+		if err := rows.Scan(&bundleID, &componentID, &quantity); err != nil {
+			return nil, err
+		}
+		// A bundle without components is still recorded, with no parts.
+		parts := components[bundleID]
+		if componentID != nil {
+			parts = append(parts, models.ReservationItem{ProductID: *componentID, Quantity: *quantity})
+		}
+		components[bundleID] = parts
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(components) == 0 {
+		return items, nil
+	}
+
+	expanded := make([]models.ReservationItem, 0, len(items))
+	for _, item := range items {
+		parts, ok := components[item.ProductID]
+		if !ok {
+			expanded = append(expanded, item)
+			continue
+		}
+		if len(parts) == 0 {
+			return nil, fmt.Errorf("bundle with ID %d has no components: %w", item.ProductID, ErrInsufficientStock)
+		}
+		for _, part := range parts {
+			expanded = append(expanded, models.ReservationItem{ProductID: part.ProductID, Quantity: part.Quantity * item.Quantity})
+		}
+	}
+	return mergeReservationItems(expanded), nil
+}
+
+// mergeReservationItems sums the quantities of duplicate products and sorts the result by product ID.
+func mergeReservationItems(items []models.ReservationItem) []models.ReservationItem {
+	quantities := make(map[int]int, len(items))
+	for _, item := range items {
+		quantities[item.ProductID] += item.Quantity
+	}
+
+	merged := make([]models.ReservationItem, 0, len(quantities))
+	for productID, quantity := range quantities {
+		merged = append(merged, models.ReservationItem{ProductID: productID, Quantity: quantity})
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].ProductID < merged[j].ProductID })
+	return merged
+}
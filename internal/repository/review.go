@@ -0,0 +1,220 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/mariosker/products_rest_api/internal/database"
+	"github.com/mariosker/products_rest_api/internal/models"
+)
+
+type ReviewRepository interface {
+	CreateReview(ctx context.Context, productID int, payload *models.ReviewPayload, status string) (*models.Review, error)
+	GetReviewByID(ctx context.Context, productID, reviewID int) (*models.Review, error)
+	GetReviewsByProductID(ctx context.Context, productID int, statuses []string, limit, offset int) ([]*models.Review, error)
+	ModerateReview(ctx context.Context, productID, reviewID int, status string) (*models.Review, error)
+	DeleteReview(ctx context.Context, productID, reviewID int) error
+}
+
+type PostgresReviewRepository struct {
+	dbConnection database.DBConnection
+}
+
+func NewPostgresReviewRepository(dbConnection database.DBConnection) *PostgresReviewRepository {
+	return &PostgresReviewRepository{dbConnection: dbConnection}
+}
+
+// reviewColumns selects the columns scanned by scanReview.
+const reviewColumns = "id, product_id, rating, COALESCE(text, ''), author, status, created_at"
+
+// CreateReview adds a review to a product. An approved review is counted towards the
+// product's rating in the same transaction.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+// - productID: the ID of the reviewed product.
+// - payload: the rating, text and author of the review.
+// - status: the moderation status of the new review.
+func (r *PostgresReviewRepository) CreateReview(ctx context.Context, productID int, payload *models.ReviewPayload, status string) (*models.Review, error) {
+	ctx = database.WithOperation(ctx, "PostgresReviewRepository.CreateReview")
+	tx, err := beginTenant(ctx, r.dbConnection)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO reviews (product_id, rating, text, author, status)
+		VALUES ($1, $2, NULLIF($3, ''), $4, $5)
+		RETURNING ` + reviewColumns
+	review, err := scanReview(tx.QueryRow(ctx, query, productID, payload.Rating, payload.Text, payload.Author, status))
+	if isForeignKeyViolation(err) || isRowSecurityViolation(err) {
+		return nil, fmt.Errorf("product with ID %d: %w", productID, ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if status == models.ReviewStatusApproved {
+		if err := adjustRating(ctx, tx, productID, review.Rating, 1); err != nil {
+			return nil, err
+		}
+	}
+	return review, tx.Commit(ctx)
+}
+
+// GetReviewByID retrieves a single review of a product.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+// - productID: the ID of the reviewed product.
+// - reviewID: the ID of the review to be retrieved.
+func (r *PostgresReviewRepository) GetReviewByID(ctx context.Context, productID, reviewID int) (*models.Review, error) {
+	ctx = database.WithOperation(ctx, "PostgresReviewRepository.GetReviewByID")
+	query := "SELECT " + reviewColumns + " FROM reviews WHERE product_id = $1 AND id = $2"
+	var review *models.Review
+	err := inTenant(ctx, r.dbConnection, func(tx pgx.Tx) error {
+		var err error
+		review, err = scanReview(tx.QueryRow(ctx, query, productID, reviewID))
+		return err
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("review with ID %d: %w", reviewID, ErrNotFound)
+	}
+	return review, err
+}
+
+// GetReviewsByProductID retrieves the reviews of a product, newest first, with pagination support.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+// - productID: the ID of the reviewed product.
+// - statuses: the moderation statuses of the reviews to return; empty means any status.
+// - limit: the maximum number of reviews to return.
+// - offset: the number of reviews to skip before starting to return reviews.
+func (r *PostgresReviewRepository) GetReviewsByProductID(ctx context.Context, productID int, statuses []string, limit, offset int) ([]*models.Review, error) {
+	ctx = database.WithOperation(ctx, "PostgresReviewRepository.GetReviewsByProductID")
+	tx, err := beginTenant(ctx, r.dbConnection)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var exists bool
+	if err := tx.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM products WHERE id = $1)", productID).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("product with ID %d: %w", productID, ErrNotFound)
+	}
+
+	query := `
+		SELECT ` + reviewColumns + ` FROM reviews
+		WHERE product_id = $1 AND (cardinality($2::text[]) = 0 OR status = ANY($2::text[]))
+		ORDER BY created_at DESC, id DESC LIMIT $3 OFFSET $4`
+	if statuses == nil {
+		statuses = []string{}
+	}
+	rows, err := tx.Query(ctx, query, productID, statuses, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reviews := []*models.Review{}
+	for rows.Next() {
+		review, err := scanReview(rows)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, review)
+	}
+	return reviews, rows.Err()
+}
+
+// ModerateReview changes the moderation status of a review and updates the product's rating
+// in the same transaction when the review is approved or stops being approved.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+// - productID: the ID of the reviewed product.
+// - reviewID: the ID of the review to be moderated.
+// - status: the new moderation status.
+func (r *PostgresReviewRepository) ModerateReview(ctx context.Context, productID, reviewID int, status string) (*models.Review, error) {
+	ctx = database.WithOperation(ctx, "PostgresReviewRepository.ModerateReview")
+	tx, err := beginTenant(ctx, r.dbConnection)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var previous string
+	err = tx.QueryRow(ctx, "SELECT status FROM reviews WHERE product_id = $1 AND id = $2 FOR UPDATE", productID, reviewID).Scan(&previous)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("review with ID %d: %w", reviewID, ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	query := "UPDATE reviews SET status = $3 WHERE product_id = $1 AND id = $2 RETURNING " + reviewColumns
+	review, err := scanReview(tx.QueryRow(ctx, query, productID, reviewID, status))
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case previous != models.ReviewStatusApproved && status == models.ReviewStatusApproved:
+		err = adjustRating(ctx, tx, productID, review.Rating, 1)
+	case previous == models.ReviewStatusApproved && status != models.ReviewStatusApproved:
+		err = adjustRating(ctx, tx, productID, -review.Rating, -1)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return review, tx.Commit(ctx)
+}
+
+// DeleteReview deletes a review of a product, removing it from the product's rating in the
+// same transaction if it was approved.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+// - productID: the ID of the reviewed product.
+// - reviewID: the ID of the review to be deleted.
+func (r *PostgresReviewRepository) DeleteReview(ctx context.Context, productID, reviewID int) error {
+	ctx = database.WithOperation(ctx, "PostgresReviewRepository.DeleteReview")
+	tx, err := beginTenant(ctx, r.dbConnection)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var rating int
+	var status string
+	err = tx.QueryRow(ctx, "DELETE FROM reviews WHERE product_id = $1 AND id = $2 RETURNING rating, status", productID, reviewID).Scan(&rating, &status)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("review with ID %d: %w", reviewID, ErrNotFound)
+	}
+	if err != nil {
+		return err
+	}
+	if status == models.ReviewStatusApproved {
+		if err := adjustRating(ctx, tx, productID, -rating, -1); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// adjustRating adds ratingDelta to the summed rating and countDelta to the review count of a product.
+func adjustRating(ctx context.Context, q database.DBConnection, productID, ratingDelta, countDelta int) error {
+	_, err := q.Exec(ctx, "UPDATE products SET rating_sum = rating_sum + $2, review_count = review_count + $3 WHERE id = $1",
+		productID, ratingDelta, countDelta)
+	return err
+}
+
+func scanReview(row pgx.Row) (*models.Review, error) {
+	var review models.Review
+	err := row.Scan(&review.ID, &review.ProductID, &review.Rating, &review.Text, &review.Author, &review.Status, &review.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/mariosker/products_rest_api/internal/database"
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/mariosker/products_rest_api/internal/tax"
+)
+
+type TaxRepository interface {
+	PutTaxRate(ctx context.Context, rate *models.TaxRate) error
+	GetTaxRates(ctx context.Context) ([]*models.TaxRate, error)
+	GetTaxRatesForRegion(ctx context.Context, country, region string) ([]*models.TaxRate, error)
+	DeleteTaxRate(ctx context.Context, country, region, taxClass string) error
+}
+
+type PostgresTaxRepository struct {
+	dbConnection database.DBConnection
+}
+
+func NewPostgresTaxRepository(dbConnection database.DBConnection) *PostgresTaxRepository {
+	return &PostgresTaxRepository{dbConnection: dbConnection}
+}
+
+// taxRateColumns selects the columns scanned by scanTaxRates.
+const taxRateColumns = "country, region, tax_class, rate, prices_include_tax"
+
+// PutTaxRate creates or replaces the rate of a tax class in a country or region.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+// - rate: the tax rate to be stored.
+func (r *PostgresTaxRepository) PutTaxRate(ctx context.Context, rate *models.TaxRate) error {
+	ctx = database.WithOperation(ctx, "PostgresTaxRepository.PutTaxRate")
+	return inTenant(ctx, r.dbConnection, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO tax_rates (country, region, tax_class, rate, prices_include_tax)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (tenant_id, country, region, tax_class) DO UPDATE SET
+				rate = EXCLUDED.rate, prices_include_tax = EXCLUDED.prices_include_tax`,
+			rate.Country, rate.Region, rate.TaxClass, rate.Rate, rate.PricesIncludeTax)
+		return err
+	})
+}
+
+// GetTaxRates retrieves every tax rate of the tenant in ctx ordered by country, region and tax class.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+func (r *PostgresTaxRepository) GetTaxRates(ctx context.Context) ([]*models.TaxRate, error) {
+	ctx = database.WithOperation(ctx, "PostgresTaxRepository.GetTaxRates")
+	tx, err := beginTenant(ctx, r.dbConnection)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, "SELECT "+taxRateColumns+" FROM tax_rates ORDER BY country, region, tax_class")
+	if err != nil {
+		return nil, err
+	}
+	return scanTaxRates(rows)
+}
+
+// GetTaxRatesForRegion retrieves the rate of every tax class that applies in a region.
+// A rate set for the region takes precedence over the rate set for its whole country.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+// - country: the ISO 3166-1 country code.
+// - region: the ISO 3166-2 subdivision, or "" for the whole country.
+func (r *PostgresTaxRepository) GetTaxRatesForRegion(ctx context.Context, country, region string) ([]*models.TaxRate, error) {
+	ctx = database.WithOperation(ctx, "PostgresTaxRepository.GetTaxRatesForRegion")
+	tx, err := beginTenant(ctx, r.dbConnection)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `
+		SELECT DISTINCT ON (tax_class) `+taxRateColumns+`
+		FROM tax_rates WHERE country = $1 AND region IN ($2, '')
+		ORDER BY tax_class, region DESC`, country, region)
+	if err != nil {
+		return nil, err
+	}
+	return scanTaxRates(rows)
+}
+
+// DeleteTaxRate deletes the rate of a tax class in a country or region.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+// - country: the ISO 3166-1 country code.
+// - region: the ISO 3166-2 subdivision, or "" for the whole country.
+// - taxClass: the tax class of the rate to be deleted.
+func (r *PostgresTaxRepository) DeleteTaxRate(ctx context.Context, country, region, taxClass string) error {
+	ctx = database.WithOperation(ctx, "PostgresTaxRepository.DeleteTaxRate")
+	return inTenant(ctx, r.dbConnection, func(tx pgx.Tx) error {
+		result, err := tx.Exec(ctx,
+			"DELETE FROM tax_rates WHERE country = $1 AND region = $2 AND tax_class = $3", country, region, taxClass)
+		if err != nil {
+			return err
+		}
+		if result.RowsAffected() == 0 {
+			return fmt.Errorf("tax rate for %s in %s: %w", taxClass, tax.RegionCode(country, region), ErrNotFound)
+		}
+		return nil
+	})
+}
+
+func scanTaxRates(rows pgx.Rows) ([]*models.TaxRate, error) {
+	defer rows.Close()
+
+	rates := []*models.TaxRate{}
+	for rows.Next() {
+		var rate models.TaxRate
+		if err := rows.Scan(&rate.Country, &rate.Region, &rate.TaxClass, &rate.Rate, &rate.PricesIncludeTax); err != nil {
+			return nil, err
+		}
+		rates = append(rates, &rate)
+	}
+	return rates, rows.Err()
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/mariosker/products_rest_api/internal/database"
+	"github.com/mariosker/products_rest_api/internal/tenant"
+)
+
+// tenantRole is the role tenant-scoped transactions run as. It owns none of the tables, so their
+// row-level security policies apply to it.
+const tenantRole = "products_tenant"
+
+// beginTenant starts a transaction that, when ctx carries a tenant, only sees and changes the rows
+// of that tenant: its products and everything hanging off them, its reservations and its discounts.
+// Postgres enforces this with row-level security, so no query needs to filter by tenant itself.
+// Without a tenant, as for background workers, the transaction sees the rows of every tenant.
+func beginTenant(ctx context.Context, db database.DBConnection) (pgx.Tx, error) {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if tenantID, ok := tenant.FromContext(ctx); ok {
+		_, err = tx.Exec(ctx, "SELECT set_config('app.tenant_id', $1, true)", tenantID)
+		if err == nil {
+			_, err = tx.Exec(ctx, "SET LOCAL ROLE "+tenantRole)
+		}
+		if err != nil {
+			tx.Rollback(ctx)
+			return nil, err
+		}
+	}
+	return tx, nil
+}
+
+// inTenant runs fn in a transaction started by beginTenant and commits it if fn succeeds.
+func inTenant(ctx context.Context, db database.DBConnection, fn func(tx pgx.Tx) error) error {
+	tx, err := beginTenant(ctx, db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/mariosker/products_rest_api/internal/database"
+	"github.com/mariosker/products_rest_api/internal/models"
+)
+
+type TranslationRepository interface {
+	PutTranslation(ctx context.Context, translation *models.ProductTranslation) error
+	GetTranslationsByProductID(ctx context.Context, productID int) ([]*models.ProductTranslation, error)
+	GetBestTranslations(ctx context.Context, productIDs []int, locales []string) (map[int]*models.ProductTranslation, error)
+	DeleteTranslation(ctx context.Context, productID int, locale string) error
+}
+
+type PostgresTranslationRepository struct {
+	dbConnection database.DBConnection
+}
+
+func NewPostgresTranslationRepository(dbConnection database.DBConnection) *PostgresTranslationRepository {
+	return &PostgresTranslationRepository{dbConnection: dbConnection}
+}
+
+// PutTranslation creates or replaces the translation of a product in a locale.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+// - translation: the translation to be stored.
+func (r *PostgresTranslationRepository) PutTranslation(ctx context.Context, translation *models.ProductTranslation) error {
+	ctx = database.WithOperation(ctx, "PostgresTranslationRepository.PutTranslation")
+	err := inTenant(ctx, r.dbConnection, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO product_translations (product_id, locale, name, description)
+			VALUES ($1, $2, $3, NULLIF($4, ''))
+			ON CONFLICT (product_id, locale) DO UPDATE SET name = EXCLUDED.name, description = EXCLUDED.description`,
+			translation.ProductID, translation.Locale, translation.Name, translation.Description)
+		return err
+	})
+	if isForeignKeyViolation(err) || isRowSecurityViolation(err) {
+		return fmt.Errorf("product with ID %d: %w", translation.ProductID, ErrNotFound)
+	}
+	return err
+}
+
+// GetTranslationsByProductID retrieves every translation of a product ordered by locale.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+// - productID: the ID of the product whose translations are retrieved.
+func (r *PostgresTranslationRepository) GetTranslationsByProductID(ctx context.Context, productID int) ([]*models.ProductTranslation, error) {
+	ctx = database.WithOperation(ctx, "PostgresTranslationRepository.GetTranslationsByProductID")
+	tx, err := beginTenant(ctx, r.dbConnection)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var exists bool
+	if err := tx.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM products WHERE id = $1)", productID).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("product with ID %d: %w", productID, ErrNotFound)
+	}
+
+	rows, err := tx.Query(ctx, `
+		SELECT product_id, locale, name, COALESCE(description, '')
+		FROM product_translations WHERE product_id = $1 ORDER BY locale`, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	translations := []*models.ProductTranslation{}
+	for rows.Next() {
+		var t models.ProductTranslation
+		if err := rows.Scan(&t.ProductID, &t.Locale, &t.Name, &t.Description); err != nil {
+			return nil, err
+		}
+		translations = append(translations, &t)
+	}
+	return translations, rows.Err()
+}
+
+// GetBestTranslations retrieves, for each of the given products, its translation in the
+// earliest of locales it has been translated into. Products without any of the locales are omitted.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+// - productIDs: the IDs of the products to translate.
+// - locales: the acceptable locales, most preferred first.
+func (r *PostgresTranslationRepository) GetBestTranslations(ctx context.Context, productIDs []int, locales []string) (map[int]*models.ProductTranslation, error) {
+	ctx = database.WithOperation(ctx, "PostgresTranslationRepository.GetBestTranslations")
+	translations := map[int]*models.ProductTranslation{}
+	if len(productIDs) == 0 || len(locales) == 0 {
+		return translations, nil
+	}
+
+	tx, err := beginTenant(ctx, r.dbConnection)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `
+		SELECT DISTINCT ON (product_id) product_id, locale, name, COALESCE(description, '')
+		FROM product_translations
+		WHERE product_id = ANY($1) AND locale = ANY($2::text[])
+		ORDER BY product_id, array_position($2::text[], locale::text)`, productIDs, locales)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var t models.ProductTranslation
+		if err := rows.Scan(&t.ProductID, &t.Locale, &t.Name, &t.Description); err != nil {
+			return nil, err
+		}
+		translations[t.ProductID] = &t
+	}
+	return translations, rows.Err()
+}
+
+// DeleteTranslation deletes the translation of a product in a locale.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+// - productID: the ID of the product the translation belongs to.
+// - locale: the locale of the translation.
+func (r *PostgresTranslationRepository) DeleteTranslation(ctx context.Context, productID int, locale string) error {
+	ctx = database.WithOperation(ctx, "PostgresTranslationRepository.DeleteTranslation")
+	return inTenant(ctx, r.dbConnection, func(tx pgx.Tx) error {
+		result, err := tx.Exec(ctx, "DELETE FROM product_translations WHERE product_id = $1 AND locale = $2", productID, locale)
+		if err != nil {
+			return err
+		}
+		if result.RowsAffected() == 0 {
+			return fmt.Errorf("translation %q of product with ID %d: %w", locale, productID, ErrNotFound)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/mariosker/products_rest_api/internal/database"
+	"github.com/mariosker/products_rest_api/internal/models"
+)
+
+type VariantRepository interface {
+	CreateVariant(ctx context.Context, productID int, payload *models.ProductVariantPayload) (*models.ProductVariant, error)
+	GetVariantByID(ctx context.Context, productID, variantID int) (*models.ProductVariant, error)
+	GetVariantsByProductID(ctx context.Context, productID int) ([]*models.ProductVariant, error)
+	UpdateVariant(ctx context.Context, productID, variantID int, payload *models.ProductVariantPayload) (*models.ProductVariant, error)
+	DeleteVariant(ctx context.Context, productID, variantID int) error
+}
+
+type PostgresVariantRepository struct {
+	dbConnection database.DBConnection
+}
+
+func NewPostgresVariantRepository(dbConnection database.DBConnection) *PostgresVariantRepository {
+	return &PostgresVariantRepository{dbConnection: dbConnection}
+}
+
+// variantColumns selects a variant v joined with its product p, resolving the effective price.
+// Variants without a price override follow the product's scheduled price.
+const variantColumns = "v.id, v.product_id, v.sku, v.options, v.price, COALESCE(v.price, " + effectivePriceColumn + "), v.stock"
+
+// CreateVariant inserts a new variant for the given product.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+// - productID: the ID of the product the variant belongs to.
+// - payload: the variant data to be created.
+func (r *PostgresVariantRepository) CreateVariant(ctx context.Context, productID int, payload *models.ProductVariantPayload) (*models.ProductVariant, error) {
+	ctx = database.WithOperation(ctx, "PostgresVariantRepository.CreateVariant")
+	tx, err := beginTenant(ctx, r.dbConnection)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := checkOptionAxes(ctx, tx, productID, 0, payload.Options); err != nil {
+		return nil, err
+	}
+
+	query := `
+		WITH p AS (SELECT id, price FROM products WHERE id = $1),
+		v AS (
+			INSERT INTO product_variants (product_id, sku, options, price, stock)
+			SELECT p.id, $2, $3, $4, $5 FROM p
+			RETURNING *
+		)
+		SELECT ` + variantColumns + ` FROM v JOIN p ON p.id = v.product_id`
+	variant, err := scanVariant(tx.QueryRow(ctx, query, productID, payload.SKU, payload.Options, payload.Price, payload.Stock))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("product with ID %d: %w", productID, ErrNotFound)
+	}
+	if isUniqueViolation(err) {
+		return nil, fmt.Errorf("variant with SKU %q or the same options already exists: %w", payload.SKU, ErrConflict)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return variant, tx.Commit(ctx)
+}
+
+// GetVariantByID retrieves a single variant of a product.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+// - productID: the ID of the product the variant belongs to.
+// - variantID: the ID of the variant to be retrieved.
+func (r *PostgresVariantRepository) GetVariantByID(ctx context.Context, productID, variantID int) (*models.ProductVariant, error) {
+	ctx = database.WithOperation(ctx, "PostgresVariantRepository.GetVariantByID")
+	query := "SELECT " + variantColumns + " FROM product_variants v JOIN products p ON p.id = v.product_id WHERE v.product_id = $1 AND v.id = $2"
+	var variant *models.ProductVariant
+	err := inTenant(ctx, r.dbConnection, func(tx pgx.Tx) error {
+		var err error
+		variant, err = scanVariant(tx.QueryRow(ctx, query, productID, variantID))
+		return err
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("variant with ID %d: %w", variantID, ErrNotFound)
+	}
+	return variant, err
+}
+
+// GetVariantsByProductID retrieves every variant of a product ordered by ID.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+// - productID: the ID of the product whose variants are retrieved.
+func (r *PostgresVariantRepository) GetVariantsByProductID(ctx context.Context, productID int) ([]*models.ProductVariant, error) {
+	ctx = database.WithOperation(ctx, "PostgresVariantRepository.GetVariantsByProductID")
+	tx, err := beginTenant(ctx, r.dbConnection)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var exists bool
+	if err := tx.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM products WHERE id = $1)", productID).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("product with ID %d: %w", productID, ErrNotFound)
+	}
+
+	query := "SELECT " + variantColumns + " FROM product_variants v JOIN products p ON p.id = v.product_id WHERE v.product_id = $1 ORDER BY v.id"
+	rows, err := tx.Query(ctx, query, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	variants := []*models.ProductVariant{}
+	for rows.Next() {
+		variant, err := scanVariant(rows)
+		if err != nil {
+			return nil, err
+		}
+		variants = append(variants, variant)
+	}
+	return variants, rows.Err()
+}
+
+// UpdateVariant replaces the SKU, options, price override and stock of a variant.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+// - productID: the ID of the product the variant belongs to.
+// - variantID: the ID of the variant to be updated.
+// - payload: the variant data to be updated.
+func (r *PostgresVariantRepository) UpdateVariant(ctx context.Context, productID, variantID int, payload *models.ProductVariantPayload) (*models.ProductVariant, error) {
+	ctx = database.WithOperation(ctx, "PostgresVariantRepository.UpdateVariant")
+	tx, err := beginTenant(ctx, r.dbConnection)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := checkOptionAxes(ctx, tx, productID, variantID, payload.Options); err != nil {
+		return nil, err
+	}
+
+	query := `
+		WITH v AS (
+			UPDATE product_variants SET sku = $3, options = $4, price = $5, stock = $6
+			WHERE product_id = $1 AND id = $2
+			RETURNING *
+		)
+		SELECT ` + variantColumns + ` FROM v JOIN products p ON p.id = v.product_id`
+	variant, err := scanVariant(tx.QueryRow(ctx, query, productID, variantID, payload.SKU, payload.Options, payload.Price, payload.Stock))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("variant with ID %d: %w", variantID, ErrNotFound)
+	}
+	if isUniqueViolation(err) {
+		return nil, fmt.Errorf("variant with SKU %q or the same options already exists: %w", payload.SKU, ErrConflict)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return variant, tx.Commit(ctx)
+}
+
+// DeleteVariant deletes a variant of a product.
+// Parameters:
+// - ctx: context for managing request deadlines and cancellation signals.
+// - productID: the ID of the product the variant belongs to.
+// - variantID: the ID of the variant to be deleted.
+func (r *PostgresVariantRepository) DeleteVariant(ctx context.Context, productID, variantID int) error {
+	ctx = database.WithOperation(ctx, "PostgresVariantRepository.DeleteVariant")
+	return inTenant(ctx, r.dbConnection, func(tx pgx.Tx) error {
+		result, err := tx.Exec(ctx, "DELETE FROM product_variants WHERE product_id = $1 AND id = $2", productID, variantID)
+		if err != nil {
+			return err
+		}
+		if result.RowsAffected() == 0 {
+			return fmt.Errorf("variant with ID %d: %w", variantID, ErrNotFound)
+		}
+		return nil
+	})
+}
+
+// checkOptionAxes ensures options use the same keys as the product's other variants,
+// so that every variant of a product is described along the same axes.
+func checkOptionAxes(ctx context.Context, q database.DBConnection, productID, variantID int, options map[string]string) error {
+	var existing map[string]string
+	err := q.QueryRow(ctx,
+		"SELECT options FROM product_variants WHERE product_id = $1 AND id <> $2 LIMIT 1",
+		productID, variantID).Scan(&existing)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(existing) != len(options) {
+		return ErrVariantOptionsMismatch
+	}
+	for axis := range existing {
+		if _, ok := options[axis]; !ok {
+			return ErrVariantOptionsMismatch
+		}
+	}
+	return nil
+}
+
+func scanVariant(row pgx.Row) (*models.ProductVariant, error) {
+	var variant models.ProductVariant
+	err := row.Scan(&variant.ID, &variant.ProductID, &variant.SKU, &variant.Options, &variant.Price, &variant.EffectivePrice, &variant.Stock)
+	if err != nil {
+		return nil, err
+	}
+	return &variant, nil
+}
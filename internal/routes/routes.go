@@ -8,7 +8,99 @@ import (
 func SetupRoutes(r *gin.Engine, productHandler *handlers.ProductHandler) {
 	r.POST("/products", productHandler.CreateProduct)
 	r.GET("/products/:id", productHandler.GetProduct)
+	r.GET("/products/by-sku/:sku", productHandler.GetProductBySKU)
+	r.GET("/products/by-slug/:slug", productHandler.GetProductBySlug)
+	r.GET("/products/by-gtin/:code", productHandler.GetProductByGTIN)
 	r.GET("/products", productHandler.GetProducts)
 	r.PUT("/products/:id", productHandler.UpdateProduct)
 	r.DELETE("/products/:id", productHandler.DeleteProduct)
+	r.POST("/products/:id/publish", productHandler.PublishProduct)
+	r.POST("/products/:id/archive", productHandler.ArchiveProduct)
+	r.POST("/products/:id/unarchive", productHandler.UnarchiveProduct)
+}
+
+func SetupReservationRoutes(r *gin.Engine, reservationHandler *handlers.ReservationHandler) {
+	r.POST("/reservations", reservationHandler.CreateReservation)
+	r.GET("/reservations/:id", reservationHandler.GetReservation)
+	r.POST("/reservations/:id/commit", reservationHandler.CommitReservation)
+	r.POST("/reservations/:id/release", reservationHandler.ReleaseReservation)
+}
+
+func SetupVariantRoutes(r *gin.Engine, variantHandler *handlers.VariantHandler) {
+	r.POST("/products/:id/variants", variantHandler.CreateVariant)
+	r.GET("/products/:id/variants", variantHandler.GetVariants)
+	r.GET("/products/:id/variants/:variantId", variantHandler.GetVariant)
+	r.PUT("/products/:id/variants/:variantId", variantHandler.UpdateVariant)
+	r.DELETE("/products/:id/variants/:variantId", variantHandler.DeleteVariant)
+}
+
+func SetupAttributeRoutes(r *gin.Engine, attributeHandler *handlers.AttributeHandler) {
+	r.GET("/categories/:category/attributes", attributeHandler.GetAttributeDefinitions)
+	r.PUT("/categories/:category/attributes/:name", attributeHandler.PutAttributeDefinition)
+	r.DELETE("/categories/:category/attributes/:name", attributeHandler.DeleteAttributeDefinition)
+}
+
+func SetupImageRoutes(r *gin.Engine, imageHandler *handlers.ImageHandler) {
+	r.POST("/products/:id/images", imageHandler.UploadImage)
+	r.GET("/products/:id/images", imageHandler.GetImages)
+	r.GET("/products/:id/images/:imageId", imageHandler.GetImage)
+	r.DELETE("/products/:id/images/:imageId", imageHandler.DeleteImage)
+}
+
+func SetupTranslationRoutes(r *gin.Engine, translationHandler *handlers.TranslationHandler) {
+	r.GET("/products/:id/translations", translationHandler.GetTranslations)
+	r.PUT("/products/:id/translations/:locale", translationHandler.PutTranslation)
+	r.DELETE("/products/:id/translations/:locale", translationHandler.DeleteTranslation)
+}
+
+func SetupPriceScheduleRoutes(r *gin.Engine, priceScheduleHandler *handlers.PriceScheduleHandler) {
+	r.POST("/products/:id/price-schedules", priceScheduleHandler.CreatePriceSchedule)
+	r.GET("/products/:id/price-schedules", priceScheduleHandler.GetPriceSchedules)
+	r.GET("/products/:id/price-schedules/:scheduleId", priceScheduleHandler.GetPriceSchedule)
+	r.PUT("/products/:id/price-schedules/:scheduleId", priceScheduleHandler.UpdatePriceSchedule)
+	r.DELETE("/products/:id/price-schedules/:scheduleId", priceScheduleHandler.DeletePriceSchedule)
+}
+
+func SetupDiscountRoutes(r *gin.Engine, discountHandler *handlers.DiscountHandler) {
+	r.POST("/discounts", discountHandler.CreateDiscount)
+	r.GET("/discounts", discountHandler.GetDiscounts)
+	r.GET("/discounts/:id", discountHandler.GetDiscount)
+	r.PUT("/discounts/:id", discountHandler.UpdateDiscount)
+	r.DELETE("/discounts/:id", discountHandler.DeleteDiscount)
+}
+
+func SetupPricingRoutes(r *gin.Engine, pricingHandler *handlers.PricingHandler) {
+	r.POST("/pricing/quote", pricingHandler.Quote)
+}
+
+func SetupTaxRoutes(r *gin.Engine, taxHandler *handlers.TaxHandler) {
+	r.GET("/tax-rates", taxHandler.GetTaxRates)
+	r.PUT("/tax-rates/:region/:taxClass", taxHandler.PutTaxRate)
+	r.DELETE("/tax-rates/:region/:taxClass", taxHandler.DeleteTaxRate)
+}
+
+func SetupBundleRoutes(r *gin.Engine, bundleHandler *handlers.BundleHandler) {
+	r.GET("/products/:id/bundle", bundleHandler.GetBundle)
+	r.PUT("/products/:id/bundle", bundleHandler.PutBundle)
+	r.DELETE("/products/:id/bundle", bundleHandler.DeleteBundle)
+}
+
+func SetupRelationRoutes(r *gin.Engine, relationHandler *handlers.RelationHandler) {
+	r.GET("/products/:id/relations", relationHandler.GetRelations)
+	r.PUT("/products/:id/relations", relationHandler.PutRelations)
+}
+
+func SetupReviewRoutes(r *gin.Engine, reviewHandler *handlers.ReviewHandler) {
+	r.POST("/products/:id/reviews", reviewHandler.CreateReview)
+	r.GET("/products/:id/reviews", reviewHandler.GetReviews)
+	r.GET("/products/:id/reviews/:reviewId", reviewHandler.GetReview)
+	r.PUT("/products/:id/reviews/:reviewId/status", reviewHandler.ModerateReview)
+	r.DELETE("/products/:id/reviews/:reviewId", reviewHandler.DeleteReview)
+}
+
+func SetupAPIKeyRoutes(r *gin.Engine, apiKeyHandler *handlers.APIKeyHandler) {
+	r.POST("/admin/api-keys", apiKeyHandler.CreateAPIKey)
+	r.GET("/admin/api-keys", apiKeyHandler.GetAPIKeys)
+	r.POST("/admin/api-keys/:id/rotate", apiKeyHandler.RotateAPIKey)
+	r.DELETE("/admin/api-keys/:id", apiKeyHandler.RevokeAPIKey)
 }
@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeS3 is a minimal in-memory stand-in for an S3-compatible object store,
+// implementing just enough of the API for PutObject, GetObject and RemoveObject.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	types   map[string]string
+}
+
+func newFakeS3() *fakeS3 {
+	return &fakeS3{objects: map[string][]byte{}, types: map[string]string{}}
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		f.objects[r.URL.Path] = body
+		f.types[r.URL.Path] = r.Header.Get("Content-Type")
+		w.Header().Set("ETag", `"etag"`)
+	case http.MethodGet, http.MethodHead:
+		body, ok := f.objects[r.URL.Path]
+		if !ok {
+			w.Header().Set("Content-Type", "application/xml")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, `<Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>`)
+			return
+		}
+		w.Header().Set("Content-Type", f.types[r.URL.Path])
+		w.Header().Set("ETag", `"etag"`)
+		w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+		_, _ = w.Write(body)
+	case http.MethodDelete:
+		delete(f.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestStorage(t *testing.T) {
+	local, err := NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+
+	fake := newFakeS3()
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	s3, err := NewS3Storage(S3Config{
+		Endpoint:  strings.TrimPrefix(server.URL, "http://"),
+		Bucket:    "images",
+		AccessKey: "access",
+		SecretKey: "secret",
+		Region:    "us-east-1",
+	})
+	require.NoError(t, err)
+
+	backends := map[string]Storage{"Local": local, "S3": s3}
+	for name, store := range backends {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			content := "\x89PNG fake image"
+
+			require.NoError(t, store.Put(ctx, "products/1/a.png", strings.NewReader(content), int64(len(content)), "image/png"))
+
+			r, err := store.Get(ctx, "products/1/a.png")
+			require.NoError(t, err)
+			got, err := io.ReadAll(r)
+			require.NoError(t, err)
+			require.NoError(t, r.Close())
+			assert.Equal(t, content, string(got))
+
+			require.NoError(t, store.Delete(ctx, "products/1/a.png"))
+			_, err = store.Get(ctx, "products/1/a.png")
+			assert.True(t, errors.Is(err, ErrNotFound), "got %v", err)
+
+			assert.NoError(t, store.Delete(ctx, "products/1/missing.png"))
+		})
+	}
+
+	t.Run("S3 Content Type", func(t *testing.T) {
+		require.NoError(t, s3.Put(context.Background(), "products/2/b.jpg", strings.NewReader("jpeg"), 4, "image/jpeg"))
+		assert.Equal(t, "image/jpeg", fake.types["/images/products/2/b.jpg"])
+		assert.Equal(t, "jpeg", string(fake.objects["/images/products/2/b.jpg"]))
+	})
+
+	t.Run("Local Rejects Escaping Keys", func(t *testing.T) {
+		err := local.Put(context.Background(), "../outside.png", strings.NewReader("x"), 1, "image/png")
+		assert.Error(t, err)
+		_, err = local.Get(context.Background(), "/etc/passwd")
+		assert.Error(t, err)
+	})
+
+	t.Run("Local Short Write", func(t *testing.T) {
+		err := local.Put(context.Background(), "products/3/c.png", strings.NewReader("abc"), 10, "image/png")
+		assert.Error(t, err)
+		_, err = local.Get(context.Background(), "products/3/c.png")
+		assert.True(t, errors.Is(err, ErrNotFound))
+	})
+}
@@ -0,0 +1,89 @@
+// Package tax computes net, tax and gross amounts from a percentage tax rate.
+package tax
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+)
+
+// RoundingMode selects how amounts are rounded to cents.
+type RoundingMode string
+
+const (
+	// RoundHalfUp rounds halves away from zero, as most receipts do.
+	RoundHalfUp RoundingMode = "half_up"
+	// RoundHalfEven rounds halves to the nearest even cent (banker's rounding).
+	RoundHalfEven RoundingMode = "half_even"
+	// RoundUp rounds away from zero.
+	RoundUp RoundingMode = "up"
+	// RoundDown rounds towards zero.
+	RoundDown RoundingMode = "down"
+)
+
+// ParseRoundingMode returns the rounding mode named s.
+func ParseRoundingMode(s string) (RoundingMode, error) {
+	switch mode := RoundingMode(s); mode {
+	case RoundHalfUp, RoundHalfEven, RoundUp, RoundDown:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown rounding mode %q", s)
+	}
+}
+
+// Round rounds amount to cents using mode.
+func Round(amount float64, mode RoundingMode) float64 {
+	// Drop the binary representation error first so that e.g. 2.675 rounds as a half.
+	cents := math.Round(amount*100*1e6) / 1e6
+	switch mode {
+	case RoundHalfEven:
+		cents = math.RoundToEven(cents)
+	case RoundUp:
+		if cents < 0 {
+			cents = math.Floor(cents)
+		} else {
+			cents = math.Ceil(cents)
+		}
+	case RoundDown:
+		cents = math.Trunc(cents)
+	default:
+		cents = math.Round(cents)
+	}
+	return cents / 100
+}
+
+// Amounts are the net, tax and gross amounts of a price. Gross is always Net plus Tax.
+type Amounts struct {
+	Net   float64
+	Tax   float64
+	Gross float64
+}
+
+// FromNet computes the amounts of a price that excludes tax at rate percent.
+func FromNet(net, rate float64, mode RoundingMode) Amounts {
+	net = Round(net, mode)
+	tax := Round(net*rate/100, mode)
+	return Amounts{Net: net, Tax: tax, Gross: Round(net+tax, mode)}
+}
+
+// regionPattern matches an ISO 3166-1 country code optionally followed by an ISO 3166-2 subdivision.
+var regionPattern = regexp.MustCompile(`^([A-Z]{2})(?:-([A-Z0-9]{1,3}))?$`)
+
+// ParseRegion splits a region code such as "DE" or "US-CA" into its country and subdivision.
+// The code is case-insensitive; the subdivision is "" for a country code.
+func ParseRegion(code string) (country, subdivision string, err error) {
+	match := regionPattern.FindStringSubmatch(strings.ToUpper(code))
+	if match == nil {
+		return "", "", fmt.Errorf("invalid region %q", code)
+	}
+	return match[1], match[2], nil
+}
+
+// RegionCode joins a country and subdivision into a region code such as "DE" or "US-CA".
+func RegionCode(country, subdivision string) string {
+	if subdivision == "" {
+		return country
+	}
+	return country + "-" + subdivision
+}
@@ -0,0 +1,94 @@
+package tax
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		amount float64
+		mode   RoundingMode
+		want   float64
+	}{
+		{2.675, RoundHalfUp, 2.68},
+		{2.665, RoundHalfUp, 2.67},
+		{-2.675, RoundHalfUp, -2.68},
+		{2.675, RoundHalfEven, 2.68},
+		{2.665, RoundHalfEven, 2.66},
+		{2.6651, RoundHalfEven, 2.67},
+		{2.661, RoundUp, 2.67},
+		{2.66, RoundUp, 2.66},
+		{-2.661, RoundUp, -2.67},
+		{2.669, RoundDown, 2.66},
+		{-2.669, RoundDown, -2.66},
+		{0.1 + 0.2, RoundDown, 0.3},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Round(tt.amount, tt.mode), "Round(%v, %s)", tt.amount, tt.mode)
+	}
+}
+
+func TestFromNet(t *testing.T) {
+	tests := []struct {
+		name string
+		net  float64
+		rate float64
+		mode RoundingMode
+		want Amounts
+	}{
+		{name: "Standard Rate", net: 100, rate: 19, mode: RoundHalfUp, want: Amounts{Net: 100, Tax: 19, Gross: 119}},
+		{name: "Zero Rate", net: 9.99, rate: 0, mode: RoundHalfUp, want: Amounts{Net: 9.99, Tax: 0, Gross: 9.99}},
+		{name: "Half Up", net: 12.5, rate: 7.25, mode: RoundHalfUp, want: Amounts{Net: 12.5, Tax: 0.91, Gross: 13.41}},
+		{name: "Half Even", net: 12.5, rate: 7.4, mode: RoundHalfEven, want: Amounts{Net: 12.5, Tax: 0.92, Gross: 13.42}},
+		{name: "Down", net: 12.5, rate: 7.25, mode: RoundDown, want: Amounts{Net: 12.5, Tax: 0.9, Gross: 13.4}},
+		{name: "Up", net: 10.01, rate: 20, mode: RoundUp, want: Amounts{Net: 10.01, Tax: 2.01, Gross: 12.02}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, FromNet(tt.net, tt.rate, tt.mode))
+		})
+	}
+}
+
+func TestParseRegion(t *testing.T) {
+	tests := []struct {
+		code        string
+		country     string
+		subdivision string
+		valid       bool
+	}{
+		{code: "DE", country: "DE", valid: true},
+		{code: "us-ca", country: "US", subdivision: "CA", valid: true},
+		{code: "GB-LND", country: "GB", subdivision: "LND", valid: true},
+		{code: "DEU"},
+		{code: "US-"},
+		{code: "US-CALI"},
+		{code: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			country, subdivision, err := ParseRegion(tt.code)
+			if !tt.valid {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.country, country)
+			assert.Equal(t, tt.subdivision, subdivision)
+		})
+	}
+}
+
+func TestParseRoundingMode(t *testing.T) {
+	mode, err := ParseRoundingMode("half_even")
+	assert.NoError(t, err)
+	assert.Equal(t, RoundHalfEven, mode)
+
+	_, err = ParseRoundingMode("nearest")
+	assert.Error(t, err)
+}
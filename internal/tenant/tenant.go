@@ -0,0 +1,31 @@
+// Package tenant identifies the catalogue a request works on when several brands share one deployment.
+package tenant
+
+import (
+	"context"
+	"regexp"
+)
+
+// Header is the request header clients that are not bound to a tenant choose one with.
+const Header = "X-Tenant-ID"
+
+// idPattern matches valid tenant IDs: lowercase letters, digits, dashes and underscores.
+var idPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+type contextKey struct{}
+
+// ValidID reports whether id can be used as a tenant ID.
+func ValidID(id string) bool {
+	return idPattern.MatchString(id)
+}
+
+// WithID returns a copy of ctx that carries the tenant ID.
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the tenant ID ctx carries, if any.
+func FromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(contextKey{}).(string)
+	return id, ok
+}
@@ -0,0 +1,62 @@
+package thumbnails
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Cache stores generated thumbnails as files named by their key.
+type Cache struct {
+	dir string
+}
+
+// NewCache creates a Cache in dir, creating the directory if needed.
+func NewCache(dir string) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &Cache{dir: dir}, nil
+}
+
+// Open opens the cached thumbnail with the given key, reporting false if it is not cached.
+func (c *Cache) Open(key string) (*os.File, bool, error) {
+	f, err := os.Open(c.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return f, true, nil
+}
+
+// Put stores a thumbnail under key. The file is written under a temporary name and renamed
+// into place, so concurrent requests for the same thumbnail never read a partial file.
+func (c *Cache) Put(key string, data []byte) error {
+	path := c.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".thumb-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// path spreads thumbnails over subdirectories named by the first two characters of their key.
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, key[:2], key)
+}
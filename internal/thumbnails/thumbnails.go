@@ -0,0 +1,127 @@
+// Package thumbnails resizes JPEG and PNG images and caches the results on disk.
+package thumbnails
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+
+	"golang.org/x/image/draw"
+)
+
+// Fit modes
+const (
+	// FitContain scales the image to fit within the requested size, keeping its aspect ratio.
+	FitContain = "contain"
+	// FitCover scales the image to fill the requested size, cropping the overflow around the centre.
+	FitCover = "cover"
+)
+
+// maxSourcePixels bounds the decoded size of source images, so a small but highly
+// compressed upload cannot exhaust memory when it is decoded.
+const maxSourcePixels = 50_000_000
+
+// ErrUnsupportedFormat is returned for images other than JPEG and PNG.
+var ErrUnsupportedFormat = errors.New("thumbnails are only available for JPEG and PNG images")
+
+// Options describe a thumbnail. A zero Width or Height is derived from the aspect ratio of the source.
+type Options struct {
+	Width  int
+	Height int
+	Fit    string
+}
+
+// Supports reports whether thumbnails can be generated for images of contentType.
+func Supports(contentType string) bool {
+	return contentType == "image/jpeg" || contentType == "image/png"
+}
+
+// Key identifies the thumbnail of the source with the given content hash.
+func Key(sourceHash string, opts Options) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s:%dx%d:%s", sourceHash, opts.Width, opts.Height, opts.Fit)))
+	return hex.EncodeToString(sum[:])
+}
+
+// Generate decodes a JPEG or PNG image from r and returns a thumbnail encoded in the same format.
+func Generate(r io.Reader, opts Options) ([]byte, error) {
+	var source bytes.Buffer
+	if _, err := io.Copy(&source, r); err != nil {
+		return nil, err
+	}
+
+	config, format, err := image.DecodeConfig(bytes.NewReader(source.Bytes()))
+	if err != nil {
+		return nil, ErrUnsupportedFormat
+	}
+	if format != "jpeg" && format != "png" {
+		return nil, ErrUnsupportedFormat
+	}
+	if config.Width*config.Height > maxSourcePixels {
+		return nil, fmt.Errorf("source image of %dx%d pixels is too large", config.Width, config.Height)
+	}
+
+	src, _, err := image.Decode(&source)
+	if err != nil {
+		return nil, err
+	}
+	thumb := Resize(src, opts)
+
+	var out bytes.Buffer
+	if format == "jpeg" {
+		err = jpeg.Encode(&out, thumb, &jpeg.Options{Quality: 85})
+	} else {
+		err = png.Encode(&out, thumb)
+	}
+	return out.Bytes(), err
+}
+
+// Resize scales src according to opts.
+func Resize(src image.Image, opts Options) image.Image {
+	bounds := src.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	w, h := opts.Width, opts.Height
+	if w == 0 {
+		w = max(1, srcW*h/srcH)
+	}
+	if h == 0 {
+		h = max(1, srcH*w/srcW)
+	}
+
+	if opts.Fit == FitCover {
+		// Crop the largest centred region of the source with the target aspect ratio.
+		crop := bounds
+		if srcW*h > srcH*w {
+			cropW := srcH * w / h
+			crop.Min.X += (srcW - cropW) / 2
+			crop.Max.X = crop.Min.X + cropW
+		} else {
+			cropH := srcW * h / w
+			crop.Min.Y += (srcH - cropH) / 2
+			crop.Max.Y = crop.Min.Y + cropH
+		}
+		return scale(src, crop, w, h)
+	}
+
+	// Contain: shrink to fit within w x h, never enlarging the source.
+	if srcW <= w && srcH <= h {
+		return src
+	}
+	if srcW*h > srcH*w {
+		h = max(1, srcH*w/srcW)
+	} else {
+		w = max(1, srcW*h/srcH)
+	}
+	return scale(src, bounds, w, h)
+}
+
+func scale(src image.Image, from image.Rectangle, w, h int) image.Image {
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, from, draw.Src, nil)
+	return dst
+}
@@ -0,0 +1,110 @@
+package thumbnails
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newImage(w, h int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	return img
+}
+
+func TestResize(t *testing.T) {
+	tests := []struct {
+		name         string
+		srcW, srcH   int
+		opts         Options
+		wantW, wantH int
+	}{
+		{"Contain Landscape", 400, 200, Options{Width: 100, Height: 100, Fit: FitContain}, 100, 50},
+		{"Contain Portrait", 200, 400, Options{Width: 100, Height: 100, Fit: FitContain}, 50, 100},
+		{"Contain Never Enlarges", 50, 40, Options{Width: 100, Height: 100, Fit: FitContain}, 50, 40},
+		{"Cover Landscape", 400, 200, Options{Width: 100, Height: 100, Fit: FitCover}, 100, 100},
+		{"Cover Portrait", 200, 400, Options{Width: 120, Height: 60, Fit: FitCover}, 120, 60},
+		{"Width Only", 400, 200, Options{Width: 200, Fit: FitContain}, 200, 100},
+		{"Height Only", 400, 200, Options{Height: 50, Fit: FitCover}, 100, 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Resize(newImage(tt.srcW, tt.srcH), tt.opts)
+			assert.Equal(t, tt.wantW, got.Bounds().Dx())
+			assert.Equal(t, tt.wantH, got.Bounds().Dy())
+		})
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	src := newImage(300, 150)
+	encoders := map[string]func(io.Writer, image.Image) error{
+		"jpeg": func(w io.Writer, m image.Image) error { return jpeg.Encode(w, m, nil) },
+		"png":  png.Encode,
+	}
+
+	for format, encode := range encoders {
+		t.Run(format, func(t *testing.T) {
+			var source bytes.Buffer
+			require.NoError(t, encode(&source, src))
+
+			thumb, err := Generate(&source, Options{Width: 60, Height: 60, Fit: FitCover})
+			require.NoError(t, err)
+
+			config, gotFormat, err := image.DecodeConfig(bytes.NewReader(thumb))
+			require.NoError(t, err)
+			assert.Equal(t, format, gotFormat)
+			assert.Equal(t, 60, config.Width)
+			assert.Equal(t, 60, config.Height)
+		})
+	}
+
+	t.Run("Unsupported Format", func(t *testing.T) {
+		var source bytes.Buffer
+		require.NoError(t, gif.Encode(&source, src, nil))
+
+		_, err := Generate(&source, Options{Width: 60, Fit: FitContain})
+		assert.ErrorIs(t, err, ErrUnsupportedFormat)
+	})
+}
+
+func TestKey(t *testing.T) {
+	base := Key("abc", Options{Width: 200, Height: 200, Fit: FitCover})
+	assert.Equal(t, base, Key("abc", Options{Width: 200, Height: 200, Fit: FitCover}))
+	assert.NotEqual(t, base, Key("abd", Options{Width: 200, Height: 200, Fit: FitCover}))
+	assert.NotEqual(t, base, Key("abc", Options{Width: 200, Height: 200, Fit: FitContain}))
+	assert.NotEqual(t, base, Key("abc", Options{Width: 200, Height: 100, Fit: FitCover}))
+}
+
+func TestCache(t *testing.T) {
+	cache, err := NewCache(t.TempDir())
+	require.NoError(t, err)
+	key := Key("abc", Options{Width: 200, Fit: FitContain})
+
+	_, ok, err := cache.Open(key)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, cache.Put(key, []byte("thumbnail")))
+
+	f, ok, err := cache.Open(key)
+	require.NoError(t, err)
+	require.True(t, ok)
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	require.NoError(t, err)
+	assert.Equal(t, "thumbnail", string(data))
+}
@@ -0,0 +1,69 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/mariosker/products_rest_api/internal/database"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// QueryTracer is a pgx query tracer that creates a span for every query made by a named operation
+// while serving a traced request.
+type QueryTracer struct{}
+
+// NewQueryTracer creates a query tracer. Spans are named after the operation that made the query,
+// as named with database.WithOperation, such as PostgresProductRepository.GetProductByID.
+func NewQueryTracer() *QueryTracer {
+	return &QueryTracer{}
+}
+
+// TraceQueryStart implements pgx.QueryTracer. Queries made outside a traced request, such as those
+// of the background workers, and queries of unnamed operations are not traced.
+func (t *QueryTracer) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx
+	}
+	name := database.Operation(ctx)
+	if name == "" {
+		return ctx
+	}
+
+	ctx, _ = otel.Tracer(instrumentationName).Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemPostgreSQL,
+			semconv.DBOperationName(operation(data.SQL)),
+			semconv.DBQueryText(data.SQL),
+		),
+	)
+	return context.WithValue(ctx, querySpanKey{}, true)
+}
+
+// TraceQueryEnd implements pgx.QueryTracer. Queries that return rows end when the rows are closed.
+func (t *QueryTracer) TraceQueryEnd(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryEndData) {
+	if traced, _ := ctx.Value(querySpanKey{}).(bool); !traced {
+		return
+	}
+	span := trace.SpanFromContext(ctx)
+	if data.Err != nil {
+		span.RecordError(data.Err)
+		span.SetStatus(codes.Error, data.Err.Error())
+	}
+	span.End()
+}
+
+// querySpanKey marks contexts whose span was started by TraceQueryStart, so TraceQueryEnd does not
+// end the span of the request when a query was not traced.
+type querySpanKey struct{}
+
+// operation returns the SQL keyword a statement starts with, such as SELECT or INSERT.
+func operation(sql string) string {
+	keyword, _, _ := strings.Cut(strings.TrimSpace(sql), " ")
+	keyword, _, _ = strings.Cut(keyword, "\n")
+	return strings.ToUpper(keyword)
+}
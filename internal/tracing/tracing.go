@@ -0,0 +1,68 @@
+// Package tracing traces requests with OpenTelemetry, from the HTTP handlers down to the queries
+// they make, and exports the spans over OTLP or to stdout.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// instrumentationName names the tracer the spans of this package are created with.
+const instrumentationName = "github.com/mariosker/products_rest_api/internal/tracing"
+
+// Options configures where spans are exported to.
+type Options struct {
+	// Exporter is "otlp" to export spans over OTLP/HTTP or "stdout" to print them, for local runs.
+	Exporter string
+	// Endpoint is the host and port of the OTLP collector, such as "localhost:4318". The
+	// OTEL_EXPORTER_OTLP_ENDPOINT environment variable, or localhost:4318, is used when it is empty.
+	Endpoint string
+	// Insecure exports over plain HTTP instead of HTTPS.
+	Insecure bool
+	// ServiceName is the service.name resource attribute of every span.
+	ServiceName string
+}
+
+// Setup installs a global tracer provider that exports spans as configured by opts, and the W3C
+// trace context and baggage propagators. The returned function flushes pending spans and stops
+// the provider, and must be called before the process exits.
+func Setup(ctx context.Context, opts Options) (func(context.Context) error, error) {
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch opts.Exporter {
+	case "otlp":
+		exporterOpts := []otlptracehttp.Option{}
+		if opts.Endpoint != "" {
+			exporterOpts = append(exporterOpts, otlptracehttp.WithEndpoint(opts.Endpoint))
+		}
+		if opts.Insecure {
+			exporterOpts = append(exporterOpts, otlptracehttp.WithInsecure())
+		}
+		exporter, err = otlptracehttp.New(ctx, exporterOpts...)
+	case "stdout":
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout), stdouttrace.WithPrettyPrint())
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", opts.Exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s trace exporter: %w", opts.Exporter, err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(semconv.ServiceName(opts.ServiceName)))
+	if err != nil {
+		return nil, err
+	}
+	provider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return provider.Shutdown, nil
+}
@@ -0,0 +1,153 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/mariosker/products_rest_api/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// recordSpans installs a tracer provider that records the spans ended during the test.
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+	return recorder
+}
+
+func attributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+// fakeRepository stands in for a repository method that makes a query while serving a request.
+type fakeRepository struct {
+	tracer *QueryTracer
+}
+
+func (r *fakeRepository) GetProduct(ctx context.Context, err error) {
+	ctx = database.WithOperation(ctx, "fakeRepository.GetProduct")
+	ctx = r.tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "SELECT id, name FROM products WHERE id = $1"})
+	r.tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: err})
+}
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(Middleware())
+	router.GET("/products/:id", func(c *gin.Context) {
+		repo := &fakeRepository{tracer: NewQueryTracer()}
+		repo.GetProduct(c.Request.Context(), nil)
+		c.Status(http.StatusOK)
+	})
+	router.GET("/fail", func(c *gin.Context) { c.Status(http.StatusInternalServerError) })
+
+	t.Run("Continues Client Trace", func(t *testing.T) {
+		recorder := recordSpans(t)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/products/7", nil)
+		req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		spans := recorder.Ended()
+		require.Len(t, spans, 2)
+		query, request := spans[0], spans[1]
+
+		assert.Equal(t, "GET /products/:id", request.Name())
+		assert.Equal(t, trace.SpanKindServer, request.SpanKind())
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", request.SpanContext().TraceID().String())
+		assert.Equal(t, "00f067aa0ba902b7", request.Parent().SpanID().String())
+		assert.Equal(t, "/products/:id", attributes(request)["http.route"].AsString())
+		assert.Equal(t, int64(http.StatusOK), attributes(request)["http.response.status_code"].AsInt64())
+
+		assert.Equal(t, "fakeRepository.GetProduct", query.Name())
+		assert.Equal(t, request.SpanContext().SpanID(), query.Parent().SpanID())
+		assert.Equal(t, "SELECT", attributes(query)["db.operation.name"].AsString())
+		assert.Equal(t, "SELECT id, name FROM products WHERE id = $1", attributes(query)["db.query.text"].AsString())
+	})
+
+	t.Run("Server Error", func(t *testing.T) {
+		recorder := recordSpans(t)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/fail", nil)
+		router.ServeHTTP(w, req)
+
+		spans := recorder.Ended()
+		require.Len(t, spans, 1)
+		assert.Equal(t, codes.Error, spans[0].Status().Code)
+		assert.False(t, spans[0].Parent().IsValid(), "requests without a traceparent should start a new trace")
+	})
+}
+
+func TestQueryTracer(t *testing.T) {
+	repo := &fakeRepository{tracer: NewQueryTracer()}
+
+	t.Run("Failed Query", func(t *testing.T) {
+		recorder := recordSpans(t)
+		ctx, span := otel.Tracer("test").Start(context.Background(), "request")
+		repo.GetProduct(ctx, errors.New("relation does not exist"))
+		span.End()
+
+		spans := recorder.Ended()
+		require.Len(t, spans, 2)
+		assert.Equal(t, codes.Error, spans[0].Status().Code)
+		assert.Equal(t, "relation does not exist", spans[0].Status().Description)
+		assert.Equal(t, codes.Unset, spans[1].Status().Code, "the request span should be left to the middleware")
+	})
+
+	t.Run("Outside A Request", func(t *testing.T) {
+		recorder := recordSpans(t)
+		repo.GetProduct(context.Background(), nil)
+		assert.Empty(t, recorder.Ended())
+	})
+
+	t.Run("Unnamed Operation", func(t *testing.T) {
+		recorder := recordSpans(t)
+		ctx, span := otel.Tracer("test").Start(context.Background(), "request")
+		ctx = repo.tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "SELECT 1"})
+		repo.tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{})
+		span.End()
+
+		require.Len(t, recorder.Ended(), 1, "only the request span should be recorded")
+	})
+}
+
+func TestOperation(t *testing.T) {
+	assert.Equal(t, "SELECT", operation("\n\t\tSELECT id FROM products"))
+	assert.Equal(t, "WITH", operation("with\nrecent AS (SELECT 1) SELECT * FROM recent"))
+	assert.Equal(t, "UPDATE", operation("UPDATE products SET name = $1"))
+}
+
+func TestSetup(t *testing.T) {
+	t.Run("Stdout", func(t *testing.T) {
+		shutdown, err := Setup(context.Background(), Options{Exporter: "stdout", ServiceName: "products-api"})
+		require.NoError(t, err)
+		assert.NoError(t, shutdown(context.Background()))
+	})
+
+	t.Run("Unknown Exporter", func(t *testing.T) {
+		_, err := Setup(context.Background(), Options{Exporter: "zipkin"})
+		assert.ErrorContains(t, err, `unknown trace exporter "zipkin"`)
+	})
+}
@@ -0,0 +1,40 @@
+package utils
+
+import "strings"
+
+// gtinLength is the length of a GTIN-14, the form every shorter GTIN is normalized to.
+const gtinLength = 14
+
+// IsValidGTIN reports whether code is a GTIN-8, GTIN-12 (UPC-A), GTIN-13 (EAN-13)
+// or GTIN-14 made only of digits with a correct check digit.
+func IsValidGTIN(code string) bool {
+	switch len(code) {
+	case 8, 12, 13, 14:
+	default:
+		return false
+	}
+
+	sum := 0
+	for i := len(code) - 1; i >= 0; i-- {
+		c := code[i]
+		if c < '0' || c > '9' {
+			return false
+		}
+		digit := int(c - '0')
+		// Weights alternate 1 (check digit), 3, 1, 3, ... from the right.
+		if (len(code)-1-i)%2 == 1 {
+			digit *= 3
+		}
+		sum += digit
+	}
+	return sum%10 == 0
+}
+
+// NormalizeGTIN validates code and left-pads it with zeros to a GTIN-14, so that
+// the UPC-A "036000291452" and the EAN-13 "0036000291452" normalize to the same value.
+func NormalizeGTIN(code string) (string, bool) {
+	if !IsValidGTIN(code) {
+		return "", false
+	}
+	return strings.Repeat("0", gtinLength-len(code)) + code, true
+}
@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsValidGTIN(t *testing.T) {
+	tests := []struct {
+		name  string
+		code  string
+		valid bool
+	}{
+		{"GTIN-8", "96385074", true},
+		{"UPC-A", "036000291452", true},
+		{"EAN-13", "4006381333931", true},
+		{"GTIN-14", "10036000291459", true},
+		{"Wrong Check Digit", "036000291453", false},
+		{"Unsupported Length", "1234567", false},
+		{"Non Digits", "03600029145A", false},
+		{"Empty", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.valid, IsValidGTIN(tt.code))
+		})
+	}
+}
+
+func TestNormalizeGTIN(t *testing.T) {
+	upc, ok := NormalizeGTIN("036000291452")
+	assert.True(t, ok)
+	ean, ok := NormalizeGTIN("0036000291452")
+	assert.True(t, ok)
+	assert.Equal(t, "00036000291452", upc)
+	assert.Equal(t, upc, ean)
+
+	_, ok = NormalizeGTIN("036000291453")
+	assert.False(t, ok)
+}
@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"Blue T-Shirt", "blue-t-shirt"},
+		{"  Leading and trailing  ", "leading-and-trailing"},
+		{"Café Crème", "cafe-creme"},
+		{"100% Cotton -- Size XL!", "100-cotton-size-xl"},
+		{"日本語", "product"},
+		{"", "product"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Slugify(tt.name))
+		})
+	}
+}
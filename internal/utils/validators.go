@@ -0,0 +1,24 @@
+package utils
+
+import (
+	"sync"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+var registerValidatorsOnce sync.Once
+
+// RegisterValidators registers the custom binding tags used by the request payloads
+// with Gin's validator. It is safe to call more than once.
+func RegisterValidators() {
+	registerValidatorsOnce.Do(func() {
+		v, ok := binding.Validator.Engine().(*validator.Validate)
+		if !ok {
+			return
+		}
+		_ = v.RegisterValidation("gtin", func(fl validator.FieldLevel) bool {
+			return IsValidGTIN(fl.Field().String())
+		})
+	})
+}
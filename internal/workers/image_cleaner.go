@@ -0,0 +1,68 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/mariosker/products_rest_api/internal/repository"
+	"github.com/mariosker/products_rest_api/internal/storage"
+)
+
+// imageCleanupBatch is the most orphaned images removed per run.
+const imageCleanupBatch = 100
+
+// ImageCleaner periodically removes the stored content of deleted images, including those deleted
+// along with their product.
+type ImageCleaner struct {
+	repo     repository.ImageRepository
+	store    storage.Storage
+	interval time.Duration
+}
+
+// NewImageCleaner creates a new ImageCleaner that removes content from store every interval.
+func NewImageCleaner(repo repository.ImageRepository, store storage.Storage, interval time.Duration) *ImageCleaner {
+	return &ImageCleaner{repo: repo, store: store, interval: interval}
+}
+
+// Run removes orphaned image content until ctx is cancelled.
+func (c *ImageCleaner) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.Clean(ctx)
+		}
+	}
+}
+
+// Clean removes the content of up to imageCleanupBatch orphaned images once. Content that cannot be
+// removed stays queued and is retried on the next run.
+func (c *ImageCleaner) Clean(ctx context.Context) {
+	keys, err := c.repo.GetOrphanedImageKeys(ctx, imageCleanupBatch)
+	if err != nil {
+		log.Printf("Failed to list orphaned images: %v", err)
+		return
+	}
+
+	removed := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if err := c.store.Delete(ctx, key); err != nil {
+			log.Printf("Failed to delete image content %s: %v", key, err)
+			continue
+		}
+		removed = append(removed, key)
+	}
+	if len(removed) == 0 {
+		return
+	}
+	if err := c.repo.DeleteOrphanedImageKeys(ctx, removed); err != nil {
+		log.Printf("Failed to forget orphaned images: %v", err)
+		return
+	}
+	log.Printf("Removed %d orphaned images", len(removed))
+}
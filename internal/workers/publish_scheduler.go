@@ -0,0 +1,47 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/mariosker/products_rest_api/internal/repository"
+)
+
+// PublishScheduler periodically publishes drafts whose scheduled publish time has passed.
+type PublishScheduler struct {
+	repo     repository.ProductRepository
+	interval time.Duration
+}
+
+// NewPublishScheduler creates a new PublishScheduler that runs every interval.
+func NewPublishScheduler(repo repository.ProductRepository, interval time.Duration) *PublishScheduler {
+	return &PublishScheduler{repo: repo, interval: interval}
+}
+
+// Run publishes scheduled products until ctx is cancelled.
+func (s *PublishScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.Publish(ctx)
+		}
+	}
+}
+
+// Publish publishes every product whose publish time has passed once.
+func (s *PublishScheduler) Publish(ctx context.Context) {
+	published, err := s.repo.PublishScheduledProducts(ctx)
+	if err != nil {
+		log.Printf("Failed to publish scheduled products: %v", err)
+		return
+	}
+	if published > 0 {
+		log.Printf("Published %d scheduled products", published)
+	}
+}
@@ -0,0 +1,48 @@
+// Package workers provides background jobs that run alongside the HTTP server.
+package workers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/mariosker/products_rest_api/internal/repository"
+)
+
+// ReservationSweeper periodically releases reservations whose TTL has elapsed.
+type ReservationSweeper struct {
+	repo     repository.ReservationRepository
+	interval time.Duration
+}
+
+// NewReservationSweeper creates a new ReservationSweeper that runs every interval.
+func NewReservationSweeper(repo repository.ReservationRepository, interval time.Duration) *ReservationSweeper {
+	return &ReservationSweeper{repo: repo, interval: interval}
+}
+
+// Run sweeps expired reservations until ctx is cancelled.
+func (s *ReservationSweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.Sweep(ctx)
+		}
+	}
+}
+
+// Sweep releases every expired reservation once.
+func (s *ReservationSweeper) Sweep(ctx context.Context) {
+	released, err := s.repo.ReleaseExpiredReservations(ctx)
+	if err != nil {
+		log.Printf("Failed to release expired reservations: %v", err)
+		return
+	}
+	if released > 0 {
+		log.Printf("Released %d expired reservations", released)
+	}
+}
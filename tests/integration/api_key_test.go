@@ -0,0 +1,138 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mariosker/products_rest_api/internal/auth"
+	"github.com/mariosker/products_rest_api/internal/handlers"
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/mariosker/products_rest_api/internal/repository"
+	"github.com/mariosker/products_rest_api/internal/routes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeys(t *testing.T) {
+	require.NoError(t, truncateTables())
+	t.Cleanup(func() { _ = truncateTables() })
+
+	gin.SetMode(gin.TestMode)
+	apiKeyRepo := repository.NewPostgresAPIKeyRepository(pgxConn)
+	router := gin.New()
+	router.Use(auth.Authenticate(apiKeyRepo, auth.Options{PublicPaths: []string{"/health"}, AdminKey: "pk_admin", Policy: auth.DefaultPolicy}))
+	router.GET("/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+	routes.SetupRoutes(router, handlers.NewProductHandler(repository.NewPostgresProductRepository(pgxConn)))
+	routes.SetupAPIKeyRoutes(router, handlers.NewAPIKeyHandler(apiKeyRepo))
+
+	send := func(method, path, key, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if key != "" {
+			req.Header.Set("Authorization", "Bearer "+key)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	issue := func(body string) models.IssuedAPIKey {
+		w := send("POST", "/admin/api-keys", "pk_admin", body)
+		require.Equal(t, http.StatusCreated, w.Code)
+		var issued models.IssuedAPIKey
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &issued))
+		return issued
+	}
+
+	reader := issue(`{"name":"storefront","scopes":["products:read"]}`)
+	writer := issue(`{"name":"backoffice","scopes":["products:read","products:write","products:price:write"]}`)
+
+	t.Run("Public Path", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, send("GET", "/health", "", "").Code)
+	})
+
+	t.Run("Scopes", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, send("GET", "/products", "", "").Code)
+		assert.Equal(t, http.StatusOK, send("GET", "/products", reader.Key, "").Code)
+		assert.Equal(t, http.StatusForbidden, send("POST", "/products", reader.Key, `{"name":"Lamp","price":30}`).Code)
+		assert.Equal(t, http.StatusCreated, send("POST", "/products", writer.Key, `{"name":"Lamp","price":30}`).Code)
+		assert.Equal(t, http.StatusForbidden, send("GET", "/admin/api-keys", writer.Key, "").Code)
+	})
+
+	t.Run("Editor Role Cannot Change Prices", func(t *testing.T) {
+		editor := issue(`{"name":"catalog","roles":["editor"]}`)
+		assert.Equal(t, http.StatusForbidden, send("POST", "/products", editor.Key, `{"name":"Desk","price":200}`).Code)
+		w := send("POST", "/products", "pk_admin", `{"name":"Desk","price":200}`)
+		require.Equal(t, http.StatusCreated, w.Code)
+		var created models.CreateProductResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+		path := fmt.Sprintf("/products/%d", created.ID)
+
+		assert.Equal(t, http.StatusForbidden, send("PUT", path, editor.Key, `{"name":"Standing Desk","price":250}`).Code)
+		assert.Equal(t, http.StatusOK, send("PUT", path, editor.Key, `{"name":"Standing Desk","price":200}`).Code)
+		assert.Equal(t, http.StatusOK, send("PUT", path, "pk_admin", `{"name":"Standing Desk","price":250}`).Code)
+	})
+
+	t.Run("List Without Keys", func(t *testing.T) {
+		w := send("GET", "/admin/api-keys", "pk_admin", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, w.Body.String(), reader.Key)
+		var keys []models.APIKey
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &keys))
+		assert.Len(t, keys, 3)
+	})
+
+	t.Run("Rotate With Grace Period", func(t *testing.T) {
+		w := send("POST", fmt.Sprintf("/admin/api-keys/%d/rotate", reader.ID), "pk_admin", `{"grace_seconds":60}`)
+		require.Equal(t, http.StatusOK, w.Code)
+		var rotated models.IssuedAPIKey
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &rotated))
+		assert.NotEqual(t, reader.Key, rotated.Key)
+
+		assert.Equal(t, http.StatusOK, send("GET", "/products", reader.Key, "").Code)
+		assert.Equal(t, http.StatusOK, send("GET", "/products", rotated.Key, "").Code)
+	})
+
+	t.Run("Rotate Immediately", func(t *testing.T) {
+		w := send("POST", fmt.Sprintf("/admin/api-keys/%d/rotate", writer.ID), "pk_admin", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, http.StatusUnauthorized, send("GET", "/products", writer.Key, "").Code)
+	})
+
+	t.Run("Revoke", func(t *testing.T) {
+		assert.Equal(t, http.StatusNoContent, send("DELETE", fmt.Sprintf("/admin/api-keys/%d", reader.ID), "pk_admin", "").Code)
+		assert.Equal(t, http.StatusUnauthorized, send("GET", "/products", reader.Key, "").Code)
+		assert.Equal(t, http.StatusNotFound, send("DELETE", fmt.Sprintf("/admin/api-keys/%d", reader.ID), "pk_admin", "").Code)
+	})
+
+	t.Run("Tenant Admin Only Manages Its Tenant", func(t *testing.T) {
+		acmeAdmin := issue(`{"name":"acme admin","scopes":["admin"],"tenant_id":"acme"}`)
+		unbound := issue(`{"name":"integration","scopes":["products:read"]}`)
+
+		assert.Equal(t, http.StatusForbidden, send("POST", "/admin/api-keys", acmeAdmin.Key, `{"name":"planted","scopes":["admin"],"tenant_id":"globex"}`).Code)
+
+		w := send("POST", "/admin/api-keys", acmeAdmin.Key, `{"name":"acme storefront","scopes":["products:read"]}`)
+		require.Equal(t, http.StatusCreated, w.Code)
+		var issued models.IssuedAPIKey
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &issued))
+		assert.Equal(t, "acme", issued.TenantID)
+
+		w = send("GET", "/admin/api-keys", acmeAdmin.Key, "")
+		require.Equal(t, http.StatusOK, w.Code)
+		var keys []models.APIKey
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &keys))
+		require.Len(t, keys, 2)
+		for _, key := range keys {
+			assert.Equal(t, "acme", key.TenantID)
+		}
+
+		assert.Equal(t, http.StatusNotFound, send("POST", fmt.Sprintf("/admin/api-keys/%d/rotate", unbound.ID), acmeAdmin.Key, "").Code)
+		assert.Equal(t, http.StatusNotFound, send("DELETE", fmt.Sprintf("/admin/api-keys/%d", unbound.ID), acmeAdmin.Key, "").Code)
+		assert.Equal(t, http.StatusOK, send("GET", "/products", unbound.Key, "").Code)
+		assert.Equal(t, http.StatusNoContent, send("DELETE", fmt.Sprintf("/admin/api-keys/%d", issued.ID), acmeAdmin.Key, "").Code)
+	})
+}
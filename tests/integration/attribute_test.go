@@ -0,0 +1,98 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProductAttributes(t *testing.T) {
+	router := setupTest(t)
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	require.Equal(t, http.StatusOK, send("PUT", "/categories/laptops/attributes/ram_gb", `{"type":"number","required":true,"min":4}`).Code)
+	require.Equal(t, http.StatusOK, send("PUT", "/categories/laptops/attributes/colour", `{"type":"string","allowed_values":["silver","black"]}`).Code)
+	require.Equal(t, http.StatusOK, send("PUT", "/categories/laptops/attributes/touchscreen", `{"type":"boolean"}`).Code)
+
+	t.Run("List Definitions", func(t *testing.T) {
+		w := send("GET", "/categories/laptops/attributes", "")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var definitions []models.AttributeDefinition
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &definitions))
+		assert.Len(t, definitions, 3)
+	})
+
+	t.Run("Create Valid Attributes", func(t *testing.T) {
+		w := send("POST", "/products", `{"name":"Laptop 16","price":1200,"category":"laptops","attributes":{"ram_gb":16,"colour":"silver","touchscreen":true}}`)
+		assert.Equal(t, http.StatusCreated, w.Code)
+		w = send("POST", "/products", `{"name":"Laptop 8","price":800,"category":"laptops","attributes":{"ram_gb":8,"colour":"black"}}`)
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+
+	t.Run("Create Invalid Attributes", func(t *testing.T) {
+		w := send("POST", "/products", `{"name":"Laptop","price":900,"category":"laptops","attributes":{"ram_gb":2,"colour":"gold"}}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "colour must be one of silver, black")
+		assert.Contains(t, w.Body.String(), "ram_gb must be at least 4")
+	})
+
+	t.Run("Create Missing Required Attribute", func(t *testing.T) {
+		w := send("POST", "/products", `{"name":"Laptop","price":900,"category":"laptops","attributes":{"colour":"black"}}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "ram_gb is required")
+	})
+
+	t.Run("Uncategorised Accepts Any Attributes", func(t *testing.T) {
+		w := send("POST", "/products", `{"name":"Mug","price":5,"attributes":{"volume_ml":350}}`)
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+
+	t.Run("Filter By Equality", func(t *testing.T) {
+		w := send("GET", "/products?attr.colour=silver", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"name":"Laptop 16"`)
+		assert.NotContains(t, w.Body.String(), `"name":"Laptop 8"`)
+
+		w = send("GET", "/products?attr.touchscreen=true", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"name":"Laptop 16"`)
+	})
+
+	t.Run("Filter By Range", func(t *testing.T) {
+		w := send("GET", "/products?attr.ram_gb_gte=8&attr.ram_gb_lt=16", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"name":"Laptop 8"`)
+		assert.NotContains(t, w.Body.String(), `"name":"Laptop 16"`)
+	})
+
+	t.Run("Update Validates Against Category", func(t *testing.T) {
+		productID, err := insertTestProduct("Plain", 10.0)
+		require.NoError(t, err)
+
+		w := send("PUT", fmt.Sprintf("/products/%d", productID), `{"name":"Plain","price":10,"category":"laptops"}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = send("PUT", fmt.Sprintf("/products/%d", productID), `{"name":"Plain","price":10,"category":"laptops","attributes":{"ram_gb":32}}`)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Delete Definition", func(t *testing.T) {
+		assert.Equal(t, http.StatusNoContent, send("DELETE", "/categories/laptops/attributes/touchscreen", "").Code)
+		assert.Equal(t, http.StatusNotFound, send("DELETE", "/categories/laptops/attributes/touchscreen", "").Code)
+	})
+}
@@ -0,0 +1,112 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundles(t *testing.T) {
+	router := setupTest(t)
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	create := func(body string) int {
+		w := send("POST", "/products", body)
+		require.Equal(t, http.StatusCreated, w.Code)
+		var created models.CreateProductResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+		return created.ID
+	}
+	getProduct := func(id int) models.Product {
+		w := send("GET", fmt.Sprintf("/products/%d", id), "")
+		require.Equal(t, http.StatusOK, w.Code)
+		var product models.Product
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &product))
+		return product
+	}
+
+	camera := create(`{"name":"Camera","price":400,"stock":7}`)
+	lens := create(`{"name":"Lens","price":150,"stock":20}`)
+	kit := create(`{"name":"Camera Kit","price":500}`)
+	bundlePath := fmt.Sprintf("/products/%d/bundle", kit)
+
+	t.Run("Derived Price And Availability", func(t *testing.T) {
+		body := fmt.Sprintf(`{"pricing":"derived","discount_percent":10,"components":[{"product_id":%d,"quantity":1},{"product_id":%d,"quantity":2}]}`, camera, lens)
+		w := send("PUT", bundlePath, body)
+		require.Equal(t, http.StatusOK, w.Code)
+		var bundle models.Bundle
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &bundle))
+		assert.Equal(t, models.BundlePricingDerived, bundle.Pricing)
+		assert.Len(t, bundle.Components, 2)
+
+		product := getProduct(kit)
+		assert.Equal(t, models.ProductTypeBundle, product.Type)
+		assert.Equal(t, 630.0, product.Price)
+		assert.Equal(t, 7, product.Available)
+	})
+
+	t.Run("Availability Follows Reservations", func(t *testing.T) {
+		w := send("POST", "/reservations", fmt.Sprintf(`{"items":[{"product_id":%d,"quantity":8}]}`, lens))
+		require.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, 6, getProduct(kit).Available)
+	})
+
+	t.Run("Reserving Holds Components", func(t *testing.T) {
+		w := send("POST", "/reservations", fmt.Sprintf(`{"items":[{"product_id":%d,"quantity":2}]}`, kit))
+		require.Equal(t, http.StatusCreated, w.Code)
+		var reservation models.Reservation
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &reservation))
+		assert.ElementsMatch(t, []models.ReservationItem{{ProductID: camera, Quantity: 2}, {ProductID: lens, Quantity: 4}}, reservation.Items)
+		assert.Equal(t, 4, getProduct(kit).Available)
+
+		w = send("POST", fmt.Sprintf("/reservations/%d/commit", reservation.ID), "")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, 5, getProduct(camera).Stock)
+		assert.Equal(t, 16, getProduct(lens).Stock)
+		assert.Equal(t, 4, getProduct(kit).Available)
+
+		w = send("POST", "/reservations", fmt.Sprintf(`{"items":[{"product_id":%d,"quantity":5}]}`, kit))
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("Fixed Price", func(t *testing.T) {
+		body := fmt.Sprintf(`{"pricing":"fixed","components":[{"product_id":%d,"quantity":1}]}`, camera)
+		require.Equal(t, http.StatusOK, send("PUT", bundlePath, body).Code)
+		assert.Equal(t, 500.0, getProduct(kit).Price)
+	})
+
+	t.Run("Invalid Components", func(t *testing.T) {
+		self := fmt.Sprintf(`{"pricing":"fixed","components":[{"product_id":%d,"quantity":1}]}`, kit)
+		assert.Equal(t, http.StatusBadRequest, send("PUT", bundlePath, self).Code)
+
+		missing := `{"pricing":"fixed","components":[{"product_id":9999,"quantity":1}]}`
+		assert.Equal(t, http.StatusBadRequest, send("PUT", bundlePath, missing).Code)
+
+		nested := fmt.Sprintf(`{"pricing":"fixed","components":[{"product_id":%d,"quantity":1}]}`, kit)
+		assert.Equal(t, http.StatusBadRequest, send("PUT", fmt.Sprintf("/products/%d/bundle", lens), nested).Code)
+	})
+
+	t.Run("Component Cannot Be Deleted", func(t *testing.T) {
+		assert.Equal(t, http.StatusConflict, send("DELETE", fmt.Sprintf("/products/%d", camera), "").Code)
+	})
+
+	t.Run("Unbundle", func(t *testing.T) {
+		assert.Equal(t, http.StatusNoContent, send("DELETE", bundlePath, "").Code)
+		assert.Equal(t, http.StatusNotFound, send("GET", bundlePath, "").Code)
+		assert.Equal(t, models.ProductTypeSimple, getProduct(kit).Type)
+		assert.Equal(t, http.StatusNoContent, send("DELETE", fmt.Sprintf("/products/%d", camera), "").Code)
+	})
+}
@@ -0,0 +1,80 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscountsAndQuotes(t *testing.T) {
+	router := setupTest(t)
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	createProduct := func(body string) int {
+		w := send("POST", "/products", body)
+		require.Equal(t, http.StatusCreated, w.Code)
+		var created models.CreateProductResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+		return created.ID
+	}
+
+	laptopID := createProduct(`{"name":"Laptop","price":1000,"category":"laptops"}`)
+	cableID := createProduct(`{"name":"Cable","price":5,"tags":["bulk"]}`)
+
+	require.Equal(t, http.StatusCreated, send("POST", "/discounts", `{"name":"20% off laptops","type":"percentage","value":20,"target":{"categories":["laptops"]}}`).Code)
+	w := send("POST", "/discounts", `{"name":"Buy 3 get 10% off","type":"tiered","tiers":[{"min_quantity":3,"percent":10}],"target":{"tags":["bulk"]},"priority":5}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	var tiered models.Discount
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tiered))
+	assert.Equal(t, []models.DiscountTier{{MinQuantity: 3, Percent: 10}}, tiered.Tiers)
+
+	t.Run("List In Priority Order", func(t *testing.T) {
+		w := send("GET", "/discounts", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		var discounts []models.Discount
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &discounts))
+		require.Len(t, discounts, 2)
+		assert.Equal(t, tiered.ID, discounts[0].ID)
+	})
+
+	t.Run("Quote", func(t *testing.T) {
+		w := send("POST", "/pricing/quote", fmt.Sprintf(`{"items":[{"product_id":%d,"quantity":1},{"product_id":%d,"quantity":4}]}`, laptopID, cableID))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var quote models.Quote
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &quote))
+		require.Len(t, quote.Items, 2)
+		assert.Equal(t, 800.0, quote.Items[0].Total)
+		assert.Equal(t, 18.0, quote.Items[1].Total)
+		assert.Equal(t, 818.0, quote.Total)
+		assert.Equal(t, 202.0, quote.Discount)
+	})
+
+	t.Run("Update And Delete", func(t *testing.T) {
+		path := fmt.Sprintf("/discounts/%d", tiered.ID)
+		w := send("PUT", path, `{"name":"Bulk","type":"fixed","value":1,"target":{"tags":["bulk"]}}`)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, w.Body.String(), "tiers")
+
+		assert.Equal(t, http.StatusNoContent, send("DELETE", path, "").Code)
+		assert.Equal(t, http.StatusNotFound, send("GET", path, "").Code)
+	})
+
+	t.Run("Unknown Product", func(t *testing.T) {
+		w := send("POST", "/pricing/quote", `{"items":[{"product_id":9999,"quantity":1}]}`)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
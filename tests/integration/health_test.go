@@ -0,0 +1,43 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mariosker/products_rest_api/internal/health"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthChecks(t *testing.T) {
+	setupTest(t)
+	ctx := context.Background()
+
+	var version uint
+	var dirty bool
+	require.NoError(t, pgxConn.QueryRow(ctx, `SELECT version, dirty FROM schema_migrations`).Scan(&version, &dirty))
+
+	t.Run("Database", func(t *testing.T) {
+		assert.NoError(t, health.Database(pgxConn)(ctx))
+	})
+
+	t.Run("Migrations Applied", func(t *testing.T) {
+		assert.NoError(t, health.Migrations(pgxConn, version)(ctx))
+		assert.NoError(t, health.Migrations(pgxConn, version-1)(ctx), "a newer schema should be accepted")
+	})
+
+	t.Run("Migrations Pending", func(t *testing.T) {
+		assert.ErrorContains(t, health.Migrations(pgxConn, version+1)(ctx), "expected")
+	})
+
+	t.Run("Dirty", func(t *testing.T) {
+		_, err := pgxConn.Exec(ctx, `UPDATE schema_migrations SET dirty = true`)
+		require.NoError(t, err)
+		defer func() {
+			_, err := pgxConn.Exec(ctx, `UPDATE schema_migrations SET dirty = false`)
+			require.NoError(t, err)
+		}()
+
+		assert.ErrorContains(t, health.Migrations(pgxConn, version)(ctx), "failed")
+	})
+}
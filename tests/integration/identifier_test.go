@@ -0,0 +1,113 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProductIdentifiers(t *testing.T) {
+	router := setupTest(t)
+
+	createProduct := func(body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("POST", "/products", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Generated Slugs Get Collision Suffixes", func(t *testing.T) {
+		require.Equal(t, http.StatusCreated, createProduct(`{"name":"Blue Shirt","price":10.0}`).Code)
+		require.Equal(t, http.StatusCreated, createProduct(`{"name":"Blue  shirt!","price":12.0}`).Code)
+
+		var slugs []string
+		rows, err := pgxConn.Query(context.Background(), "SELECT slug FROM products WHERE name ILIKE 'blue%' ORDER BY id")
+		require.NoError(t, err)
+		for rows.Next() {
+			var slug string
+			require.NoError(t, rows.Scan(&slug))
+			slugs = append(slugs, slug)
+		}
+		rows.Close()
+		assert.Equal(t, []string{"blue-shirt", "blue-shirt-2"}, slugs)
+	})
+
+	t.Run("Lookup By SKU And Slug", func(t *testing.T) {
+		require.Equal(t, http.StatusCreated, createProduct(`{"name":"Green Hat","price":5.0,"sku":"HAT-GRN"}`).Code)
+
+		for _, path := range []string{"/products/by-sku/HAT-GRN", "/products/by-slug/green-hat"} {
+			req, _ := http.NewRequest("GET", path, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			require.Equal(t, http.StatusOK, w.Code, path)
+
+			var product models.Product
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &product))
+			assert.Equal(t, "Green Hat", product.Name)
+			assert.Equal(t, "HAT-GRN", product.SKU)
+		}
+	})
+
+	t.Run("Duplicate SKU Conflicts", func(t *testing.T) {
+		assert.Equal(t, http.StatusConflict, createProduct(`{"name":"Other Hat","price":5.0,"sku":"HAT-GRN"}`).Code)
+	})
+
+	t.Run("Duplicate Explicit Slug Conflicts", func(t *testing.T) {
+		assert.Equal(t, http.StatusConflict, createProduct(`{"name":"Another","price":5.0,"slug":"green-hat"}`).Code)
+	})
+
+	t.Run("Unknown SKU", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/products/by-sku/UNKNOWN", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestProductGTIN(t *testing.T) {
+	router := setupTest(t)
+
+	body := `{"name":"Soda","price":1.5,"gtin":"036000291452"}`
+	req, _ := http.NewRequest("POST", "/products", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	t.Run("UPC-A And EAN-13 Forms Match", func(t *testing.T) {
+		for _, code := range []string{"036000291452", "0036000291452", "00036000291452"} {
+			req, _ := http.NewRequest("GET", "/products/by-gtin/"+code, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code, code)
+			assert.Contains(t, w.Body.String(), `"name":"Soda"`)
+		}
+	})
+
+	t.Run("Duplicate GTIN In Another Form Conflicts", func(t *testing.T) {
+		body := `{"name":"Soda Copy","price":1.5,"gtin":"0036000291452"}`
+		req, _ := http.NewRequest("POST", "/products", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("Invalid Check Digit", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/products/by-gtin/036000291453", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
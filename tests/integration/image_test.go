@@ -0,0 +1,138 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/mariosker/products_rest_api/internal/repository"
+	"github.com/mariosker/products_rest_api/internal/storage"
+	"github.com/mariosker/products_rest_api/internal/workers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProductImages(t *testing.T) {
+	router := setupTest(t)
+
+	productID, err := insertTestProduct("Camera", 300.0)
+	require.NoError(t, err)
+
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	upload := func(productID int, content []byte, fields map[string]string) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		for name, value := range fields {
+			_ = form.WriteField(name, value)
+		}
+		part, _ := form.CreateFormFile("image", "photo.png")
+		_, _ = part.Write(content)
+		_ = form.Close()
+
+		req, _ := http.NewRequest("POST", fmt.Sprintf("/products/%d/images", productID), &body)
+		req.Header.Set("Content-Type", form.FormDataContentType())
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	listImages := func() []models.ProductImage {
+		req, _ := http.NewRequest("GET", fmt.Sprintf("/products/%d/images", productID), nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var images []models.ProductImage
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &images))
+		return images
+	}
+
+	var first, second models.ProductImage
+	t.Run("First Upload Becomes Primary", func(t *testing.T) {
+		w := upload(productID, png, nil)
+		require.Equal(t, http.StatusCreated, w.Code)
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &first))
+		assert.True(t, first.IsPrimary)
+		assert.Equal(t, 0, first.Position)
+	})
+
+	t.Run("Upload New Primary", func(t *testing.T) {
+		w := upload(productID, png, map[string]string{"primary": "true"})
+		require.Equal(t, http.StatusCreated, w.Code)
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &second))
+		assert.Equal(t, 1, second.Position)
+
+		images := listImages()
+		require.Len(t, images, 2)
+		assert.False(t, images[0].IsPrimary)
+		assert.True(t, images[1].IsPrimary)
+	})
+
+	t.Run("Download", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", first.URL, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+		assert.Equal(t, png, w.Body.Bytes())
+	})
+
+	t.Run("Unknown Product", func(t *testing.T) {
+		w := upload(9999, png, nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Not An Image", func(t *testing.T) {
+		w := upload(productID, []byte("plain text"), nil)
+		assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+	})
+
+	t.Run("Delete Primary Promotes Next", func(t *testing.T) {
+		req, _ := http.NewRequest("DELETE", second.URL, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusNoContent, w.Code)
+
+		images := listImages()
+		require.Len(t, images, 1)
+		assert.Equal(t, first.ID, images[0].ID)
+		assert.True(t, images[0].IsPrimary)
+
+		req, _ = http.NewRequest("GET", second.URL, nil)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Deleting Product Removes Content", func(t *testing.T) {
+		var key string
+		require.NoError(t, pgxConn.QueryRow(context.Background(),
+			"SELECT storage_key FROM product_images WHERE id = $1", first.ID).Scan(&key))
+
+		req, _ := http.NewRequest("DELETE", fmt.Sprintf("/products/%d", productID), nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusNoContent, w.Code)
+
+		store, err := storage.NewLocalStorage(testImageDir())
+		require.NoError(t, err)
+		imageRepo := repository.NewPostgresImageRepository(pgxConn)
+		keys, err := imageRepo.GetOrphanedImageKeys(context.Background(), 10)
+		require.NoError(t, err)
+		assert.Contains(t, keys, key)
+
+		workers.NewImageCleaner(imageRepo, store, time.Minute).Clean(context.Background())
+		_, err = store.Get(context.Background(), key)
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+		keys, err = imageRepo.GetOrphanedImageKeys(context.Background(), 10)
+		require.NoError(t, err)
+		assert.Empty(t, keys)
+	})
+}
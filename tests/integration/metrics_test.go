@@ -0,0 +1,37 @@
+package integration
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mariosker/products_rest_api/internal/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics(t *testing.T) {
+	router := setupTest(t)
+	registry := metrics.NewRegistry()
+	metrics.RegisterCatalog(registry, pgxConn)
+
+	for _, body := range []string{
+		`{"name":"Draft Lamp","price":30,"status":"draft"}`,
+		`{"name":"Live Lamp","price":40}`,
+		`{"name":"Live Desk","price":120}`,
+	} {
+		req, _ := http.NewRequest("POST", "/products", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusCreated, w.Code)
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/metrics", nil)
+	registry.Handler().ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `catalog_products{status="draft"} 1`)
+	assert.Contains(t, w.Body.String(), `catalog_products{status="published"} 2`)
+}
@@ -0,0 +1,99 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriceSchedules(t *testing.T) {
+	router := setupTest(t)
+
+	productID, err := insertTestProduct("Headphones", 100.0)
+	require.NoError(t, err)
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	getProduct := func() models.Product {
+		w := send("GET", fmt.Sprintf("/products/%d", productID), "")
+		require.Equal(t, http.StatusOK, w.Code)
+		var product models.Product
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &product))
+		return product
+	}
+	schedulesPath := fmt.Sprintf("/products/%d/price-schedules", productID)
+	now := time.Now().UTC()
+
+	// A future sale does not change the price yet.
+	w := send("POST", schedulesPath, fmt.Sprintf(`{"price":60,"starts_at":%q}`, now.Add(24*time.Hour).Format(time.RFC3339)))
+	require.Equal(t, http.StatusCreated, w.Code)
+	product := getProduct()
+	assert.Equal(t, 100.0, product.Price)
+	assert.Equal(t, 100.0, product.ListPrice)
+
+	// An active promotion does.
+	w = send("POST", schedulesPath, fmt.Sprintf(`{"price":80,"starts_at":%q,"ends_at":%q}`,
+		now.Add(-time.Hour).Format(time.RFC3339), now.Add(time.Hour).Format(time.RFC3339)))
+	require.Equal(t, http.StatusCreated, w.Code)
+	var active models.PriceSchedule
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &active))
+
+	product = getProduct()
+	assert.Equal(t, 80.0, product.Price)
+	assert.Equal(t, 100.0, product.ListPrice)
+
+	t.Run("List Reflects Schedule", func(t *testing.T) {
+		w := send("GET", "/products", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"price":80,"list_price":100`)
+	})
+
+	t.Run("Product Update Keeps Schedule", func(t *testing.T) {
+		w := send("PUT", fmt.Sprintf("/products/%d", productID), `{"name":"Headphones","price":100}`)
+		require.Equal(t, http.StatusOK, w.Code)
+		var updated models.Product
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &updated))
+		assert.Equal(t, 80.0, updated.Price)
+		assert.Equal(t, 100.0, updated.ListPrice)
+	})
+
+	t.Run("List Schedules", func(t *testing.T) {
+		w := send("GET", schedulesPath, "")
+		require.Equal(t, http.StatusOK, w.Code)
+		var schedules []models.PriceSchedule
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &schedules))
+		require.Len(t, schedules, 2)
+		assert.Equal(t, active.ID, schedules[0].ID)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		w := send("PUT", fmt.Sprintf("%s/%d", schedulesPath, active.ID), fmt.Sprintf(`{"price":75,"starts_at":%q}`, now.Add(-time.Hour).Format(time.RFC3339)))
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, 75.0, getProduct().Price)
+	})
+
+	t.Run("Delete Restores List Price", func(t *testing.T) {
+		path := fmt.Sprintf("%s/%d", schedulesPath, active.ID)
+		assert.Equal(t, http.StatusNoContent, send("DELETE", path, "").Code)
+		assert.Equal(t, http.StatusNotFound, send("GET", path, "").Code)
+		assert.Equal(t, 100.0, getProduct().Price)
+	})
+
+	t.Run("Unknown Product", func(t *testing.T) {
+		w := send("POST", "/products/9999/price-schedules", fmt.Sprintf(`{"price":1,"starts_at":%q}`, now.Format(time.RFC3339)))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
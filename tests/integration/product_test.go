@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -19,9 +20,13 @@ import (
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 	"github.com/jackc/pgx/v5"
 	_ "github.com/lib/pq"
+	"github.com/mariosker/products_rest_api/internal/auth"
 	"github.com/mariosker/products_rest_api/internal/handlers"
 	"github.com/mariosker/products_rest_api/internal/repository"
 	"github.com/mariosker/products_rest_api/internal/routes"
+	"github.com/mariosker/products_rest_api/internal/storage"
+	"github.com/mariosker/products_rest_api/internal/tax"
+	"github.com/mariosker/products_rest_api/internal/utils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/testcontainers/testcontainers-go"
@@ -102,20 +107,63 @@ func runMigrations(dsn string) error {
 
 func truncateTables() error {
 	_, err := pgxConn.Exec(context.Background(), `
-		TRUNCATE TABLE products RESTART IDENTITY CASCADE;
+		TRUNCATE TABLE products, reservations, attribute_definitions, product_images, orphaned_images, discounts, tax_rates, api_keys, rate_limit_buckets RESTART IDENTITY CASCADE;
 	`)
 	return err
 }
 
-func setupRouter() *gin.Engine {
+func setupRouter(middleware ...gin.HandlerFunc) *gin.Engine {
 	gin.SetMode(gin.TestMode)
+	utils.RegisterValidators()
 	r := gin.Default()
+	r.Use(middleware...)
 	productRepo := repository.NewPostgresProductRepository(pgxConn)
-	productHandler := handlers.NewProductHandler(productRepo)
+	r.Use(handlers.HideUnpublishedProducts(productRepo))
+	variantRepo := repository.NewPostgresVariantRepository(pgxConn)
+	translationRepo := repository.NewPostgresTranslationRepository(pgxConn)
+	taxRepo := repository.NewPostgresTaxRepository(pgxConn)
+	relationRepo := repository.NewPostgresRelationRepository(pgxConn)
+	productHandler := handlers.NewProductHandler(productRepo,
+		handlers.WithVariantRepository(variantRepo),
+		handlers.WithTranslations(translationRepo, "en"),
+		handlers.WithTaxes(taxRepo, tax.RoundHalfUp),
+		handlers.WithRelations(relationRepo))
 	routes.SetupRoutes(r, productHandler)
+	routes.SetupTranslationRoutes(r, handlers.NewTranslationHandler(translationRepo, "en"))
+	routes.SetupVariantRoutes(r, handlers.NewVariantHandler(variantRepo))
+	reservationRepo := repository.NewPostgresReservationRepository(pgxConn)
+	reservationHandler := handlers.NewReservationHandler(reservationRepo, time.Minute)
+	routes.SetupReservationRoutes(r, reservationHandler)
+	routes.SetupAttributeRoutes(r, handlers.NewAttributeHandler(repository.NewPostgresAttributeRepository(pgxConn)))
+	routes.SetupPriceScheduleRoutes(r, handlers.NewPriceScheduleHandler(repository.NewPostgresPriceScheduleRepository(pgxConn)))
+	discountRepo := repository.NewPostgresDiscountRepository(pgxConn)
+	routes.SetupDiscountRoutes(r, handlers.NewDiscountHandler(discountRepo))
+	routes.SetupPricingRoutes(r, handlers.NewPricingHandler(productRepo, discountRepo))
+	routes.SetupTaxRoutes(r, handlers.NewTaxHandler(taxRepo))
+	routes.SetupBundleRoutes(r, handlers.NewBundleHandler(repository.NewPostgresBundleRepository(pgxConn)))
+	routes.SetupRelationRoutes(r, handlers.NewRelationHandler(relationRepo))
+	routes.SetupReviewRoutes(r, handlers.NewReviewHandler(repository.NewPostgresReviewRepository(pgxConn), false))
+	routes.SetupAPIKeyRoutes(r, handlers.NewAPIKeyHandler(repository.NewPostgresAPIKeyRepository(pgxConn)))
+	imageStorage, err := storage.NewLocalStorage(testImageDir())
+	if err != nil {
+		log.Fatalf("Failed to set up image storage: %v", err)
+	}
+	routes.SetupImageRoutes(r, handlers.NewImageHandler(repository.NewPostgresImageRepository(pgxConn), imageStorage, 1<<20))
 	return r
 }
 
+// withPrincipal authenticates every request as a client with scopes.
+func withPrincipal(scopes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		auth.SetPrincipal(c, &auth.Principal{Subject: "integration", Scopes: scopes})
+	}
+}
+
+// testImageDir is the directory the test router stores image content in.
+func testImageDir() string {
+	return filepath.Join(os.TempDir(), "products_rest_api_images")
+}
+
 func setupTest(t *testing.T) *gin.Engine {
 	require.NoError(t, truncateTables())
 	t.Cleanup(func() { _ = truncateTables() })
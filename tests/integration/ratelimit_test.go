@@ -0,0 +1,67 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mariosker/products_rest_api/internal/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgresRateLimitStore(t *testing.T) {
+	require.NoError(t, truncateTables())
+	t.Cleanup(func() { _ = truncateTables() })
+
+	ctx := context.Background()
+	limit := ratelimit.Limit{Requests: 5, Period: time.Minute}
+
+	t.Run("Shared Between Instances", func(t *testing.T) {
+		first := ratelimit.NewPostgresStore(pgxConn)
+		second := ratelimit.NewPostgresStore(pgxConn)
+
+		for i := 0; i < 3; i++ {
+			result, err := first.Take(ctx, "write:client:api-key:1", limit)
+			require.NoError(t, err)
+			assert.True(t, result.Allowed)
+		}
+		result, err := second.Take(ctx, "write:client:api-key:1", limit)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 1, result.Remaining)
+
+		result, err = second.Take(ctx, "write:client:api-key:1", limit)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+		result, err = first.Take(ctx, "write:client:api-key:1", limit)
+		require.NoError(t, err)
+		assert.False(t, result.Allowed)
+		assert.Greater(t, result.RetryAfter, time.Duration(0))
+	})
+
+	t.Run("Peek Does Not Take", func(t *testing.T) {
+		store := ratelimit.NewPostgresStore(pgxConn)
+		result, err := store.Peek(ctx, "auth:ip:192.0.2.9", limit)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+
+		for i := 0; i < limit.Requests; i++ {
+			_, err := store.Take(ctx, "auth:ip:192.0.2.9", limit)
+			require.NoError(t, err)
+		}
+		for i := 0; i < 2; i++ {
+			result, err = store.Peek(ctx, "auth:ip:192.0.2.9", limit)
+			require.NoError(t, err)
+			assert.False(t, result.Allowed)
+		}
+	})
+
+	t.Run("Separate Keys", func(t *testing.T) {
+		store := ratelimit.NewPostgresStore(pgxConn)
+		result, err := store.Take(ctx, "read:ip:192.0.2.1", limit)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, limit.Requests-1, result.Remaining)
+	})
+}
@@ -0,0 +1,86 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelations(t *testing.T) {
+	router := setupTest(t)
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	create := func(name string) int {
+		w := send("POST", "/products", fmt.Sprintf(`{"name":%q,"price":10}`, name))
+		require.Equal(t, http.StatusCreated, w.Code)
+		var created models.CreateProductResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+		return created.ID
+	}
+	relationsOf := func(path string) []models.ProductRelation {
+		w := send("GET", path, "")
+		require.Equal(t, http.StatusOK, w.Code)
+		var relations []models.ProductRelation
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &relations))
+		return relations
+	}
+
+	camera := create("Camera")
+	lens := create("Lens")
+	tripod := create("Tripod")
+	newCamera := create("Camera II")
+	relationsPath := fmt.Sprintf("/products/%d/relations", camera)
+
+	t.Run("Put And Order", func(t *testing.T) {
+		body := fmt.Sprintf(`{"relations":[{"type":"accessory","product_id":%d},{"type":"upsell","product_id":%d},{"type":"accessory","product_id":%d}]}`,
+			tripod, newCamera, lens)
+		require.Equal(t, http.StatusOK, send("PUT", relationsPath, body).Code)
+
+		accessories := relationsOf(relationsPath + "?type=accessory")
+		require.Len(t, accessories, 2)
+		assert.Equal(t, tripod, accessories[0].ProductID)
+		assert.Equal(t, "Tripod", accessories[0].Name)
+		assert.Equal(t, lens, accessories[1].ProductID)
+		assert.Equal(t, 1, accessories[1].Position)
+	})
+
+	t.Run("Directional", func(t *testing.T) {
+		assert.Empty(t, relationsOf(fmt.Sprintf("/products/%d/relations", lens)))
+	})
+
+	t.Run("Expand", func(t *testing.T) {
+		w := send("GET", fmt.Sprintf("/products/%d?expand=relations", camera), "")
+		require.Equal(t, http.StatusOK, w.Code)
+		var product models.Product
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &product))
+		assert.Len(t, product.Relations, 3)
+	})
+
+	t.Run("Invalid Relations", func(t *testing.T) {
+		self := fmt.Sprintf(`{"relations":[{"type":"related","product_id":%d}]}`, camera)
+		assert.Equal(t, http.StatusBadRequest, send("PUT", relationsPath, self).Code)
+		assert.Equal(t, http.StatusBadRequest, send("PUT", relationsPath, `{"relations":[{"type":"related","product_id":9999}]}`).Code)
+		assert.Len(t, relationsOf(relationsPath), 3)
+	})
+
+	t.Run("Deleted Products Are Removed", func(t *testing.T) {
+		require.Equal(t, http.StatusNoContent, send("DELETE", fmt.Sprintf("/products/%d", lens), "").Code)
+		assert.Len(t, relationsOf(relationsPath), 2)
+
+		require.Equal(t, http.StatusNoContent, send("DELETE", fmt.Sprintf("/products/%d", camera), "").Code)
+		assert.Equal(t, http.StatusNotFound, send("GET", relationsPath, "").Code)
+	})
+}
@@ -0,0 +1,137 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/mariosker/products_rest_api/internal/repository"
+	"github.com/mariosker/products_rest_api/internal/workers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func insertTestProductWithStock(name string, price float64, stock int) (int, error) {
+	var id int
+	query := "INSERT INTO products (name, price, stock) VALUES ($1, $2, $3) RETURNING id"
+	err := pgxConn.QueryRow(context.Background(), query, name, price, stock).Scan(&id)
+	return id, err
+}
+
+func createReservation(t *testing.T, router *gin.Engine, body string) (*httptest.ResponseRecorder, models.Reservation) {
+	req, _ := http.NewRequest("POST", "/reservations", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var reservation models.Reservation
+	if w.Code == http.StatusCreated {
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &reservation))
+	}
+	return w, reservation
+}
+
+func getAvailable(t *testing.T, router *gin.Engine, productID int) int {
+	req, _ := http.NewRequest("GET", fmt.Sprintf("/products/%d", productID), nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var product models.Product
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &product))
+	return product.Available
+}
+
+func TestReservations(t *testing.T) {
+	router := setupTest(t)
+
+	shirtID, err := insertTestProductWithStock("Shirt", 20.0, 5)
+	require.NoError(t, err)
+	hatID, err := insertTestProductWithStock("Hat", 10.0, 2)
+	require.NoError(t, err)
+
+	t.Run("Reserve Subtracts Available Stock", func(t *testing.T) {
+		w, reservation := createReservation(t, router, fmt.Sprintf(`{"items":[{"product_id":%d,"quantity":3},{"product_id":%d,"quantity":1}]}`, shirtID, hatID))
+		require.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, models.ReservationStatusActive, reservation.Status)
+
+		assert.Equal(t, 2, getAvailable(t, router, shirtID))
+		assert.Equal(t, 1, getAvailable(t, router, hatID))
+
+		req, _ := http.NewRequest("POST", fmt.Sprintf("/reservations/%d/release", reservation.ID), nil)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, 5, getAvailable(t, router, shirtID))
+	})
+
+	t.Run("Insufficient Stock Reserves Nothing", func(t *testing.T) {
+		w, _ := createReservation(t, router, fmt.Sprintf(`{"items":[{"product_id":%d,"quantity":1},{"product_id":%d,"quantity":3}]}`, shirtID, hatID))
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Equal(t, 5, getAvailable(t, router, shirtID))
+	})
+
+	t.Run("Unknown Product", func(t *testing.T) {
+		w, _ := createReservation(t, router, `{"items":[{"product_id":9999,"quantity":1}]}`)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Commit Deducts Stock", func(t *testing.T) {
+		w, reservation := createReservation(t, router, fmt.Sprintf(`{"items":[{"product_id":%d,"quantity":2}]}`, hatID))
+		require.Equal(t, http.StatusCreated, w.Code)
+
+		req, _ := http.NewRequest("POST", fmt.Sprintf("/reservations/%d/commit", reservation.ID), nil)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var stock int
+		require.NoError(t, pgxConn.QueryRow(context.Background(), "SELECT stock FROM products WHERE id = $1", hatID).Scan(&stock))
+		assert.Equal(t, 0, stock)
+
+		req, _ = http.NewRequest("POST", fmt.Sprintf("/reservations/%d/release", reservation.ID), nil)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("Commit Fails When Stock Was Lowered", func(t *testing.T) {
+		capID, err := insertTestProductWithStock("Cap", 8.0, 3)
+		require.NoError(t, err)
+		w, reservation := createReservation(t, router, fmt.Sprintf(`{"items":[{"product_id":%d,"quantity":3}]}`, capID))
+		require.Equal(t, http.StatusCreated, w.Code)
+		_, err = pgxConn.Exec(context.Background(), "UPDATE products SET stock = 1 WHERE id = $1", capID)
+		require.NoError(t, err)
+
+		req, _ := http.NewRequest("POST", fmt.Sprintf("/reservations/%d/commit", reservation.ID), nil)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusConflict, w.Code)
+
+		var status string
+		require.NoError(t, pgxConn.QueryRow(context.Background(), "SELECT status FROM reservations WHERE id = $1", reservation.ID).Scan(&status))
+		assert.Equal(t, models.ReservationStatusActive, status)
+	})
+
+	t.Run("Sweeper Releases Expired Reservations", func(t *testing.T) {
+		w, reservation := createReservation(t, router, fmt.Sprintf(`{"items":[{"product_id":%d,"quantity":4}],"ttl_seconds":1}`, shirtID))
+		require.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, 1, getAvailable(t, router, shirtID))
+
+		time.Sleep(1100 * time.Millisecond)
+		repo := repository.NewPostgresReservationRepository(pgxConn)
+		workers.NewReservationSweeper(repo, time.Second).Sweep(context.Background())
+
+		stored, err := repo.GetReservationByID(context.Background(), reservation.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.ReservationStatusExpired, stored.Status)
+		assert.Equal(t, 5, getAvailable(t, router, shirtID))
+	})
+}
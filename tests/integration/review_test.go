@@ -0,0 +1,115 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReviews(t *testing.T) {
+	router := setupTest(t)
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	create := func(name string) int {
+		w := send("POST", "/products", fmt.Sprintf(`{"name":%q,"price":10}`, name))
+		require.Equal(t, http.StatusCreated, w.Code)
+		var created models.CreateProductResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+		return created.ID
+	}
+	review := func(productID, rating int) int {
+		w := send("POST", fmt.Sprintf("/products/%d/reviews", productID), fmt.Sprintf(`{"rating":%d,"text":"Fine","author":"Sam"}`, rating))
+		require.Equal(t, http.StatusCreated, w.Code)
+		var created models.Review
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+		return created.ID
+	}
+	moderate := func(productID, reviewID int, status string) {
+		path := fmt.Sprintf("/products/%d/reviews/%d/status", productID, reviewID)
+		require.Equal(t, http.StatusOK, send("PUT", path, fmt.Sprintf(`{"status":%q}`, status)).Code)
+	}
+	getProduct := func(id int) models.Product {
+		w := send("GET", fmt.Sprintf("/products/%d", id), "")
+		require.Equal(t, http.StatusOK, w.Code)
+		var product models.Product
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &product))
+		return product
+	}
+
+	kettle := create("Kettle")
+	toaster := create("Toaster")
+	create("Blender")
+
+	first := review(kettle, 5)
+	second := review(kettle, 2)
+	third := review(toaster, 4)
+
+	t.Run("Pending Reviews Are Not Counted", func(t *testing.T) {
+		product := getProduct(kettle)
+		assert.Nil(t, product.RatingAverage)
+		assert.Zero(t, product.ReviewCount)
+
+		w := send("GET", fmt.Sprintf("/products/%d/reviews", kettle), "")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `[]`, w.Body.String())
+	})
+
+	t.Run("Approval Updates Rating", func(t *testing.T) {
+		moderate(kettle, first, models.ReviewStatusApproved)
+		moderate(kettle, second, models.ReviewStatusApproved)
+		moderate(toaster, third, models.ReviewStatusApproved)
+
+		product := getProduct(kettle)
+		require.NotNil(t, product.RatingAverage)
+		assert.Equal(t, 3.5, *product.RatingAverage)
+		assert.Equal(t, 2, product.ReviewCount)
+	})
+
+	t.Run("Rejection And Deletion Update Rating", func(t *testing.T) {
+		moderate(kettle, second, models.ReviewStatusRejected)
+		assert.Equal(t, 5.0, *getProduct(kettle).RatingAverage)
+
+		moderate(kettle, second, models.ReviewStatusRejected)
+		assert.Equal(t, 1, getProduct(kettle).ReviewCount)
+
+		require.Equal(t, http.StatusNoContent, send("DELETE", fmt.Sprintf("/products/%d/reviews/%d", kettle, first), "").Code)
+		product := getProduct(kettle)
+		assert.Nil(t, product.RatingAverage)
+		assert.Zero(t, product.ReviewCount)
+
+		moderate(kettle, second, models.ReviewStatusApproved)
+		assert.Equal(t, 2.0, *getProduct(kettle).RatingAverage)
+	})
+
+	t.Run("Sort By Rating", func(t *testing.T) {
+		w := send("GET", "/products?sort=-rating", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		var products []models.Product
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &products))
+		require.Len(t, products, 3)
+		assert.Equal(t, []string{"Toaster", "Kettle", "Blender"}, []string{products[0].Name, products[1].Name, products[2].Name})
+	})
+
+	t.Run("Moderation Queue", func(t *testing.T) {
+		review(toaster, 1)
+		w := send("GET", fmt.Sprintf("/products/%d/reviews?status=pending", toaster), "")
+		require.Equal(t, http.StatusOK, w.Code)
+		var reviews []models.Review
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &reviews))
+		require.Len(t, reviews, 1)
+		assert.Equal(t, 1, reviews[0].Rating)
+	})
+}
@@ -0,0 +1,116 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mariosker/products_rest_api/internal/auth"
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/mariosker/products_rest_api/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProductLifecycle(t *testing.T) {
+	anonymous := setupTest(t)
+	router := setupRouter(withPrincipal(auth.ScopeProductsRead, auth.ScopeProductsWrite, auth.ScopePriceWrite))
+
+	sendAs := func(router http.Handler, method, path, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		return sendAs(router, method, path, body)
+	}
+	create := func(body string) models.Product {
+		w := send("POST", "/products", body)
+		require.Equal(t, http.StatusCreated, w.Code)
+		var product models.Product
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &product))
+		return product
+	}
+
+	draft := create(`{"name":"Draft Lamp","price":30,"status":"draft"}`)
+	assert.Equal(t, models.ProductStatusDraft, draft.Status)
+	live := create(`{"name":"Live Lamp","price":40}`)
+	assert.Equal(t, models.ProductStatusPublished, live.Status)
+
+	t.Run("Listing Defaults To Published", func(t *testing.T) {
+		w := send("GET", "/products", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "Live Lamp")
+		assert.NotContains(t, w.Body.String(), "Draft Lamp")
+
+		w = send("GET", "/products?status=draft", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "Draft Lamp")
+		assert.NotContains(t, w.Body.String(), "Live Lamp")
+	})
+
+	t.Run("Anonymous Clients Only See Published", func(t *testing.T) {
+		assert.Equal(t, http.StatusForbidden, sendAs(anonymous, "GET", "/products?status=all", "").Code)
+		assert.Equal(t, http.StatusNotFound, sendAs(anonymous, "GET", fmt.Sprintf("/products/%d", draft.ID), "").Code)
+	})
+
+	t.Run("Anonymous Clients Cannot Read Draft Product Data", func(t *testing.T) {
+		path := fmt.Sprintf("/products/%d", draft.ID)
+		require.Equal(t, http.StatusCreated, send("POST", path+"/variants", `{"sku":"DRAFT-LAMP-RED","options":{"color":"red"}}`).Code)
+		require.Equal(t, http.StatusOK, send("GET", path+"/variants", "").Code)
+
+		for _, sub := range []string{"/variants", "/images", "/translations", "/price-schedules", "/bundle", "/reviews", "/relations"} {
+			assert.Equal(t, http.StatusNotFound, sendAs(anonymous, "GET", path+sub, "").Code, sub)
+		}
+		assert.Equal(t, http.StatusOK, sendAs(anonymous, "GET", fmt.Sprintf("/products/%d/variants", live.ID), "").Code)
+	})
+
+	t.Run("Draft Cannot Be Archived", func(t *testing.T) {
+		w := send("POST", fmt.Sprintf("/products/%d/archive", draft.ID), "")
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("Archive And Unarchive", func(t *testing.T) {
+		w := send("POST", fmt.Sprintf("/products/%d/archive", live.ID), "")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"status":"archived"`)
+
+		w = send("POST", fmt.Sprintf("/products/%d/unarchive", live.ID), "")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"status":"draft"`)
+
+		w = send("POST", fmt.Sprintf("/products/%d/publish", live.ID), "")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"status":"published"`)
+	})
+
+	t.Run("Scheduled Publish", func(t *testing.T) {
+		publishAt := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+		w := send("POST", fmt.Sprintf("/products/%d/publish", draft.ID), fmt.Sprintf(`{"publish_at":%q}`, publishAt))
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"status":"draft"`)
+
+		repo := repository.NewPostgresProductRepository(pgxConn)
+		published, err := repo.PublishScheduledProducts(context.Background())
+		require.NoError(t, err)
+		assert.Zero(t, published)
+
+		_, err = pgxConn.Exec(context.Background(), "UPDATE products SET publish_at = now() - interval '1 minute' WHERE id = $1", draft.ID)
+		require.NoError(t, err)
+		published, err = repo.PublishScheduledProducts(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), published)
+
+		w = send("GET", fmt.Sprintf("/products/%d", draft.ID), "")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"status":"published"`)
+		assert.NotContains(t, w.Body.String(), `"publish_at"`)
+	})
+}
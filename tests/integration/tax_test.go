@@ -0,0 +1,81 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaxes(t *testing.T) {
+	router := setupTest(t)
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send("POST", "/products", `{"name":"Novel","price":10,"tax_class":"books"}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	var created models.CreateProductResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	productPath := fmt.Sprintf("/products/%d", created.ID)
+
+	require.Equal(t, http.StatusOK, send("PUT", "/tax-rates/DE/standard", `{"rate":19,"prices_include_tax":true}`).Code)
+	require.Equal(t, http.StatusOK, send("PUT", "/tax-rates/DE/books", `{"rate":7,"prices_include_tax":true}`).Code)
+	require.Equal(t, http.StatusOK, send("PUT", "/tax-rates/US/books", `{"rate":0}`).Code)
+	require.Equal(t, http.StatusOK, send("PUT", "/tax-rates/US-CA/books", `{"rate":7.25}`).Code)
+
+	productTax := func(path string) *models.ProductTax {
+		w := send("GET", path, "")
+		require.Equal(t, http.StatusOK, w.Code)
+		var product models.Product
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &product))
+		return product.Tax
+	}
+
+	t.Run("Untaxed Without Region", func(t *testing.T) {
+		assert.Nil(t, productTax(productPath))
+	})
+
+	t.Run("Gross In The EU", func(t *testing.T) {
+		assert.Equal(t, &models.ProductTax{Region: "DE", TaxClass: "books", Rate: 7, Net: 10, Tax: 0.7, Gross: 10.7, DisplayPrice: 10.7},
+			productTax(productPath+"?region=DE"))
+	})
+
+	t.Run("Region Rate Overrides Country Rate", func(t *testing.T) {
+		assert.Equal(t, &models.ProductTax{Region: "US-CA", TaxClass: "books", Rate: 7.25, Net: 10, Tax: 0.73, Gross: 10.73, DisplayPrice: 10},
+			productTax(productPath+"?region=US-CA"))
+		assert.Equal(t, 0.0, productTax(productPath+"?region=US-NY").Rate)
+	})
+
+	t.Run("List", func(t *testing.T) {
+		w := send("GET", "/products?region=DE", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"display_price":10.7`)
+	})
+
+	t.Run("Unknown Region", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, send("GET", productPath+"?region=JP", "").Code)
+	})
+
+	t.Run("List And Delete Rates", func(t *testing.T) {
+		w := send("GET", "/tax-rates", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		var rates []models.TaxRate
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &rates))
+		assert.Len(t, rates, 4)
+
+		assert.Equal(t, http.StatusNoContent, send("DELETE", "/tax-rates/US-CA/books", "").Code)
+		assert.Equal(t, http.StatusNotFound, send("DELETE", "/tax-rates/US-CA/books", "").Code)
+	})
+}
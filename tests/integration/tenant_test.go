@@ -0,0 +1,231 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mariosker/products_rest_api/internal/auth"
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/mariosker/products_rest_api/internal/repository"
+	"github.com/mariosker/products_rest_api/internal/tenant"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTenantIsolation(t *testing.T) {
+	require.NoError(t, truncateTables())
+	t.Cleanup(func() { _ = truncateTables() })
+
+	productRepo := repository.NewPostgresProductRepository(pgxConn)
+	router := setupRouter(auth.ResolveTenant("default"))
+
+	send := func(tenantID, method, path, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(tenant.Header, tenantID)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	create := func(tenantID, body string) int {
+		w := send(tenantID, "POST", "/products", body)
+		require.Equal(t, http.StatusCreated, w.Code)
+		var created models.CreateProductResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+		return created.ID
+	}
+	list := func(tenantID string) []models.Product {
+		w := send(tenantID, "GET", "/products", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		var products []models.Product
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &products))
+		return products
+	}
+
+	acmeLamp := create("acme", `{"name":"Lamp","price":30,"sku":"LAMP-1","stock":5}`)
+	globexLamp := create("globex", `{"name":"Lamp","price":45,"sku":"LAMP-1","stock":5}`)
+
+	t.Run("Same Keys In Different Tenants", func(t *testing.T) {
+		var acmeSlug, globexSlug string
+		require.NoError(t, pgxConn.QueryRow(context.Background(), "SELECT slug FROM products WHERE id = $1", acmeLamp).Scan(&acmeSlug))
+		require.NoError(t, pgxConn.QueryRow(context.Background(), "SELECT slug FROM products WHERE id = $1", globexLamp).Scan(&globexSlug))
+		assert.Equal(t, "lamp", acmeSlug)
+		assert.Equal(t, "lamp", globexSlug)
+
+		w := send("acme", "GET", "/products/by-sku/LAMP-1", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), fmt.Sprintf(`"id":%d`, acmeLamp))
+	})
+
+	t.Run("Cannot Read Other Tenant", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, send("globex", "GET", fmt.Sprintf("/products/%d", acmeLamp), "").Code)
+
+		products := list("globex")
+		require.Len(t, products, 1)
+		assert.Equal(t, globexLamp, products[0].ID)
+		assert.Empty(t, list("initech"))
+	})
+
+	t.Run("Cannot Modify Other Tenant", func(t *testing.T) {
+		path := fmt.Sprintf("/products/%d", acmeLamp)
+		assert.Equal(t, http.StatusNotFound, send("globex", "PUT", path, `{"name":"Stolen Lamp","price":1}`).Code)
+		// Deleting is idempotent, so another tenant's product is reported deleted like a missing one.
+		assert.Equal(t, http.StatusNoContent, send("globex", "DELETE", path, "").Code)
+		assert.Equal(t, http.StatusNotFound, send("globex", "POST", path+"/archive", "").Code)
+
+		w := send("acme", "GET", path, "")
+		require.Equal(t, http.StatusOK, w.Code)
+		var product models.Product
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &product))
+		assert.Equal(t, "Lamp", product.Name)
+		assert.Equal(t, 30.0, product.Price)
+		assert.Equal(t, models.ProductStatusPublished, product.Status)
+	})
+
+	t.Run("Cannot Reach Other Tenant's Product Data", func(t *testing.T) {
+		path := fmt.Sprintf("/products/%d", acmeLamp)
+		require.Equal(t, http.StatusCreated, send("acme", "POST", path+"/variants", `{"sku":"LAMP-1-RED","options":{"color":"red"}}`).Code)
+		require.Equal(t, http.StatusOK, send("acme", "PUT", path+"/translations/de", `{"name":"Lampe"}`).Code)
+
+		tests := []struct {
+			name, method, path, body string
+			expected                 int
+		}{
+			{"List Variants", "GET", path + "/variants", "", http.StatusNotFound},
+			{"Create Variant", "POST", path + "/variants", `{"sku":"LAMP-1-BLUE","options":{"color":"blue"}}`, http.StatusNotFound},
+			{"List Translations", "GET", path + "/translations", "", http.StatusNotFound},
+			{"Put Translation", "PUT", path + "/translations/fr", `{"name":"Lampe"}`, http.StatusNotFound},
+			{"Delete Translation", "DELETE", path + "/translations/de", "", http.StatusNotFound},
+			{"List Images", "GET", path + "/images", "", http.StatusNotFound},
+			{"List Price Schedules", "GET", path + "/price-schedules", "", http.StatusNotFound},
+			{"Create Price Schedule", "POST", path + "/price-schedules", `{"price":1,"starts_at":"2030-01-01T00:00:00Z"}`, http.StatusNotFound},
+			{"List Reviews", "GET", path + "/reviews", "", http.StatusNotFound},
+			{"Create Review", "POST", path + "/reviews", `{"rating":1,"author":"Mallory"}`, http.StatusNotFound},
+			{"List Relations", "GET", path + "/relations", "", http.StatusNotFound},
+			{"Relate To Other Tenant", "PUT", fmt.Sprintf("/products/%d/relations", globexLamp),
+				fmt.Sprintf(`{"relations":[{"type":"related","product_id":%d}]}`, acmeLamp), http.StatusBadRequest},
+			{"Bundle Other Tenant", "PUT", fmt.Sprintf("/products/%d/bundle", globexLamp),
+				fmt.Sprintf(`{"pricing":"fixed","components":[{"product_id":%d,"quantity":1}]}`, acmeLamp), http.StatusBadRequest},
+			{"Reserve Other Tenant", "POST", "/reservations", fmt.Sprintf(`{"items":[{"product_id":%d,"quantity":1}]}`, acmeLamp), http.StatusNotFound},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				assert.Equal(t, tt.expected, send("globex", tt.method, tt.path, tt.body).Code)
+			})
+		}
+
+		var variants, translations int
+		require.NoError(t, pgxConn.QueryRow(context.Background(), "SELECT count(*) FROM product_variants WHERE product_id = $1", acmeLamp).Scan(&variants))
+		require.NoError(t, pgxConn.QueryRow(context.Background(), "SELECT count(*) FROM product_translations WHERE product_id = $1", acmeLamp).Scan(&translations))
+		assert.Equal(t, 1, variants)
+		assert.Equal(t, 1, translations)
+	})
+
+	t.Run("Same Variant SKU In Different Tenants", func(t *testing.T) {
+		// acme already has a LAMP-1-RED variant.
+		path := fmt.Sprintf("/products/%d/variants", globexLamp)
+		assert.Equal(t, http.StatusCreated, send("globex", "POST", path, `{"sku":"LAMP-1-RED","options":{"color":"red"}}`).Code)
+		assert.Equal(t, http.StatusConflict, send("globex", "POST", path, `{"sku":"LAMP-1-RED","options":{"color":"blue"}}`).Code)
+	})
+
+	t.Run("Cannot Reach Other Tenant's Reservations", func(t *testing.T) {
+		w := send("acme", "POST", "/reservations", fmt.Sprintf(`{"items":[{"product_id":%d,"quantity":2}]}`, acmeLamp))
+		require.Equal(t, http.StatusCreated, w.Code)
+		var reservation models.Reservation
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &reservation))
+		path := fmt.Sprintf("/reservations/%d", reservation.ID)
+
+		assert.Equal(t, http.StatusNotFound, send("globex", "GET", path, "").Code)
+		assert.Equal(t, http.StatusNotFound, send("globex", "POST", path+"/commit", "").Code)
+		assert.Equal(t, http.StatusNotFound, send("globex", "POST", path+"/release", "").Code)
+
+		require.Equal(t, http.StatusOK, send("acme", "POST", path+"/release", "").Code)
+	})
+
+	t.Run("Discounts Apply To Their Own Tenant", func(t *testing.T) {
+		w := send("acme", "POST", "/discounts", `{"name":"Everything 50% off","type":"percentage","value":50}`)
+		require.Equal(t, http.StatusCreated, w.Code)
+		var discount models.Discount
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &discount))
+
+		w = send("globex", "GET", "/discounts", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `[]`, w.Body.String())
+		assert.Equal(t, http.StatusNotFound, send("globex", "DELETE", fmt.Sprintf("/discounts/%d", discount.ID), "").Code)
+
+		quote := func(tenantID string, productID int) models.Quote {
+			w := send(tenantID, "POST", "/pricing/quote", fmt.Sprintf(`{"items":[{"product_id":%d,"quantity":1}]}`, productID))
+			require.Equal(t, http.StatusOK, w.Code)
+			var quote models.Quote
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &quote))
+			return quote
+		}
+		assert.Equal(t, 15.0, quote("acme", acmeLamp).Total)
+		assert.Equal(t, 45.0, quote("globex", globexLamp).Total)
+	})
+
+	t.Run("Enforced By Row Level Security", func(t *testing.T) {
+		// Even a query that does not filter by tenant only sees the rows of the transaction's tenant.
+		tx, err := pgxConn.Begin(context.Background())
+		require.NoError(t, err)
+		defer tx.Rollback(context.Background())
+		_, err = tx.Exec(context.Background(), "SELECT set_config('app.tenant_id', 'globex', true)")
+		require.NoError(t, err)
+		_, err = tx.Exec(context.Background(), "SET LOCAL ROLE products_tenant")
+		require.NoError(t, err)
+
+		var count int
+		require.NoError(t, tx.QueryRow(context.Background(), "SELECT count(*) FROM products").Scan(&count))
+		assert.Equal(t, 1, count)
+
+		result, err := tx.Exec(context.Background(), "UPDATE products SET price = 1 WHERE id = $1", acmeLamp)
+		require.NoError(t, err)
+		assert.Zero(t, result.RowsAffected())
+
+		_, err = tx.Exec(context.Background(), "INSERT INTO products (name, price, slug, tenant_id) VALUES ('Planted', 1, 'planted', 'acme')")
+		assert.Error(t, err)
+	})
+
+	t.Run("Repository Without Tenant Sees Every Tenant", func(t *testing.T) {
+		products, err := productRepo.GetProducts(context.Background(), 10, 0, models.ProductFilter{})
+		require.NoError(t, err)
+		assert.Len(t, products, 2)
+
+		scoped, err := productRepo.GetProducts(tenant.WithID(context.Background(), "acme"), 10, 0, models.ProductFilter{})
+		require.NoError(t, err)
+		require.Len(t, scoped, 1)
+		assert.Equal(t, acmeLamp, scoped[0].ID)
+	})
+
+	t.Run("Attribute Definitions And Tax Rates Belong To Their Tenant", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, send("acme", "PUT", "/categories/lamps/attributes/wattage", `{"type":"number","required":true}`).Code)
+		require.Equal(t, http.StatusOK, send("acme", "PUT", "/tax-rates/DE/standard", `{"rate":19,"prices_include_tax":true}`).Code)
+		require.Equal(t, http.StatusOK, send("globex", "PUT", "/tax-rates/DE/standard", `{"rate":7}`).Code)
+
+		w := send("globex", "GET", "/categories/lamps/attributes", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `[]`, w.Body.String())
+		assert.Equal(t, http.StatusNotFound, send("globex", "DELETE", "/categories/lamps/attributes/wattage", "").Code)
+		// acme's required attribute does not apply to globex's products.
+		assert.Equal(t, http.StatusCreated, send("globex", "POST", "/products", `{"name":"Desk Lamp","price":20,"category":"lamps"}`).Code)
+		assert.Equal(t, http.StatusBadRequest, send("acme", "POST", "/products", `{"name":"Desk Lamp","price":20,"category":"lamps"}`).Code)
+
+		w = send("globex", "GET", "/tax-rates", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		var rates []models.TaxRate
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &rates))
+		require.Len(t, rates, 1)
+		assert.Equal(t, 7.0, rates[0].Rate)
+
+		require.Equal(t, http.StatusNoContent, send("globex", "DELETE", "/tax-rates/DE/standard", "").Code)
+		assert.Equal(t, http.StatusNotFound, send("globex", "DELETE", "/tax-rates/DE/standard", "").Code)
+		w = send("acme", "GET", fmt.Sprintf("/products/%d?region=DE", acmeLamp), "")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"rate":19`)
+	})
+}
@@ -0,0 +1,81 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProductTranslations(t *testing.T) {
+	router := setupTest(t)
+
+	productID, err := insertTestProduct("Mug", 5.0)
+	require.NoError(t, err)
+
+	send := func(method, path, body string, header http.Header) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		for name, values := range header {
+			req.Header[name] = values
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	productPath := fmt.Sprintf("/products/%d", productID)
+
+	require.Equal(t, http.StatusOK, send("PUT", productPath+"/translations/de", `{"name":"Becher","description":"Ein Keramikbecher"}`, nil).Code)
+	require.Equal(t, http.StatusOK, send("PUT", productPath+"/translations/fr", `{"name":"Tasse"}`, nil).Code)
+
+	t.Run("List", func(t *testing.T) {
+		w := send("GET", productPath+"/translations", "", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var translations []models.ProductTranslation
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &translations))
+		assert.Len(t, translations, 2)
+	})
+
+	t.Run("Accept-Language Picks Best Translation", func(t *testing.T) {
+		w := send("GET", productPath, "", http.Header{"Accept-Language": {"it, fr;q=0.9, de;q=0.8"}})
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"name":"Tasse"`)
+		assert.Equal(t, "fr", w.Header().Get("Content-Language"))
+	})
+
+	t.Run("Regional Locale Falls Back To Language", func(t *testing.T) {
+		w := send("GET", productPath+"?locale=de-CH", "", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"name":"Becher","description":"Ein Keramikbecher"`)
+	})
+
+	t.Run("Falls Back To Default Locale", func(t *testing.T) {
+		w := send("GET", productPath+"?locale=ja", "", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"name":"Mug"`)
+		assert.Contains(t, w.Body.String(), `"locale":"en"`)
+	})
+
+	t.Run("List Is Localised", func(t *testing.T) {
+		w := send("GET", "/products?locale=de", "", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"name":"Becher"`)
+	})
+
+	t.Run("Unknown Product", func(t *testing.T) {
+		w := send("PUT", "/products/9999/translations/de", `{"name":"Becher"}`, nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		assert.Equal(t, http.StatusNoContent, send("DELETE", productPath+"/translations/fr", "", nil).Code)
+		assert.Equal(t, http.StatusNotFound, send("DELETE", productPath+"/translations/fr", "", nil).Code)
+	})
+}
@@ -0,0 +1,88 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mariosker/products_rest_api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProductVariants(t *testing.T) {
+	router := setupTest(t)
+
+	productID, err := insertTestProduct("T-Shirt", 20.0)
+	require.NoError(t, err)
+
+	postVariant := func(productID int, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("POST", fmt.Sprintf("/products/%d/variants", productID), strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Create Inherits Product Price", func(t *testing.T) {
+		w := postVariant(productID, `{"sku":"TS-M-RED","options":{"size":"M","colour":"red"},"stock":3}`)
+		require.Equal(t, http.StatusCreated, w.Code)
+
+		var variant models.ProductVariant
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &variant))
+		assert.Nil(t, variant.Price)
+		assert.Equal(t, 20.0, variant.EffectivePrice)
+	})
+
+	t.Run("Create With Price Override", func(t *testing.T) {
+		w := postVariant(productID, `{"sku":"TS-XL-RED","options":{"size":"XL","colour":"red"},"price":24.0}`)
+		require.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), `"effective_price":24`)
+	})
+
+	t.Run("Duplicate SKU", func(t *testing.T) {
+		w := postVariant(productID, `{"sku":"TS-M-RED","options":{"size":"S","colour":"red"}}`)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("Duplicate Options", func(t *testing.T) {
+		w := postVariant(productID, `{"sku":"TS-M-RED-2","options":{"size":"M","colour":"red"}}`)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("Mismatched Option Axes", func(t *testing.T) {
+		w := postVariant(productID, `{"sku":"TS-LONG","options":{"sleeve":"long"}}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Unknown Product", func(t *testing.T) {
+		w := postVariant(9999, `{"sku":"NOPE","options":{"size":"M"}}`)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Expand Variants", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", fmt.Sprintf("/products/%d?expand=variants", productID), nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var product models.Product
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &product))
+		assert.Len(t, product.Variants, 2)
+	})
+
+	t.Run("Deleting Product Removes Variants", func(t *testing.T) {
+		req, _ := http.NewRequest("DELETE", fmt.Sprintf("/products/%d", productID), nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusNoContent, w.Code)
+
+		req, _ = http.NewRequest("GET", fmt.Sprintf("/products/%d/variants", productID), nil)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}